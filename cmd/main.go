@@ -12,6 +12,7 @@ import (
 	"github.com/lucas-moura1/gobrax-challenge/config"
 	"github.com/lucas-moura1/gobrax-challenge/entity"
 	"github.com/lucas-moura1/gobrax-challenge/handler"
+	"github.com/lucas-moura1/gobrax-challenge/notifier"
 	"github.com/lucas-moura1/gobrax-challenge/repository"
 	"github.com/lucas-moura1/gobrax-challenge/usecase"
 	"github.com/spf13/viper"
@@ -20,6 +21,9 @@ import (
 
 func main() {
 	viper.AutomaticEnv()
+	viper.SetDefault("LICENSE_SCAN_INTERVAL", "24h")
+	viper.SetDefault("LICENSE_EXPIRING_WITHIN", "720h")
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
 
 	logger, _ := zap.NewDevelopment()
 	defer logger.Sync()
@@ -29,31 +33,125 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	db.AutoMigrate(&entity.Driver{}, &entity.Vehicle{})
+	if err := repository.RegisterErrorTranslation(db); err != nil {
+		panic(err)
+	}
+	if err := repository.Migrate(log, db); err != nil {
+		panic(err)
+	}
 
 	driverRepository := repository.NewDriverRepository(log, db)
-	driverUsecase := usecase.NewDriverUsecase(log, driverRepository)
+	vehicleRepository := repository.NewVehicleRepository(log, db)
+	assignmentRepository := repository.NewAssignmentRepository(log, db)
+	unitOfWork := repository.NewUnitOfWork(log, db)
+	if err := vehicleRepository.BackfillPlates(); err != nil {
+		panic(err)
+	}
+	// vehicles saved before classes were recorded get the configured class,
+	// or stay unclassified until one is informed when none is configured
+	if class := viper.GetString("VEHICLE_DEFAULT_CLASS"); class != "" {
+		if !entity.IsVehicleClass(class) {
+			panic(fmt.Sprintf("invalid VEHICLE_DEFAULT_CLASS %q", class))
+		}
+		if err := vehicleRepository.BackfillClasses(class); err != nil {
+			panic(err)
+		}
+	}
+	if err := assignmentRepository.Backfill(); err != nil {
+		panic(err)
+	}
+
+	idempotencyRepository := repository.NewIdempotencyRepository(log, db)
+	idempotencyHandler := handler.IdempotencyHandler{
+		IdempotencyUsecase: usecase.NewIdempotencyUsecase(idempotencyRepository, viper.GetDuration("IDEMPOTENCY_KEY_TTL")),
+	}
+
+	// purges are only allowed with the admin token configured on the server
+	adminHandler := handler.AdminHandler{Token: viper.GetString("ADMIN_TOKEN")}
+
+	driverUsecase := usecase.NewDriverUsecase(log, driverRepository, vehicleRepository, unitOfWork)
 	driverHandler := handler.DriverHandler{
 		DriverUsecase: driverUsecase,
 	}
 
-	http.HandleFunc("GET /drivers", driverHandler.GetAll)
-	http.HandleFunc("GET /drivers/{id}", driverHandler.GetById)
-	http.HandleFunc("POST /drivers", driverHandler.Create)
-	http.HandleFunc("POST /drivers/{id}/vehicle", driverHandler.AddVehicle)
-	http.HandleFunc("PATCH /drivers/{id}", driverHandler.Update)
-	http.HandleFunc("DELETE /drivers/{id}", driverHandler.Delete)
-
-	vehicleRepository := repository.NewVehicleRepository(log, db)
-	vehicleUsecase := usecase.NewVehicleUsecase(vehicleRepository)
+	vehicleUsecase := usecase.NewVehicleUsecase(vehicleRepository, driverRepository, unitOfWork)
 	vehicleHandler := handler.VehicleHandler{
 		VehicleUsecase: vehicleUsecase,
 	}
 
+	// paths with a driver or vehicle id also accept its external id as source:id
+	driverRef := driverHandler.ExternalId
+	vehicleRef := vehicleHandler.ExternalId
+
+	http.HandleFunc("GET /drivers", driverHandler.GetAll)
+	http.HandleFunc("GET /drivers/export", driverHandler.Export)
+	http.HandleFunc("GET /drivers/expiring", driverHandler.GetExpiring)
+	http.HandleFunc("GET /drivers/by-external/{source}/{id}", driverHandler.GetByExternalId)
+	http.HandleFunc("GET /drivers/{id}", driverRef("id", driverHandler.GetById))
+	http.HandleFunc("POST /drivers", idempotencyHandler.Middleware(driverHandler.Create))
+	http.HandleFunc("POST /drivers/import", driverHandler.Import)
+	http.HandleFunc("POST /drivers/{id}/vehicle", driverRef("id", idempotencyHandler.Middleware(driverHandler.AddVehicle)))
+	http.HandleFunc("PUT /drivers/{id}/vehicles/{vehicleId}", driverRef("id", vehicleRef("vehicleId", driverHandler.AttachVehicle)))
+	http.HandleFunc("DELETE /drivers/{id}/vehicles/{vehicleId}", driverRef("id", vehicleRef("vehicleId", driverHandler.DetachVehicle)))
+	http.HandleFunc("POST /drivers/{id}/vehicles/{vehicleId}/transfer", driverRef("id", vehicleRef("vehicleId", driverHandler.TransferVehicle)))
+	http.HandleFunc("PATCH /drivers/{id}", driverRef("id", driverHandler.Update))
+	http.HandleFunc("PUT /drivers/{id}", driverHandler.Replace)
+	http.HandleFunc("DELETE /drivers/{id}", driverRef("id", driverHandler.Delete))
+	// deleted drivers are only found by their id
+	http.HandleFunc("POST /drivers/{id}/restore", driverHandler.Restore)
+	http.HandleFunc("POST /drivers/{id}/purge", adminHandler.Middleware(driverHandler.Purge))
+
 	http.HandleFunc("GET /vehicles", vehicleHandler.GetAll)
-	http.HandleFunc("GET /vehicles/{id}", vehicleHandler.GetById)
-	http.HandleFunc("PATCH /vehicles/{id}", vehicleHandler.Update)
-	http.HandleFunc("DELETE /vehicles/{id}", vehicleHandler.Delete)
+	http.HandleFunc("GET /vehicles/export", vehicleHandler.Export)
+	http.HandleFunc("GET /vehicles/by-external/{source}/{id}", vehicleHandler.GetByExternalId)
+	http.HandleFunc("GET /vehicles/{id}", vehicleRef("id", vehicleHandler.GetById))
+	http.HandleFunc("POST /vehicles", idempotencyHandler.Middleware(vehicleHandler.Create))
+	http.HandleFunc("POST /vehicles/import", vehicleHandler.Import)
+	http.HandleFunc("PATCH /vehicles/{id}", vehicleRef("id", vehicleHandler.Update))
+	http.HandleFunc("PUT /vehicles/{id}", vehicleHandler.Replace)
+	http.HandleFunc("DELETE /vehicles/{id}", vehicleRef("id", vehicleHandler.Delete))
+	http.HandleFunc("POST /vehicles/{id}/restore", vehicleHandler.Restore)
+	http.HandleFunc("POST /vehicles/{id}/purge", adminHandler.Middleware(vehicleHandler.Purge))
+
+	assignmentUsecase := usecase.NewAssignmentUsecase(assignmentRepository, vehicleRepository, driverRepository, unitOfWork)
+	assignmentHandler := handler.AssignmentHandler{
+		AssignmentUsecase: assignmentUsecase,
+	}
+
+	http.HandleFunc("GET /assignments", assignmentHandler.GetByPlateAt)
+	http.HandleFunc("GET /drivers/{id}/assignments", driverRef("id", assignmentHandler.GetByDriver))
+	http.HandleFunc("GET /vehicles/{id}/assignments", vehicleRef("id", assignmentHandler.GetByVehicle))
+	http.HandleFunc("POST /vehicles/{id}/assignments", vehicleRef("id", assignmentHandler.Assign))
+	http.HandleFunc("DELETE /vehicles/{id}/assignments", vehicleRef("id", assignmentHandler.Unassign))
+	http.HandleFunc("POST /vehicles/{id}/transfer", vehicleRef("id", assignmentHandler.Transfer))
+
+	auditUsecase := usecase.NewAuditUsecase(repository.NewAuditRepository(log, db), driverRepository, vehicleRepository)
+	auditHandler := handler.AuditHandler{
+		AuditUsecase: auditUsecase,
+	}
+
+	http.HandleFunc("GET /drivers/{id}/history", driverRef("id", auditHandler.GetByDriver))
+	http.HandleFunc("GET /vehicles/{id}/history", vehicleRef("id", auditHandler.GetByVehicle))
+
+	statusUsecase := usecase.NewStatusUsecase(repository.NewStatusRepository(log, db), driverRepository, vehicleRepository, unitOfWork)
+	statusHandler := handler.StatusHandler{
+		StatusUsecase: statusUsecase,
+	}
+
+	http.HandleFunc("GET /drivers/{id}/status-history", driverRef("id", statusHandler.GetByDriver))
+	http.HandleFunc("POST /drivers/{id}/status", driverRef("id", statusHandler.ChangeDriverStatus))
+	http.HandleFunc("GET /vehicles/{id}/status-history", vehicleRef("id", statusHandler.GetByVehicle))
+	http.HandleFunc("POST /vehicles/{id}/status", vehicleRef("id", statusHandler.ChangeVehicleStatus))
+
+	scanCtx, stopScan := context.WithCancel(context.Background())
+	licenseScanner := usecase.NewLicenseScanner(
+		log,
+		driverRepository,
+		notifier.NewLogNotifier(log),
+		viper.GetDuration("LICENSE_EXPIRING_WITHIN"),
+		viper.GetDuration("LICENSE_SCAN_INTERVAL"),
+	)
+	go licenseScanner.Run(scanCtx)
 
 	server := &http.Server{Addr: fmt.Sprintf(":%s", viper.GetString("PORT"))}
 
@@ -67,6 +165,7 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT, os.Interrupt)
 	<-stop
+	stopScan()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,106 @@
+// Package domainerr classifies the errors of the domain by kind, so the
+// transport layer can answer them consistently without knowing each error.
+package domainerr
+
+import (
+	"errors"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// Kind is the class of a domain error.
+type Kind int
+
+const (
+	// Internal is the kind of unexpected errors, e.g. a failed query.
+	Internal Kind = iota
+	// Validation is the kind of errors of an invalid input.
+	Validation
+	// NotFound is the kind of errors of a missing record.
+	NotFound
+	// Conflict is the kind of errors of a change that conflicts with the
+	// current state, e.g. a duplicated plate.
+	Conflict
+	// Precondition is the kind of errors of a change based on an outdated
+	// version of a record.
+	Precondition
+	// Unprocessable is the kind of errors of a change that a business rule
+	// does not allow, e.g. an expired license.
+	Unprocessable
+	// Unavailable is the kind of errors of a dependency that can not be
+	// reached at the moment, e.g. the database.
+	Unavailable
+	// Unsupported is the kind of errors of an input in a format that is
+	// not supported, e.g. an unknown patch media type.
+	Unsupported
+	// Forbidden is the kind of errors of an action the actor is not allowed
+	// to perform, e.g. a purge requested by someone who is not an admin.
+	Forbidden
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Validation:
+		return "validation"
+	case NotFound:
+		return "not_found"
+	case Conflict:
+		return "conflict"
+	case Precondition:
+		return "precondition"
+	case Unprocessable:
+		return "unprocessable"
+	case Unavailable:
+		return "unavailable"
+	case Unsupported:
+		return "unsupported"
+	case Forbidden:
+		return "forbidden"
+	}
+	return "internal"
+}
+
+// Error is a domain error described by an i18n message key, formatted
+// with Args.
+type Error struct {
+	Kind Kind
+	Key  string
+	Args []interface{}
+	Err  error
+}
+
+// New creates an error of kind with the message of key.
+func New(kind Kind, key string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Key: key, Args: args}
+}
+
+// Wrap creates an error of kind with the message of key caused by err.
+func Wrap(kind Kind, key string, err error) *Error {
+	return &Error{Kind: kind, Key: key, Err: err}
+}
+
+func (e *Error) Error() string {
+	message := i18n.Message(i18n.Default, e.Key, e.Args...)
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) ErrorKind() Kind {
+	return e.Kind
+}
+
+// KindOf returns the kind of err, or Internal when neither err nor the
+// errors it wraps have one.
+func KindOf(err error) Kind {
+	var kinded interface{ ErrorKind() Kind }
+	if errors.As(err, &kinded) {
+		return kinded.ErrorKind()
+	}
+	return Internal
+}
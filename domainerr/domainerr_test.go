@@ -0,0 +1,51 @@
+package domainerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	errNotFound := New(NotFound, i18n.DriverNotFound)
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{
+			name: "Should return the kind of a domain error",
+			err:  errNotFound,
+			want: NotFound,
+		},
+		{
+			name: "Should return the kind of a wrapped domain error",
+			err:  fmt.Errorf("getting driver: %w", errNotFound),
+			want: NotFound,
+		},
+		{
+			name: "Should return internal for other errors",
+			err:  errors.New("some error occurred"),
+			want: Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, KindOf(tt.err))
+		})
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := Wrap(Unavailable, i18n.ServiceUnavailable, cause)
+
+	assert.Equal(t, "service is unavailable, try again later: connection refused", err.Error())
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "driver not found", New(NotFound, i18n.DriverNotFound).Error())
+}
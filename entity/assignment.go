@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Assignment records the period in which a driver was responsible for a
+// vehicle. EndedAt is nil while the assignment is active.
+type Assignment struct {
+	gorm.Model
+	VehicleID uint `gorm:"index"`
+	DriverID  uint `gorm:"index"`
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Vehicle   *Vehicle
+	Driver    *Driver
+}
+
+// ActiveAt reports whether the driver was responsible for the vehicle at the given time.
+func (a Assignment) ActiveAt(at time.Time) bool {
+	return !a.StartedAt.After(at) && (a.EndedAt == nil || a.EndedAt.After(at))
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignment_ActiveAt(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assignment := Assignment{StartedAt: startedAt, EndedAt: &endedAt}
+	assert.True(t, assignment.ActiveAt(startedAt))
+	assert.True(t, assignment.ActiveAt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, assignment.ActiveAt(endedAt))
+	assert.False(t, assignment.ActiveAt(startedAt.Add(-time.Second)))
+
+	active := Assignment{StartedAt: startedAt}
+	assert.True(t, active.ActiveAt(time.Now()))
+}
@@ -0,0 +1,178 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// AnonymousActor is recorded as the actor of the changes made by requests
+// that do not identify who sent them.
+const AnonymousActor = "anonymous"
+
+// RoleAdmin is the role of the actors allowed to purge records.
+const RoleAdmin = "admin"
+
+// Entities and operations recorded in the audit log.
+const (
+	AuditEntityDriver  string = "driver"
+	AuditEntityVehicle string = "vehicle"
+
+	AuditOperationCreate   string = "create"
+	AuditOperationUpdate   string = "update"
+	AuditOperationDelete   string = "delete"
+	AuditOperationAssign   string = "assign"
+	AuditOperationUnassign string = "unassign"
+	AuditOperationTransfer string = "transfer"
+	AuditOperationRestore  string = "restore"
+	AuditOperationPurge    string = "purge"
+	AuditOperationStatus   string = "status"
+)
+
+// Actor is who requested a change, recorded in the audit log, and the role
+// deciding what they are allowed to do.
+type Actor struct {
+	Name string
+	Role string
+}
+
+// NewActor returns the actor with name and role, or the anonymous actor when
+// name is empty.
+func NewActor(name string, role string) Actor {
+	if name == "" {
+		name = AnonymousActor
+	}
+	return Actor{Name: name, Role: role}
+}
+
+// IsAdmin reports whether the actor has the admin role.
+func (a Actor) IsAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+// AuditEntry records a change of a driver or vehicle: who made it, when, and
+// the value of each changed field before and after it.
+type AuditEntry struct {
+	ID         uint          `gorm:"primaryKey"`
+	EntityType string        `gorm:"size:20;index:idx_audit_entries_entity"`
+	EntityID   uint          `gorm:"index:idx_audit_entries_entity"`
+	Operation  string        `gorm:"size:20"`
+	Actor      string        `gorm:"size:255"`
+	Changes    []AuditChange `gorm:"serializer:json;type:text"`
+	CreatedAt  time.Time
+}
+
+// AuditChange is the value of a field before and after a change. Before is
+// nil for created records and After is nil for deleted ones.
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// NewAuditEntry returns the entry of a change of the record with id.
+func NewAuditEntry(actor Actor, entityType string, id uint, operation string, changes []AuditChange) *AuditEntry {
+	return &AuditEntry{
+		EntityType: entityType,
+		EntityID:   id,
+		Operation:  operation,
+		Actor:      actor.Name,
+		Changes:    changes,
+	}
+}
+
+// AuditFields returns the audited fields of the driver by their name in the
+// API. The vehicles are audited through their own driver.
+func (d *Driver) AuditFields() map[string]interface{} {
+	if d == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"name":             d.Name,
+		"lastName":         d.LastName,
+		"email":            d.Email,
+		"phone":            d.Phone,
+		"cpf":              d.CPF,
+		"license":          d.License,
+		"licenseType":      d.LicenseType,
+		"licenseIssuedAt":  auditDate(d.LicenseIssuedAt),
+		"licenseExpiresAt": auditDate(d.LicenseExpiresAt),
+		"firstLicenseAt":   auditDate(d.FirstLicenseAt),
+		"externalSource":   auditString(d.ExternalSource),
+		"externalId":       auditString(d.ExternalID),
+		"status":           d.Status,
+	}
+}
+
+// AuditFields returns the audited fields of the vehicle by their name in the API.
+func (v *Vehicle) AuditFields() map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	var driverId interface{}
+	if v.DriverID != nil {
+		driverId = *v.DriverID
+	}
+	return map[string]interface{}{
+		"brand":          v.Brand,
+		"vehicleModel":   v.VehicleModel,
+		"year":           v.Year,
+		"class":          v.Class,
+		"plate":          v.Plate,
+		"plateCountry":   v.PlateCountry,
+		"plateFormat":    v.PlateFormat,
+		"driverId":       driverId,
+		"externalSource": auditString(v.ExternalSource),
+		"externalId":     auditString(v.ExternalID),
+		"status":         v.Status,
+	}
+}
+
+// DiffAudit returns the fields whose value differ between before and after,
+// sorted by name. A nil before lists the filled fields of a created record
+// and a nil after the filled fields of a deleted one.
+func DiffAudit(before map[string]interface{}, after map[string]interface{}) []AuditChange {
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
+	}
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []AuditChange
+	for _, field := range fields {
+		old, value := blankToNil(before[field]), blankToNil(after[field])
+		if old == value {
+			continue
+		}
+		changes = append(changes, AuditChange{Field: field, Before: old, After: value})
+	}
+	return changes
+}
+
+// blankToNil treats the zero value of a field as missing, so a created
+// record does not list its empty fields as changed.
+func blankToNil(value interface{}) interface{} {
+	switch value {
+	case "", 0:
+		return nil
+	}
+	return value
+}
+
+func auditDate(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.DateOnly)
+}
+
+func auditString(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAudit(t *testing.T) {
+	issuedAt := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	driverId := uint(3)
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   []AuditChange
+	}{
+		{
+			name:   "Should list the changed fields",
+			before: (&Driver{Name: "John", LicenseType: "B", Phone: "21984736452"}).AuditFields(),
+			after:  (&Driver{Name: "John", LicenseType: "CE", Phone: "21984736452", LicenseIssuedAt: &issuedAt}).AuditFields(),
+			want: []AuditChange{
+				{Field: "licenseIssuedAt", Before: nil, After: "2020-01-15"},
+				{Field: "licenseType", Before: "B", After: "CE"},
+			},
+		},
+		{
+			name:   "Should list the filled fields of a created record",
+			before: nil,
+			after:  (&Vehicle{Plate: "ABC1D23", Year: 2021, DriverID: &driverId}).AuditFields(),
+			want: []AuditChange{
+				{Field: "driverId", Before: nil, After: uint(3)},
+				{Field: "plate", Before: nil, After: "ABC1D23"},
+				{Field: "year", Before: nil, After: 2021},
+			},
+		},
+		{
+			name:   "Should list the filled fields of a deleted record",
+			before: (&Vehicle{Plate: "ABC1D23"}).AuditFields(),
+			after:  nil,
+			want:   []AuditChange{{Field: "plate", Before: "ABC1D23", After: nil}},
+		},
+		{
+			name:   "Should return no changes when every field is kept",
+			before: (&Driver{Name: "John"}).AuditFields(),
+			after:  (&Driver{Name: "John", Version: 2}).AuditFields(),
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffAudit(tt.before, tt.after))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// OnlyDigits removes every character that is not a digit, so masked
+// documents such as "123.456.789-09" are stored as "12345678909".
+func OnlyDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// ValidCPF checks the two check digits of a CPF with 11 digits.
+func ValidCPF(cpf string) bool {
+	if !hasDigits(cpf, 11) || repeatedDigits(cpf) {
+		return false
+	}
+	digits := toDigits(cpf)
+
+	for check := 9; check < 11; check++ {
+		sum := 0
+		for i := 0; i < check; i++ {
+			sum += digits[i] * (check + 1 - i)
+		}
+		digit := (sum * 10) % 11
+		if digit == 10 {
+			digit = 0
+		}
+		if digit != digits[check] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidCNH checks the two check digits of a CNH (registro nacional) with 11 digits.
+func ValidCNH(cnh string) bool {
+	if !hasDigits(cnh, 11) || repeatedDigits(cnh) {
+		return false
+	}
+	digits := toDigits(cnh)
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += digits[i] * (9 - i)
+	}
+	first := sum % 11
+	discount := 0
+	if first >= 10 {
+		first = 0
+		discount = 2
+	}
+
+	sum = 0
+	for i := 0; i < 9; i++ {
+		sum += digits[i] * (i + 1)
+	}
+	second := sum % 11
+	if second >= 10 {
+		second = 0
+	} else {
+		second -= discount
+	}
+
+	return digits[9] == first && digits[10] == second
+}
+
+func hasDigits(value string, size int) bool {
+	return len(value) == size && OnlyDigits(value) == value
+}
+
+func repeatedDigits(value string) bool {
+	return strings.Count(value, value[:1]) == len(value)
+}
+
+func toDigits(value string) []int {
+	digits := make([]int, len(value))
+	for i, r := range value {
+		digits[i] = int(r - '0')
+	}
+	return digits
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlyDigits(t *testing.T) {
+	assert.Equal(t, "52998224725", OnlyDigits("529.982.247-25"))
+	assert.Equal(t, "", OnlyDigits("abc"))
+}
+
+func TestValidCPF(t *testing.T) {
+	assert.True(t, ValidCPF("52998224725"))
+	assert.True(t, ValidCPF("12345678062"))
+	assert.False(t, ValidCPF("52998224724"))
+	assert.False(t, ValidCPF("11111111111"))
+	assert.False(t, ValidCPF("529982247"))
+	assert.False(t, ValidCPF("529.982.247-25"))
+}
+
+func TestValidCNH(t *testing.T) {
+	assert.True(t, ValidCNH("02650306461"))
+	assert.True(t, ValidCNH("12345678026"))
+	assert.False(t, ValidCNH("12345678027"))
+	assert.False(t, ValidCNH("00000000000"))
+	assert.False(t, ValidCNH("928843839"))
+}
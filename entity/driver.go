@@ -1,9 +1,12 @@
 package entity
 
 import (
+	"fmt"
 	"net/mail"
 	"regexp"
+	"time"
 
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"gorm.io/gorm"
 )
 
@@ -24,19 +27,59 @@ const (
 	LicenseTypeDE  string = "DE"
 	LicenseTypeD1E string = "D1E"
 
-	regexPhone   string = `((\+|\(|0)?\d{1,3})?((\s|\)|\-))?(\d{10})$`
-	regexLicense string = `^[a-zA-Z0-9]{6,11}$`
+	regexPhone string = `((\+|\(|0)?\d{1,3})?((\s|\)|\-))?(\d{10})$`
 )
 
 type Driver struct {
 	gorm.Model
 	Name        string
 	LastName    string
-	Email       string
+	Email       string `gorm:"uniqueIndex;size:255"`
 	Phone       string
-	License     string
+	CPF         string `gorm:"uniqueIndex;size:11"`
+	License     string `gorm:"uniqueIndex;size:11"`
 	LicenseType string
-	Vehicles    []Vehicle
+	// ExternalSource and ExternalID identify the driver in the system it is
+	// mastered in, e.g. the HR system. Both are null for drivers created here.
+	ExternalSource *string `gorm:"size:50;uniqueIndex:idx_drivers_external"`
+	ExternalID     *string `gorm:"size:100;uniqueIndex:idx_drivers_external"`
+	// dates of the current license and of the first license ever issued to the driver
+	LicenseIssuedAt  *time.Time
+	LicenseExpiresAt *time.Time `gorm:"index"`
+	FirstLicenseAt   *time.Time
+	// LicenseNotifiedExpiry is the license expiry date the driver was last
+	// notified about, so each license is only notified once
+	LicenseNotifiedExpiry *time.Time
+	Vehicles              []Vehicle
+	// Status is changed through the transitions allowed by the usecases
+	Status string `gorm:"size:20;not null;default:active;index"`
+	// Version is incremented on every update and used as the ETag of the driver
+	Version uint `gorm:"not null;default:1"`
+}
+
+// BeforeCreate starts the version and the status of a new driver, since the
+// column defaults are not read back after the insert.
+func (d *Driver) BeforeCreate(tx *gorm.DB) error {
+	if d.Version == 0 {
+		d.Version = 1
+	}
+	if d.Status == "" {
+		d.Status = DriverStatusActive
+	}
+	return nil
+}
+
+// NormalizeDocuments keeps only the digits of the CPF and license, so masked
+// and unmasked input are stored the same way.
+func (d *Driver) NormalizeDocuments() {
+	d.CPF = OnlyDigits(d.CPF)
+	d.License = OnlyDigits(d.License)
+}
+
+// LicenseExpired reports whether the license expiration date is before now.
+// A driver without an expiration date is not considered expired.
+func (d Driver) LicenseExpired(now time.Time) bool {
+	return d.LicenseExpiresAt != nil && d.LicenseExpiresAt.Before(now)
 }
 
 func (d Driver) Validate() error {
@@ -46,43 +89,80 @@ func (d Driver) Validate() error {
 	d.validateLastName(err)
 	d.validateEmail(err)
 	d.validatePhone(err)
+	d.validateCPF(err)
 	d.validateLicense(err)
 	d.validateLicenseType(err)
+	d.validateLicenseDates(err)
+	validateExternalId(err, d.ExternalSource, d.ExternalID)
 
-	if len(err.Message) > 0 {
+	for i, vehicle := range d.Vehicles {
+		err.Nest(fmt.Sprintf("vehicles[%d]", i), vehicle.Validate())
+	}
+
+	if len(err.Errors) > 0 {
 		return err
 	}
 	return nil
 }
 
 func (d Driver) validateName(err *ErrorInvalidField) {
-	if d.Name == "" || len(d.Name) < 3 {
-		err.Message = append(err.Message, "driver name is invalid")
+	switch {
+	case d.Name == "":
+		err.Add("name", CodeRequired, nil, i18n.DriverNameInvalid)
+	case len(d.Name) < 3:
+		err.Add("name", CodeTooShort, d.Name, i18n.DriverNameInvalid)
 	}
 }
 
 func (d Driver) validateLastName(err *ErrorInvalidField) {
-	if d.LastName == "" || len(d.LastName) < 3 {
-		err.Message = append(err.Message, "driver last name is invalid")
+	switch {
+	case d.LastName == "":
+		err.Add("lastName", CodeRequired, nil, i18n.DriverLastNameInvalid)
+	case len(d.LastName) < 3:
+		err.Add("lastName", CodeTooShort, d.LastName, i18n.DriverLastNameInvalid)
 	}
 }
 
 func (d Driver) validateEmail(err *ErrorInvalidField) {
+	if d.Email == "" {
+		err.Add("email", CodeRequired, nil, i18n.DriverEmailInvalid)
+		return
+	}
 	_, errMail := mail.ParseAddress(d.Email)
 	if errMail != nil {
-		err.Message = append(err.Message, "driver email is invalid")
+		err.Add("email", CodeInvalidFormat, d.Email, i18n.DriverEmailInvalid)
 	}
 }
 
 func (d Driver) validatePhone(err *ErrorInvalidField) {
+	if d.Phone == "" {
+		err.Add("phone", CodeRequired, nil, i18n.DriverPhoneInvalid)
+		return
+	}
 	if !regexp.MustCompile(regexPhone).MatchString(d.Phone) {
-		err.Message = append(err.Message, "driver phone is invalid")
+		err.Add("phone", CodeInvalidFormat, d.Phone, i18n.DriverPhoneInvalid)
+	}
+}
+
+func (d Driver) validateCPF(err *ErrorInvalidField) {
+	switch {
+	case d.CPF == "":
+		err.Add("cpf", CodeRequired, nil, i18n.DriverCPFRequired)
+	case !hasDigits(d.CPF, 11):
+		err.Add("cpf", CodeInvalidLength, d.CPF, i18n.DriverCPFInvalidLength)
+	case !ValidCPF(d.CPF):
+		err.Add("cpf", CodeInvalidCheckDigits, d.CPF, i18n.DriverCPFInvalidCheckDigits)
 	}
 }
 
 func (d Driver) validateLicense(err *ErrorInvalidField) {
-	if !regexp.MustCompile(regexLicense).MatchString(d.License) {
-		err.Message = append(err.Message, "driver license is invalid")
+	switch {
+	case d.License == "":
+		err.Add("license", CodeRequired, nil, i18n.DriverLicenseRequired)
+	case !hasDigits(d.License, 11):
+		err.Add("license", CodeInvalidLength, d.License, i18n.DriverLicenseInvalidLength)
+	case !ValidCNH(d.License):
+		err.Add("license", CodeInvalidCheckDigits, d.License, i18n.DriverLicenseInvalidCheckDigits)
 	}
 }
 
@@ -93,6 +173,21 @@ func (d Driver) validateLicenseType(err *ErrorInvalidField) {
 		LicenseTypeC1, LicenseTypeD1, LicenseTypeBE, LicenseTypeCE,
 		LicenseTypeC1E, LicenseTypeDE, LicenseTypeD1E:
 		return
+	case "":
+		err.Add("licenseType", CodeRequired, nil, i18n.DriverLicenseTypeInvalid)
+		return
+	}
+	err.Add("licenseType", CodeInvalid, d.LicenseType, i18n.DriverLicenseTypeInvalid)
+}
+
+func (d Driver) validateLicenseDates(err *ErrorInvalidField) {
+	if d.LicenseIssuedAt != nil && d.LicenseIssuedAt.After(time.Now()) {
+		err.Add("licenseIssuedAt", CodeOutOfRange, d.LicenseIssuedAt.Format(time.DateOnly), i18n.DriverLicenseIssuedAtInvalid)
+	}
+	if d.LicenseIssuedAt != nil && d.LicenseExpiresAt != nil && !d.LicenseExpiresAt.After(*d.LicenseIssuedAt) {
+		err.Add("licenseExpiresAt", CodeOutOfRange, d.LicenseExpiresAt.Format(time.DateOnly), i18n.DriverLicenseExpiresAtInvalid)
+	}
+	if d.FirstLicenseAt != nil && d.LicenseIssuedAt != nil && d.FirstLicenseAt.After(*d.LicenseIssuedAt) {
+		err.Add("firstLicenseAt", CodeOutOfRange, d.FirstLicenseAt.Format(time.DateOnly), i18n.DriverFirstLicenseAtInvalid)
 	}
-	err.Message = append(err.Message, "driver license type is invalid")
 }
@@ -2,7 +2,9 @@ package entity
 
 import (
 	"testing"
+	"time"
 
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -10,7 +12,7 @@ func TestDriver_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
 		driver  *Driver
-		want    error
+		want    []string
 		wantErr bool
 	}{
 		{
@@ -20,7 +22,8 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john.doe@example.com",
 				Phone:       "1234567890",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: LicenseTypeA,
 			},
 			want:    nil,
@@ -33,10 +36,11 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john.doe@example.com",
 				Phone:       "1234567890",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: LicenseTypeA,
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver name is invalid"}},
+			want:    []string{"driver name is invalid"},
 			wantErr: true,
 		},
 		{
@@ -46,10 +50,11 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "D",
 				Email:       "john@test.com",
 				Phone:       "1234567890",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: LicenseTypeA,
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver last name is invalid"}},
+			want:    []string{"driver last name is invalid"},
 			wantErr: true,
 		},
 		{
@@ -59,10 +64,11 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john.doe.com",
 				Phone:       "1234567890",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: LicenseTypeA,
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver email is invalid"}},
+			want:    []string{"driver email is invalid"},
 			wantErr: true,
 		},
 		{
@@ -72,10 +78,11 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "123456789",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: LicenseTypeA,
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver phone is invalid"}},
+			want:    []string{"driver phone is invalid"},
 			wantErr: true,
 		},
 		{
@@ -85,10 +92,52 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "1234567890",
-				License:     "ABC",
+				CPF:         "52998224725",
+				License:     "12345678",
 				LicenseType: LicenseTypeA,
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver license is invalid"}},
+			want:    []string{"driver license must have 11 digits"},
+			wantErr: true,
+		},
+		{
+			name: "Should return cpf is required",
+			driver: &Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "1234567890",
+				License:     "12345678026",
+				LicenseType: LicenseTypeA,
+			},
+			want:    []string{"driver cpf is required"},
+			wantErr: true,
+		},
+		{
+			name: "Should return cpf check digits are invalid",
+			driver: &Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "1234567890",
+				CPF:         "52998224724",
+				License:     "12345678026",
+				LicenseType: LicenseTypeA,
+			},
+			want:    []string{"driver cpf check digits are invalid"},
+			wantErr: true,
+		},
+		{
+			name: "Should return license check digits are invalid",
+			driver: &Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "1234567890",
+				CPF:         "52998224725",
+				License:     "12345678027",
+				LicenseType: LicenseTypeA,
+			},
+			want:    []string{"driver license check digits are invalid"},
 			wantErr: true,
 		},
 		{
@@ -98,10 +147,11 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "1234567890",
-				License:     "ABC123",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: "X",
 			},
-			want:    &ErrorInvalidField{Message: []string{"driver license type is invalid"}},
+			want:    []string{"driver license type is invalid"},
 			wantErr: true,
 		},
 		{
@@ -111,18 +161,18 @@ func TestDriver_Validate(t *testing.T) {
 				LastName:    "D",
 				Email:       "john.doe.com",
 				Phone:       "123456789",
-				License:     "ABC",
+				CPF:         "5299822",
+				License:     "12345678",
 				LicenseType: "X",
 			},
-			want: &ErrorInvalidField{
-				Message: []string{
-					"driver name is invalid",
-					"driver last name is invalid",
-					"driver email is invalid",
-					"driver phone is invalid",
-					"driver license is invalid",
-					"driver license type is invalid",
-				},
+			want: []string{
+				"driver name is invalid",
+				"driver last name is invalid",
+				"driver email is invalid",
+				"driver phone is invalid",
+				"driver cpf must have 11 digits",
+				"driver license must have 11 digits",
+				"driver license type is invalid",
 			},
 			wantErr: true,
 		},
@@ -131,10 +181,60 @@ func TestDriver_Validate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			err := tt.driver.Validate()
 			if tt.wantErr {
-				assert.Error(t, err)
+				assert.Equal(t, tt.want, err.(*ErrorInvalidField).Messages())
 				return
 			}
 			assert.Nil(t, tt.want, err)
 		})
 	}
 }
+
+func TestDriver_validateLicenseDates(t *testing.T) {
+	issuedAt := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	firstLicenseAt := time.Date(2010, 1, 15, 0, 0, 0, 0, time.UTC)
+	future := time.Now().AddDate(1, 0, 0)
+
+	err := new(ErrorInvalidField)
+	Driver{LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt, FirstLicenseAt: &firstLicenseAt}.validateLicenseDates(err)
+	assert.Empty(t, err.Errors)
+
+	err = new(ErrorInvalidField)
+	Driver{LicenseIssuedAt: &future, LicenseExpiresAt: &issuedAt, FirstLicenseAt: &expiresAt}.validateLicenseDates(err)
+	assert.Equal(t, []string{
+		"driver license issue date is invalid",
+		"driver license expiration date must be after issue date",
+		"driver first license date must not be after issue date",
+	}, err.Messages())
+}
+
+func TestDriver_LicenseExpired(t *testing.T) {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	assert.True(t, Driver{LicenseExpiresAt: &yesterday}.LicenseExpired(now))
+	assert.False(t, Driver{LicenseExpiresAt: &tomorrow}.LicenseExpired(now))
+	assert.False(t, Driver{}.LicenseExpired(now))
+}
+
+func TestDriver_Validate_FieldErrors(t *testing.T) {
+	driver := Driver{
+		LastName:    "Doe",
+		Email:       "john.doe.com",
+		Phone:       "1234567890",
+		CPF:         "52998224725",
+		License:     "123",
+		LicenseType: "X",
+		Vehicles:    []Vehicle{{Brand: "Volvo", VehicleModel: "FH 540", Year: 2021, Class: VehicleClassHeavyTruck, Plate: "AB-12345"}},
+	}
+
+	err := driver.Validate()
+	assert.Equal(t, []FieldError{
+		{Field: "name", Code: CodeRequired, Value: nil, Message: "driver name is invalid", Key: i18n.DriverNameInvalid},
+		{Field: "email", Code: CodeInvalidFormat, Value: "john.doe.com", Message: "driver email is invalid", Key: i18n.DriverEmailInvalid},
+		{Field: "license", Code: CodeInvalidLength, Value: "123", Message: "driver license must have 11 digits", Key: i18n.DriverLicenseInvalidLength},
+		{Field: "licenseType", Code: CodeInvalid, Value: "X", Message: "driver license type is invalid", Key: i18n.DriverLicenseTypeInvalid},
+		{Field: "vehicles[0].plate", Code: CodeInvalidFormat, Value: "AB-12345", Message: "vehicle plate is invalid", Key: i18n.VehiclePlateInvalid},
+	}, err.(*ErrorInvalidField).Errors)
+}
@@ -1,11 +1,103 @@
 package entity
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// Codes of the validation violations, stable for clients to rely on.
+const (
+	CodeRequired           string = "required"
+	CodeInvalid            string = "invalid"
+	CodeTooShort           string = "too_short"
+	CodeTooLong            string = "too_long"
+	CodeInvalidFormat      string = "invalid_format"
+	CodeInvalidLength      string = "invalid_length"
+	CodeInvalidCheckDigits string = "invalid_check_digits"
+	CodeOutOfRange         string = "out_of_range"
+	CodeUnknownField       string = "unknown_field"
+	CodeNotAllowed         string = "not_allowed"
+	CodeDuplicate          string = "duplicate"
+)
+
+// FieldError is a single validation violation. Field is the path of the
+// field in the request body or query string, e.g. "licenseType" or
+// "vehicles[0].plate", and Value is the rejected value. Message is in the
+// default language, Key and Args translate it to others.
+type FieldError struct {
+	Field   string
+	Code    string
+	Value   interface{}
+	Message string
+	Key     string
+	Args    []interface{}
+}
 
 type ErrorInvalidField struct {
-	Message []string
+	Errors []FieldError
+}
+
+// NewErrorInvalidField creates an error with a single violation.
+func NewErrorInvalidField(field, code string, value interface{}, key string, args ...interface{}) *ErrorInvalidField {
+	err := new(ErrorInvalidField)
+	err.Add(field, code, value, key, args...)
+	return err
+}
+
+// Add adds a violation whose message is the i18n message key formatted with args.
+func (e *ErrorInvalidField) Add(field, code string, value interface{}, key string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{
+		Field:   field,
+		Code:    code,
+		Value:   value,
+		Message: i18n.Message(i18n.Default, key, args...),
+		Key:     key,
+		Args:    args,
+	})
+}
+
+// Nest adds the violations of err, returned by the validation of a nested
+// value, with their fields under prefix, e.g. "vehicles[0]".
+func (e *ErrorInvalidField) Nest(prefix string, err error) {
+	nested, ok := err.(*ErrorInvalidField)
+	if !ok {
+		return
+	}
+	for _, fieldErr := range nested.Errors {
+		fieldErr.Field = prefix + "." + fieldErr.Field
+		e.Errors = append(e.Errors, fieldErr)
+	}
+}
+
+func (e ErrorInvalidField) Messages() []string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Message
+	}
+	return messages
 }
 
 func (e ErrorInvalidField) Error() string {
-	return strings.Join(e.Message, ",")
+	return strings.Join(e.Messages(), ",")
+}
+
+func (e ErrorInvalidField) ErrorKind() domainerr.Kind {
+	return domainerr.Validation
+}
+
+// ErrorConflict is returned when a unique field already belongs to another record.
+type ErrorConflict struct {
+	Entity string
+	Field  string
+}
+
+func (e ErrorConflict) Error() string {
+	return fmt.Sprintf("%s %s already exists", e.Entity, e.Field)
+}
+
+func (e ErrorConflict) ErrorKind() domainerr.Kind {
+	return domainerr.Conflict
 }
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+const maxExternalIdLength = 100
+
+// regexExternalSource matches the name of the system a record is mastered
+// in, e.g. "erp" or "hr-system". It can not contain ":", which separates the
+// source from the id in the external references of the paths.
+var regexExternalSource = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,49}$`)
+
+// ParseExternalRef splits an external reference as source:id. The id may
+// contain ":" itself.
+func ParseExternalRef(value string) (source string, id string, ok bool) {
+	source, id, ok = strings.Cut(value, ":")
+	if !ok || !regexExternalSource.MatchString(source) || !validExternalId(id) {
+		return "", "", false
+	}
+	return source, id, true
+}
+
+func validExternalId(id string) bool {
+	return strings.TrimSpace(id) != "" && len(id) <= maxExternalIdLength
+}
+
+// validateExternalId checks the external source and id of a record, which
+// must be informed together.
+func validateExternalId(err *ErrorInvalidField, source *string, id *string) {
+	switch {
+	case source == nil && id == nil:
+		return
+	case source == nil:
+		err.Add("externalSource", CodeRequired, nil, i18n.ExternalSourceInvalid)
+	case !regexExternalSource.MatchString(*source):
+		err.Add("externalSource", CodeInvalidFormat, *source, i18n.ExternalSourceInvalid)
+	}
+	switch {
+	case id == nil:
+		err.Add("externalId", CodeRequired, nil, i18n.ExternalIdInvalid)
+	case !validExternalId(*id):
+		err.Add("externalId", CodeInvalidFormat, *id, i18n.ExternalIdInvalid)
+	}
+}
+
+// ValidateExternalId checks the external source and id used to look up a
+// record.
+func ValidateExternalId(source string, id string) error {
+	err := new(ErrorInvalidField)
+	validateExternalId(err, &source, &id)
+	if len(err.Errors) > 0 {
+		return err
+	}
+	return nil
+}
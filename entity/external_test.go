@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExternalRef(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantSource string
+		wantId     string
+		wantOk     bool
+	}{
+		{value: "hr:1234", wantSource: "hr", wantId: "1234", wantOk: true},
+		{value: "sap-erp:urn:truck:42", wantSource: "sap-erp", wantId: "urn:truck:42", wantOk: true},
+		{value: "1234", wantOk: false},
+		{value: ":1234", wantOk: false},
+		{value: "hr:", wantOk: false},
+		{value: "h r:1234", wantOk: false},
+		{value: "hr:" + strings.Repeat("1", 101), wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			source, id, ok := ParseExternalRef(tt.value)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantSource, source)
+			assert.Equal(t, tt.wantId, id)
+		})
+	}
+}
+
+func TestValidateExternalId(t *testing.T) {
+	source, id, blank := "erp", "TRK-0042", " "
+
+	tests := []struct {
+		name       string
+		source     *string
+		id         *string
+		wantFields []string
+	}{
+		{name: "Should accept a record without external id"},
+		{name: "Should accept source and id", source: &source, id: &id},
+		{name: "Should require the id with the source", source: &source, wantFields: []string{"externalId"}},
+		{name: "Should require the source with the id", id: &id, wantFields: []string{"externalSource"}},
+		{name: "Should reject a blank id", source: &source, id: &blank, wantFields: []string{"externalId"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := new(ErrorInvalidField)
+			validateExternalId(err, tt.source, tt.id)
+			var fields []string
+			for _, fieldErr := range err.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// IdempotencyKey stores the response of a request sent with an
+// Idempotency-Key header, so a retry of the same request receives the same
+// response instead of being executed again. StatusCode is zero while the
+// first request is still being processed.
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey;size:255"`
+	Fingerprint string `gorm:"size:64"`
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
+
+func (k IdempotencyKey) Expired(now time.Time, ttl time.Duration) bool {
+	return k.CreatedAt.Add(ttl).Before(now)
+}
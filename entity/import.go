@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// Modes of an import.
+const (
+	// ImportModeAllOrNothing imports the rows only when every row is valid.
+	ImportModeAllOrNothing string = "all-or-nothing"
+	// ImportModeBestEffort imports the valid rows and reports the others.
+	ImportModeBestEffort string = "best-effort"
+)
+
+// MaxImportRows is the maximum number of rows of a file imported at once.
+const MaxImportRows = 5000
+
+type ImportOptions struct {
+	Mode string
+	// DryRun validates the rows without importing them
+	DryRun bool
+}
+
+// NewImportOptions returns the default options, importing all or nothing.
+func NewImportOptions() *ImportOptions {
+	return &ImportOptions{Mode: ImportModeAllOrNothing}
+}
+
+func (o ImportOptions) Validate() error {
+	switch o.Mode {
+	case ImportModeAllOrNothing, ImportModeBestEffort:
+		return nil
+	}
+	modes := strings.Join([]string{ImportModeAllOrNothing, ImportModeBestEffort}, ", ")
+	return NewErrorInvalidField("mode", CodeInvalid, o.Mode, i18n.ImportModeInvalid, modes)
+}
+
+// DriverImportRow is a driver read from the line of an imported file. Err
+// is set when the line could not be read as a driver, e.g. an invalid date.
+type DriverImportRow struct {
+	Line   int
+	Driver *Driver
+	Err    error
+}
+
+// VehicleImportRow is a vehicle read from the line of an imported file.
+type VehicleImportRow struct {
+	Line    int
+	Vehicle *Vehicle
+	Err     error
+}
+
+// ImportRowError is the reason a line of an imported file was rejected.
+type ImportRowError struct {
+	Line int
+	Err  error
+}
+
+// ImportReport is the result of an import. Valid counts the rows that passed
+// the validation and Imported the ones created, which is zero on a dry run
+// and on an all or nothing import with invalid rows.
+type ImportReport struct {
+	Mode     string
+	DryRun   bool
+	Total    int
+	Valid    int
+	Imported int
+	Errors   []ImportRowError
+}
+
+func NewImportReport(opts ImportOptions, total int) *ImportReport {
+	return &ImportReport{Mode: opts.Mode, DryRun: opts.DryRun, Total: total}
+}
+
+// Reject records that the row of line was not imported because of err.
+func (r *ImportReport) Reject(line int, err error) {
+	r.Errors = append(r.Errors, ImportRowError{Line: line, Err: err})
+}
+
+// Failed returns the number of rejected rows.
+func (r ImportReport) Failed() int {
+	return len(r.Errors)
+}
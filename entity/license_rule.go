@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+)
+
+const (
+	VehicleClassMotorcycle  string = "motorcycle"
+	VehicleClassCar         string = "car"
+	VehicleClassLightTruck  string = "light_truck"
+	VehicleClassHeavyTruck  string = "heavy_truck"
+	VehicleClassBus         string = "bus"
+	VehicleClassArticulated string = "articulated"
+)
+
+// licenseVehicleClasses maps each license type to the vehicle classes it may drive.
+var licenseVehicleClasses = map[string][]string{
+	// ACC only allows mopeds, which are not part of the fleet
+	LicenseTypeACC: {},
+	LicenseTypeA:   {VehicleClassMotorcycle},
+	LicenseTypeA1:  {VehicleClassMotorcycle},
+	LicenseTypeAB:  {VehicleClassMotorcycle, VehicleClassCar},
+	LicenseTypeB:   {VehicleClassCar},
+	LicenseTypeB1:  {VehicleClassCar},
+	LicenseTypeBE:  {VehicleClassCar},
+	LicenseTypeC1:  {VehicleClassCar, VehicleClassLightTruck},
+	LicenseTypeC1E: {VehicleClassCar, VehicleClassLightTruck},
+	LicenseTypeC:   {VehicleClassCar, VehicleClassLightTruck, VehicleClassHeavyTruck},
+	LicenseTypeCE:  {VehicleClassCar, VehicleClassLightTruck, VehicleClassHeavyTruck, VehicleClassArticulated},
+	LicenseTypeD1:  {VehicleClassCar, VehicleClassBus},
+	LicenseTypeD1E: {VehicleClassCar, VehicleClassBus},
+	LicenseTypeD:   {VehicleClassCar, VehicleClassLightTruck, VehicleClassHeavyTruck, VehicleClassBus},
+	LicenseTypeDE:  {VehicleClassCar, VehicleClassLightTruck, VehicleClassHeavyTruck, VehicleClassBus, VehicleClassArticulated},
+}
+
+// ErrorIncompatibleLicense is returned when a driver license type does not
+// allow driving the class of a vehicle.
+type ErrorIncompatibleLicense struct {
+	LicenseType  string
+	VehicleClass string
+	Plate        string
+}
+
+func (e ErrorIncompatibleLicense) Error() string {
+	allowed := strings.Join(licenseVehicleClasses[e.LicenseType], ", ")
+	if allowed == "" {
+		allowed = "none"
+	}
+	return fmt.Sprintf("driver license type %s does not allow driving vehicle %s of class %s, allowed classes: %s",
+		e.LicenseType, e.Plate, e.VehicleClass, allowed)
+}
+
+func (e ErrorIncompatibleLicense) ErrorKind() domainerr.Kind {
+	return domainerr.Unprocessable
+}
+
+// AllowedVehicleClasses returns the vehicle classes a license type may drive.
+func AllowedVehicleClasses(licenseType string) []string {
+	return licenseVehicleClasses[licenseType]
+}
+
+// CheckLicense returns an ErrorIncompatibleLicense when the driver license
+// type does not allow driving the vehicle.
+func (d Driver) CheckLicense(vehicle Vehicle) error {
+	if slices.Contains(licenseVehicleClasses[d.LicenseType], vehicle.Class) {
+		return nil
+	}
+	return &ErrorIncompatibleLicense{
+		LicenseType:  d.LicenseType,
+		VehicleClass: vehicle.Class,
+		Plate:        vehicle.Plate,
+	}
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriver_CheckLicense(t *testing.T) {
+	tests := []struct {
+		name        string
+		licenseType string
+		class       string
+		wantErr     bool
+	}{
+		{name: "Should allow A to drive motorcycle", licenseType: LicenseTypeA, class: VehicleClassMotorcycle},
+		{name: "Should allow B to drive car", licenseType: LicenseTypeB, class: VehicleClassCar},
+		{name: "Should allow C to drive heavy truck", licenseType: LicenseTypeC, class: VehicleClassHeavyTruck},
+		{name: "Should allow D to drive bus", licenseType: LicenseTypeD, class: VehicleClassBus},
+		{name: "Should allow CE to drive articulated", licenseType: LicenseTypeCE, class: VehicleClassArticulated},
+		{name: "Should not allow A to drive heavy truck", licenseType: LicenseTypeA, class: VehicleClassHeavyTruck, wantErr: true},
+		{name: "Should not allow B to drive motorcycle", licenseType: LicenseTypeB, class: VehicleClassMotorcycle, wantErr: true},
+		{name: "Should not allow C to drive bus", licenseType: LicenseTypeC, class: VehicleClassBus, wantErr: true},
+		{name: "Should not allow ACC to drive car", licenseType: LicenseTypeACC, class: VehicleClassCar, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Driver{LicenseType: tt.licenseType}.CheckLicense(Vehicle{Plate: "ABC1234", Class: tt.class})
+			if tt.wantErr {
+				assert.IsType(t, &ErrorIncompatibleLicense{}, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestErrorIncompatibleLicense_Error(t *testing.T) {
+	err := ErrorIncompatibleLicense{LicenseType: LicenseTypeA, VehicleClass: VehicleClassHeavyTruck, Plate: "ABC1234"}
+	assert.Equal(t, "driver license type A does not allow driving vehicle ABC1234 of class heavy_truck, allowed classes: motorcycle", err.Error())
+}
+
+func TestAllowedVehicleClasses(t *testing.T) {
+	for _, licenseType := range []string{
+		LicenseTypeACC, LicenseTypeA, LicenseTypeA1, LicenseTypeAB, LicenseTypeB, LicenseTypeB1,
+		LicenseTypeC, LicenseTypeC1, LicenseTypeD, LicenseTypeD1, LicenseTypeBE, LicenseTypeCE,
+		LicenseTypeC1E, LicenseTypeDE, LicenseTypeD1E,
+	} {
+		_, ok := licenseVehicleClasses[licenseType]
+		assert.True(t, ok, "license type %s has no rule", licenseType)
+	}
+	assert.Equal(t, []string{VehicleClassMotorcycle, VehicleClassCar}, AllowedVehicleClasses(LicenseTypeAB))
+}
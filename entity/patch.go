@@ -0,0 +1,17 @@
+package entity
+
+// Media types of the patch documents accepted by PATCH.
+const (
+	PatchTypeMerge string = "application/merge-patch+json"
+	PatchTypeJSON  string = "application/json-patch+json"
+)
+
+// Patch is a change to the fields of a record, as a JSON Merge Patch
+// (RFC 7396) or a JSON Patch (RFC 6902) document over the same field names
+// used by the requests, e.g. "licenseType".
+type Patch struct {
+	Type     string
+	Document []byte
+	// Version is the version the patch was made for, zero for any version
+	Version uint
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	PlateCountryBrazil    string = "BR"
+	PlateCountryArgentina string = "AR"
+	PlateCountryUruguay   string = "UY"
+	PlateCountryParaguay  string = "PY"
+
+	PlateFormatBrazilian          string = "brazilian"
+	PlateFormatMercosul           string = "mercosul"
+	PlateFormatMercosulMotorcycle string = "mercosul_motorcycle"
+)
+
+// PlateFormat describes how a plate is written in a country. Patterns are
+// matched against the canonical form: upper case letters and digits only.
+type PlateFormat struct {
+	Country string
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// plateFormats is ordered by priority, so a plate valid in more than one
+// country is detected as the first one unless the country is informed.
+var plateFormats = []PlateFormat{
+	{Country: PlateCountryBrazil, Name: PlateFormatBrazilian, Pattern: regexp.MustCompile(`^[A-Z]{3}[0-9]{4}$`)},
+	{Country: PlateCountryBrazil, Name: PlateFormatMercosul, Pattern: regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z][0-9]{2}$`)},
+	{Country: PlateCountryArgentina, Name: PlateFormatMercosul, Pattern: regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`)},
+	{Country: PlateCountryUruguay, Name: PlateFormatMercosul, Pattern: regexp.MustCompile(`^[A-Z]{3}[0-9]{4}$`)},
+	{Country: PlateCountryParaguay, Name: PlateFormatMercosul, Pattern: regexp.MustCompile(`^[A-Z]{4}[0-9]{3}$`)},
+	{Country: PlateCountryParaguay, Name: PlateFormatMercosulMotorcycle, Pattern: regexp.MustCompile(`^[0-9]{3}[A-Z]{4}$`)},
+}
+
+// RegisterPlateFormat adds a new plate format with the lowest priority.
+func RegisterPlateFormat(country, name, pattern string) {
+	plateFormats = append(plateFormats, PlateFormat{
+		Country: country,
+		Name:    name,
+		Pattern: regexp.MustCompile(pattern),
+	})
+}
+
+// NormalizePlate returns the canonical form of a plate, removing case,
+// dashes, dots and spaces differences, e.g. "abc-1234" becomes "ABC1234".
+func NormalizePlate(plate string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '.':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(plate)))
+}
+
+// DetectPlateFormat finds the format of a plate. When country is empty every
+// registered format is considered.
+func DetectPlateFormat(plate, country string) (*PlateFormat, bool) {
+	plate = NormalizePlate(plate)
+	country = strings.ToUpper(country)
+	for i, format := range plateFormats {
+		if country != "" && format.Country != country {
+			continue
+		}
+		if format.Pattern.MatchString(plate) {
+			return &plateFormats[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePlate(t *testing.T) {
+	assert.Equal(t, "ABC1234", NormalizePlate("abc-1234"))
+	assert.Equal(t, "AB123CD", NormalizePlate(" ab 123 cd "))
+	assert.Equal(t, "ABC1D23", NormalizePlate("ABC.1D23"))
+}
+
+func TestDetectPlateFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		plate       string
+		country     string
+		wantCountry string
+		wantFormat  string
+		wantOk      bool
+	}{
+		{
+			name:        "Should detect old brazilian plate",
+			plate:       "ABC-1234",
+			wantCountry: PlateCountryBrazil,
+			wantFormat:  PlateFormatBrazilian,
+			wantOk:      true,
+		},
+		{
+			name:        "Should detect brazilian mercosul plate",
+			plate:       "ABC1D23",
+			wantCountry: PlateCountryBrazil,
+			wantFormat:  PlateFormatMercosul,
+			wantOk:      true,
+		},
+		{
+			name:        "Should detect argentinian mercosul plate",
+			plate:       "AB 123 CD",
+			wantCountry: PlateCountryArgentina,
+			wantFormat:  PlateFormatMercosul,
+			wantOk:      true,
+		},
+		{
+			name:        "Should detect uruguayan mercosul plate when country is informed",
+			plate:       "SBA 1234",
+			country:     "uy",
+			wantCountry: PlateCountryUruguay,
+			wantFormat:  PlateFormatMercosul,
+			wantOk:      true,
+		},
+		{
+			name:        "Should detect paraguayan mercosul plate",
+			plate:       "ABCD123",
+			wantCountry: PlateCountryParaguay,
+			wantFormat:  PlateFormatMercosul,
+			wantOk:      true,
+		},
+		{
+			name:        "Should detect paraguayan mercosul motorcycle plate",
+			plate:       "123ABCD",
+			wantCountry: PlateCountryParaguay,
+			wantFormat:  PlateFormatMercosulMotorcycle,
+			wantOk:      true,
+		},
+		{
+			name:   "Should not detect invalid plate",
+			plate:  "AB-12345",
+			wantOk: false,
+		},
+		{
+			name:    "Should not detect plate of another country",
+			plate:   "ABC1D23",
+			country: PlateCountryParaguay,
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := DetectPlateFormat(tt.plate, tt.country)
+			assert.Equal(t, tt.wantOk, ok)
+			if !tt.wantOk {
+				return
+			}
+			assert.Equal(t, tt.wantCountry, format.Country)
+			assert.Equal(t, tt.wantFormat, format.Name)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+const (
+	DefaultPageSize int = 20
+	MaxPageSize     int = 100
+
+	FilterOperatorEqual       string = "eq"
+	FilterOperatorGreaterThan string = "gte"
+	FilterOperatorLessThan    string = "lte"
+	// FilterOperatorNull matches empty columns when the value is "true" and
+	// filled ones otherwise.
+	FilterOperatorNull string = "null"
+)
+
+// DriverQueryFields maps the driver fields accepted in filters and sorting to their columns.
+var DriverQueryFields = map[string]string{
+	"id":               "id",
+	"name":             "name",
+	"lastName":         "last_name",
+	"email":            "email",
+	"phone":            "phone",
+	"license":          "license",
+	"cpf":              "cpf",
+	"licenseType":      "license_type",
+	"licenseIssuedAt":  "license_issued_at",
+	"licenseExpiresAt": "license_expires_at",
+	"firstLicenseAt":   "first_license_at",
+	"externalSource":   "external_source",
+	"externalId":       "external_id",
+	"status":           "status",
+	"createdAt":        "created_at",
+	"updatedAt":        "updated_at",
+}
+
+// VehicleQueryFields maps the vehicle fields accepted in filters and sorting to their columns.
+var VehicleQueryFields = map[string]string{
+	"id":             "id",
+	"brand":          "brand",
+	"vehicleModel":   "vehicle_model",
+	"year":           "year",
+	"class":          "class",
+	"plate":          "plate",
+	"plateCountry":   "plate_country",
+	"plateFormat":    "plate_format",
+	"driverId":       "driver_id",
+	"externalSource": "external_source",
+	"externalId":     "external_id",
+	"status":         "status",
+	"createdAt":      "created_at",
+	"updatedAt":      "updated_at",
+}
+
+type Filter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// QueryOptions holds the pagination, filtering and sorting of a list request.
+// When After is set the listing is cursor based and Page is ignored. Deleted
+// lists the soft deleted records instead of the active ones.
+type QueryOptions struct {
+	Page     int
+	PageSize int
+	After    uint
+	Deleted  bool
+	Filters  []Filter
+	Sort     []Sort
+}
+
+func NewQueryOptions() *QueryOptions {
+	return &QueryOptions{Page: 1, PageSize: DefaultPageSize}
+}
+
+// ParseSort parses a comma separated list of fields, each optionally prefixed
+// with "-" for descending order, e.g. "-year,plate".
+func ParseSort(value string) []Sort {
+	var sorts []Sort
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		sort := Sort{Field: field}
+		if strings.HasPrefix(field, "-") {
+			sort = Sort{Field: field[1:], Desc: true}
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts
+}
+
+// ParseFilter builds a filter from a query parameter. The "From" and "To"
+// suffixes turn it into a range filter, e.g. "yearFrom=2015".
+func ParseFilter(key, value string) Filter {
+	if field, ok := strings.CutSuffix(key, "From"); ok && field != "" {
+		return Filter{Field: field, Operator: FilterOperatorGreaterThan, Value: value}
+	}
+	if field, ok := strings.CutSuffix(key, "To"); ok && field != "" {
+		return Filter{Field: field, Operator: FilterOperatorLessThan, Value: value}
+	}
+	return Filter{Field: key, Operator: FilterOperatorEqual, Value: value}
+}
+
+func (q QueryOptions) Offset() int {
+	if q.After > 0 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+func (q QueryOptions) Validate(fields map[string]string) error {
+	err := new(ErrorInvalidField)
+
+	if q.Page < 1 {
+		err.Add("page", CodeOutOfRange, q.Page, i18n.PageInvalid)
+	}
+	if q.PageSize < 1 || q.PageSize > MaxPageSize {
+		err.Add("pageSize", CodeOutOfRange, q.PageSize, i18n.PageSizeOutOfRange, MaxPageSize)
+	}
+	if q.After > 0 && len(q.Sort) > 0 {
+		err.Add("sort", CodeNotAllowed, nil, i18n.SortNotAllowed)
+	}
+	for _, filter := range q.Filters {
+		if _, ok := fields[filter.Field]; !ok {
+			err.Add(filter.Field, CodeUnknownField, filter.Value, i18n.FilterUnknownField, filter.Field)
+		}
+	}
+	for _, sort := range q.Sort {
+		if _, ok := fields[sort.Field]; !ok {
+			err.Add("sort", CodeUnknownField, sort.Field, i18n.SortUnknownField, sort.Field)
+		}
+	}
+
+	if len(err.Errors) > 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	assert.Equal(t, []Sort{{Field: "year", Desc: true}, {Field: "plate"}}, ParseSort("-year, plate"))
+	assert.Nil(t, ParseSort(""))
+}
+
+func TestParseFilter(t *testing.T) {
+	assert.Equal(t, Filter{Field: "year", Operator: FilterOperatorGreaterThan, Value: "2015"}, ParseFilter("yearFrom", "2015"))
+	assert.Equal(t, Filter{Field: "year", Operator: FilterOperatorLessThan, Value: "2020"}, ParseFilter("yearTo", "2020"))
+	assert.Equal(t, Filter{Field: "brand", Operator: FilterOperatorEqual, Value: "Volvo"}, ParseFilter("brand", "Volvo"))
+}
+
+func TestQueryOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    QueryOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Should return nil",
+			opts: QueryOptions{
+				Page:     1,
+				PageSize: 20,
+				Filters:  []Filter{{Field: "brand", Operator: FilterOperatorEqual, Value: "Volvo"}},
+				Sort:     []Sort{{Field: "year", Desc: true}},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "Should return all fields are invalid",
+			opts: QueryOptions{
+				Page:     0,
+				PageSize: 101,
+				After:    10,
+				Filters:  []Filter{{Field: "color", Operator: FilterOperatorEqual, Value: "red"}},
+				Sort:     []Sort{{Field: "color"}},
+			},
+			want: []string{
+				"page is invalid",
+				"pageSize must be between 1 and 100",
+				"sort can not be combined with after",
+				"filter color is invalid",
+				"sort color is invalid",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate(VehicleQueryFields)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err.(*ErrorInvalidField).Messages())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestQueryOptions_Offset(t *testing.T) {
+	assert.Equal(t, 40, QueryOptions{Page: 3, PageSize: 20}.Offset())
+	assert.Equal(t, 0, QueryOptions{Page: 3, PageSize: 20, After: 5}.Offset())
+}
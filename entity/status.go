@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// Statuses of a driver. Only active drivers can be assigned vehicles.
+const (
+	DriverStatusActive     string = "active"
+	DriverStatusOnLeave    string = "on_leave"
+	DriverStatusSuspended  string = "suspended"
+	DriverStatusTerminated string = "terminated"
+)
+
+// Statuses of a vehicle. A vehicle is in use while it is assigned to a
+// driver, and only available vehicles can be assigned.
+const (
+	VehicleStatusAvailable     string = "available"
+	VehicleStatusInUse         string = "in_use"
+	VehicleStatusInMaintenance string = "in_maintenance"
+	VehicleStatusOutOfService  string = "out_of_service"
+	VehicleStatusSold          string = "sold"
+)
+
+// Reasons recorded for the status changes made by assigning and unassigning
+// vehicles.
+const (
+	StatusReasonAssigned   = "assigned to a driver"
+	StatusReasonUnassigned = "unassigned from its driver"
+)
+
+// MaxStatusReasonLength is the maximum number of characters of the reason of
+// a status change.
+const MaxStatusReasonLength = 500
+
+// StatusChange records a change of the status of a driver or vehicle, who
+// made it and why.
+type StatusChange struct {
+	ID         uint   `gorm:"primaryKey"`
+	EntityType string `gorm:"size:20;index:idx_status_changes_entity"`
+	EntityID   uint   `gorm:"index:idx_status_changes_entity"`
+	FromStatus string `gorm:"size:20"`
+	ToStatus   string `gorm:"size:20"`
+	Reason     string `gorm:"size:500"`
+	Actor      string `gorm:"size:255"`
+	CreatedAt  time.Time
+}
+
+// NewStatusChange returns the change of the status of the record with id
+// from one status to another.
+func NewStatusChange(actor Actor, entityType string, id uint, from string, to string, reason string) *StatusChange {
+	return &StatusChange{
+		EntityType: entityType,
+		EntityID:   id,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		Actor:      actor.Name,
+	}
+}
+
+// ValidateStatusReason makes sure a status change says why it was made.
+func ValidateStatusReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return NewErrorInvalidField("reason", CodeRequired, nil, i18n.StatusReasonRequired)
+	}
+	if utf8.RuneCountInString(reason) > MaxStatusReasonLength {
+		return NewErrorInvalidField("reason", CodeTooLong, reason, i18n.StatusReasonTooLong, MaxStatusReasonLength)
+	}
+	return nil
+}
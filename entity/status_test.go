@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStatusReason(t *testing.T) {
+	assert.Nil(t, ValidateStatusReason("oil change"))
+	assert.Nil(t, ValidateStatusReason(strings.Repeat("é", MaxStatusReasonLength)))
+
+	assert.EqualError(t, ValidateStatusReason(" "), "reason is required")
+	assert.EqualError(t, ValidateStatusReason(strings.Repeat("a", MaxStatusReasonLength+1)), "reason must have at most 500 characters")
+}
@@ -1,13 +1,9 @@
 package entity
 
 import (
-	"regexp"
-
 	"gorm.io/gorm"
-)
 
-const (
-	regexPlate string = `^[A-Z]{3}-\w{4}$`
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 )
 
 type Vehicle struct {
@@ -15,43 +11,124 @@ type Vehicle struct {
 	Brand        string
 	VehicleModel string
 	Year         int
-	Plate        string
-	DriverID     uint
+	Class        string `gorm:"size:20"`
+	Plate        string `gorm:"uniqueIndex;size:10"`
+	PlateCountry string `gorm:"size:2"`
+	PlateFormat  string `gorm:"size:20"`
+	DriverID     *uint
+	// ExternalSource and ExternalID identify the vehicle in the system it is
+	// mastered in, e.g. the ERP. Both are null for vehicles created here.
+	ExternalSource *string `gorm:"size:50;uniqueIndex:idx_vehicles_external"`
+	ExternalID     *string `gorm:"size:100;uniqueIndex:idx_vehicles_external"`
+	// Status is in use while the vehicle is assigned, otherwise it is changed
+	// through the transitions allowed by the usecases
+	Status string `gorm:"size:20;not null;default:available;index"`
+	// Version is incremented on every update and used as the ETag of the vehicle
+	Version uint `gorm:"not null;default:1"`
+}
+
+// BeforeCreate starts the version and the status of a new vehicle, since the
+// column defaults are not read back after the insert.
+func (v *Vehicle) BeforeCreate(tx *gorm.DB) error {
+	if v.Version == 0 {
+		v.Version = 1
+	}
+	if v.Status == "" {
+		v.Status = VehicleStatusAvailable
+	}
+	return nil
+}
+
+// NormalizePlate stores the plate in its canonical form and records the
+// country and format detected for it.
+func (v *Vehicle) NormalizePlate() {
+	v.Plate = NormalizePlate(v.Plate)
+	format, ok := DetectPlateFormat(v.Plate, v.PlateCountry)
+	if !ok {
+		return
+	}
+	v.PlateCountry = format.Country
+	v.PlateFormat = format.Name
 }
 
 func (v Vehicle) Validate() error {
+	return v.validate(true)
+}
+
+// ValidateChange is Validate for a change of current. A vehicle saved before
+// classes were recorded may keep its empty class until one is informed.
+func (v Vehicle) ValidateChange(current Vehicle) error {
+	return v.validate(current.Class != "" || v.Class != "")
+}
+
+func (v Vehicle) validate(requireClass bool) error {
 	err := new(ErrorInvalidField)
 	v.validateBrand(err)
 	v.validateVehicleModel(err)
 	v.validateYear(err)
+	v.validateClass(err, requireClass)
 	v.validatePlate(err)
-	if len(err.Message) > 0 {
+	validateExternalId(err, v.ExternalSource, v.ExternalID)
+	if len(err.Errors) > 0 {
 		return err
 	}
 	return nil
 }
 
 func (v Vehicle) validateBrand(err *ErrorInvalidField) {
-	if v.Brand == "" || len(v.Brand) < 3 {
-		err.Message = append(err.Message, "vehicle brand is invalid")
+	switch {
+	case v.Brand == "":
+		err.Add("brand", CodeRequired, nil, i18n.VehicleBrandInvalid)
+	case len(v.Brand) < 3:
+		err.Add("brand", CodeTooShort, v.Brand, i18n.VehicleBrandInvalid)
 	}
 }
 
 func (v Vehicle) validateVehicleModel(err *ErrorInvalidField) {
-	if v.VehicleModel == "" || len(v.VehicleModel) < 3 {
-		err.Message = append(err.Message, "vehicle model is invalid")
+	switch {
+	case v.VehicleModel == "":
+		err.Add("vehicleModel", CodeRequired, nil, i18n.VehicleModelInvalid)
+	case len(v.VehicleModel) < 3:
+		err.Add("vehicleModel", CodeTooShort, v.VehicleModel, i18n.VehicleModelInvalid)
 	}
 }
 
 func (v Vehicle) validateYear(err *ErrorInvalidField) {
 	// The first car was made in 1886
 	if v.Year <= 1886 {
-		err.Message = append(err.Message, "vehicle year is invalid")
+		err.Add("year", CodeOutOfRange, v.Year, i18n.VehicleYearInvalid)
+	}
+}
+
+func (v Vehicle) validateClass(err *ErrorInvalidField, required bool) {
+	switch {
+	case IsVehicleClass(v.Class):
+		return
+	case v.Class == "":
+		if required {
+			err.Add("class", CodeRequired, nil, i18n.VehicleClassInvalid)
+		}
+		return
 	}
+	err.Add("class", CodeInvalid, v.Class, i18n.VehicleClassInvalid)
+}
+
+// IsVehicleClass reports whether class is one of the vehicle classes.
+func IsVehicleClass(class string) bool {
+	switch class {
+	case VehicleClassMotorcycle, VehicleClassCar, VehicleClassLightTruck,
+		VehicleClassHeavyTruck, VehicleClassBus, VehicleClassArticulated:
+		return true
+	}
+	return false
 }
 
 func (v Vehicle) validatePlate(err *ErrorInvalidField) {
-	if !regexp.MustCompile(regexPlate).MatchString(v.Plate) {
-		err.Message = append(err.Message, "vehicle plate is invalid")
+	if v.Plate == "" {
+		err.Add("plate", CodeRequired, nil, i18n.VehiclePlateInvalid)
+		return
+	}
+	if _, ok := DetectPlateFormat(v.Plate, v.PlateCountry); !ok {
+		err.Add("plate", CodeInvalidFormat, v.Plate, i18n.VehiclePlateInvalid)
 	}
 }
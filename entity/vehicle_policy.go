@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// Policies deciding what happens to the vehicles of a deleted driver.
+const (
+	// VehiclePolicyRestrict refuses to delete a driver with vehicles.
+	VehiclePolicyRestrict string = "restrict"
+	// VehiclePolicyUnassign deletes the driver and keeps its vehicles
+	// without a driver.
+	VehiclePolicyUnassign string = "unassign"
+	// VehiclePolicyCascade deletes the driver along with its vehicles.
+	VehiclePolicyCascade string = "cascade"
+)
+
+// ValidateVehiclePolicy reports whether policy is one of the vehicle policies.
+func ValidateVehiclePolicy(policy string) error {
+	switch policy {
+	case VehiclePolicyRestrict, VehiclePolicyUnassign, VehiclePolicyCascade:
+		return nil
+	}
+	policies := strings.Join([]string{VehiclePolicyRestrict, VehiclePolicyUnassign, VehiclePolicyCascade}, ", ")
+	return NewErrorInvalidField("vehicles", CodeInvalid, policy, i18n.VehiclePolicyInvalid, policies)
+}
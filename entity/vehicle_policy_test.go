@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVehiclePolicy(t *testing.T) {
+	for _, policy := range []string{VehiclePolicyRestrict, VehiclePolicyUnassign, VehiclePolicyCascade} {
+		assert.Nil(t, ValidateVehiclePolicy(policy))
+	}
+
+	err := ValidateVehiclePolicy("orphan")
+	assert.EqualError(t, err, "vehicles must be one of: restrict, unassign, cascade")
+}
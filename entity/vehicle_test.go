@@ -7,10 +7,11 @@ import (
 )
 
 func TestVehicle_Validate(t *testing.T) {
+	driverId := uint(1)
 	tests := []struct {
 		name    string
 		vehicle *Vehicle
-		want    error
+		want    []string
 		wantErr bool
 	}{
 		{
@@ -19,21 +20,49 @@ func TestVehicle_Validate(t *testing.T) {
 				Brand:        "Toyota",
 				VehicleModel: "Camry",
 				Year:         2022,
+				Class:        "car",
 				Plate:        "ABC-1234",
-				DriverID:     1,
+				DriverID:     &driverId,
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should return nil for mercosul plate",
+			vehicle: &Vehicle{
+				Brand:        "Volvo",
+				VehicleModel: "FH 540",
+				Year:         2022,
+				Class:        "car",
+				Plate:        "abc1d23",
+				DriverID:     &driverId,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return plate is invalid for another country",
+			vehicle: &Vehicle{
+				Brand:        "Volvo",
+				VehicleModel: "FH 540",
+				Year:         2022,
+				Class:        "car",
+				Plate:        "ABC1D23",
+				PlateCountry: PlateCountryArgentina,
+				DriverID:     &driverId,
+			},
+			want:    []string{"vehicle plate is invalid"},
+			wantErr: true,
+		},
 		{
 			name: "Should return brand is invalid",
 			vehicle: &Vehicle{
 				Brand:        "T",
 				VehicleModel: "Camry",
 				Year:         2022,
+				Class:        "car",
 				Plate:        "ABC-1234",
-				DriverID:     1,
+				DriverID:     &driverId,
 			},
-			want:    &ErrorInvalidField{Message: []string{"vehicle brand is invalid"}},
+			want:    []string{"vehicle brand is invalid"},
 			wantErr: true,
 		},
 		{
@@ -42,10 +71,11 @@ func TestVehicle_Validate(t *testing.T) {
 				Brand:        "Toyota",
 				VehicleModel: "C",
 				Year:         2022,
+				Class:        "car",
 				Plate:        "ABC-1234",
-				DriverID:     1,
+				DriverID:     &driverId,
 			},
-			want:    &ErrorInvalidField{Message: []string{"vehicle model is invalid"}},
+			want:    []string{"vehicle model is invalid"},
 			wantErr: true,
 		},
 		{
@@ -54,10 +84,11 @@ func TestVehicle_Validate(t *testing.T) {
 				Brand:        "Toyota",
 				VehicleModel: "Camry",
 				Year:         1886,
+				Class:        "car",
 				Plate:        "ABC-1234",
-				DriverID:     1,
+				DriverID:     &driverId,
 			},
-			want:    &ErrorInvalidField{Message: []string{"vehicle year is invalid"}},
+			want:    []string{"vehicle year is invalid"},
 			wantErr: true,
 		},
 		{
@@ -66,10 +97,11 @@ func TestVehicle_Validate(t *testing.T) {
 				Brand:        "Toyota",
 				VehicleModel: "Camry",
 				Year:         2022,
-				Plate:        "ABC1234",
-				DriverID:     1,
+				Class:        "car",
+				Plate:        "AB-12345",
+				DriverID:     &driverId,
 			},
-			want:    &ErrorInvalidField{Message: []string{"vehicle plate is invalid"}},
+			want:    []string{"vehicle plate is invalid"},
 			wantErr: true,
 		},
 		{
@@ -78,16 +110,15 @@ func TestVehicle_Validate(t *testing.T) {
 				Brand:        "T",
 				VehicleModel: "C",
 				Year:         1886,
-				Plate:        "ABC1234",
-				DriverID:     1,
+				Class:        "car",
+				Plate:        "AB-12345",
+				DriverID:     &driverId,
 			},
-			want: &ErrorInvalidField{
-				Message: []string{
-					"vehicle brand is invalid",
-					"vehicle model is invalid",
-					"vehicle year is invalid",
-					"vehicle plate is invalid",
-				},
+			want: []string{
+				"vehicle brand is invalid",
+				"vehicle model is invalid",
+				"vehicle year is invalid",
+				"vehicle plate is invalid",
 			},
 			wantErr: true,
 		},
@@ -104,3 +135,50 @@ func TestVehicle_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestVehicle_ValidateChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		vehicle Vehicle
+		current Vehicle
+		wantErr bool
+	}{
+		{
+			name:    "Should accept empty class when current vehicle has no class",
+			vehicle: Vehicle{Brand: "Toyota", VehicleModel: "Camry", Year: 2022, Plate: "ABC1234"},
+			current: Vehicle{Brand: "Volvo", VehicleModel: "Camry", Year: 2022, Plate: "ABC1234"},
+			wantErr: false,
+		},
+		{
+			name:    "Should return error when class is removed from current vehicle",
+			vehicle: Vehicle{Brand: "Toyota", VehicleModel: "Camry", Year: 2022, Plate: "ABC1234"},
+			current: Vehicle{Brand: "Toyota", VehicleModel: "Camry", Year: 2022, Class: "car", Plate: "ABC1234"},
+			wantErr: true,
+		},
+		{
+			name:    "Should return error for invalid class when current vehicle has no class",
+			vehicle: Vehicle{Brand: "Toyota", VehicleModel: "Camry", Year: 2022, Class: "boat", Plate: "ABC1234"},
+			current: Vehicle{Brand: "Toyota", VehicleModel: "Camry", Year: 2022, Plate: "ABC1234"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.vehicle.ValidateChange(tt.current)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVehicle_NormalizePlate(t *testing.T) {
+	vehicle := &Vehicle{Plate: " abc-1d23 "}
+	vehicle.NormalizePlate()
+	assert.Equal(t, "ABC1D23", vehicle.Plate)
+	assert.Equal(t, PlateCountryBrazil, vehicle.PlateCountry)
+	assert.Equal(t, PlateFormatMercosul, vehicle.PlateFormat)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+)
+
+// headerActor identifies who sent a request, recorded as the actor of the
+// changes it makes. The API is expected to run behind a gateway that
+// authenticates the caller and sets it. headerAdminToken carries the admin
+// token the server was configured with.
+const (
+	headerActor      = "X-Actor"
+	headerAdminToken = "X-Admin-Token"
+)
+
+// actorRoleKey is the context key of the role granted to a request by
+// AdminHandler.
+type actorRoleKey struct{}
+
+// actor returns who sent the request, or the anonymous actor when the
+// request does not say. The role is never read from the request itself, only
+// the one granted by AdminHandler.
+func actor(r *http.Request) entity.Actor {
+	role, _ := r.Context().Value(actorRoleKey{}).(string)
+	return entity.NewActor(strings.TrimSpace(r.Header.Get(headerActor)), role)
+}
+
+// AdminHandler grants the admin role to the requests carrying Token, the
+// admin token configured on the server (ADMIN_TOKEN).
+type AdminHandler struct {
+	Token string
+}
+
+// Middleware serves next as an admin when the X-Admin-Token header matches
+// Token, and as a regular actor otherwise. With an empty Token nobody is an
+// admin.
+func (ah AdminHandler) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(headerAdminToken)
+		if ah.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(ah.Token)) == 1 {
+			r = r.WithContext(context.WithValue(r.Context(), actorRoleKey{}, entity.RoleAdmin))
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_actor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/drivers/1", nil)
+	assert.Equal(t, entity.Actor{Name: entity.AnonymousActor}, actor(req))
+
+	req.Header.Set(headerActor, " maria@gobrax.com ")
+	assert.Equal(t, entity.Actor{Name: "maria@gobrax.com"}, actor(req))
+
+	req.Header.Set("X-Actor-Role", entity.RoleAdmin)
+	assert.False(t, actor(req).IsAdmin())
+}
+
+func TestAdminHandler_Middleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		header    string
+		wantAdmin bool
+	}{
+		{name: "Should grant the admin role when the token matches", token: "secret", header: "secret", wantAdmin: true},
+		{name: "Should not grant the admin role when the token does not match", token: "secret", header: "other", wantAdmin: false},
+		{name: "Should not grant the admin role without the token", token: "secret", header: "", wantAdmin: false},
+		{name: "Should not grant the admin role when no token is configured", token: "", header: "", wantAdmin: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/drivers/1/purge", nil)
+			req.Header.Set(headerAdminToken, tt.header)
+
+			var got entity.Actor
+			AdminHandler{Token: tt.token}.Middleware(func(w http.ResponseWriter, r *http.Request) {
+				got = actor(r)
+			})(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.wantAdmin, got.IsAdmin())
+		})
+	}
+}
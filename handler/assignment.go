@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+)
+
+type assignmentRequest struct {
+	DriverId int `json:"driverId"`
+}
+
+type AssignmentHandler struct {
+	AssignmentUsecase usecase.AssignmentUsecase
+}
+
+func (ah AssignmentHandler) GetByVehicle(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	assignments, err := ah.AssignmentUsecase.GetByVehicle(vehicleId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAssignmentResponses(assignments))
+}
+
+func (ah AssignmentHandler) GetByDriver(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	assignments, err := ah.AssignmentUsecase.GetByDriver(driverId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAssignmentResponses(assignments))
+}
+
+// GetByPlateAt answers who was driving the vehicle with a plate at a moment,
+// e.g. GET /assignments?plate=ABC1D23&at=2024-05-01T10:00:00Z.
+func (ah AssignmentHandler) GetByPlateAt(w http.ResponseWriter, r *http.Request) {
+	at := time.Now()
+	if value := r.URL.Query().Get("at"); value != "" {
+		var err error
+		at, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			errorHandler(w, r, entity.NewErrorInvalidField("at", entity.CodeInvalidFormat, value, i18n.MustBeDateTime, "at"))
+			return
+		}
+	}
+
+	assignment, err := ah.AssignmentUsecase.GetByPlateAt(r.URL.Query().Get("plate"), at)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAssignmentResponse(assignment))
+}
+
+func (ah AssignmentHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	assignmentReq := new(assignmentRequest)
+	err = json.NewDecoder(r.Body).Decode(assignmentReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	err = ah.AssignmentUsecase.Assign(vehicleId, assignmentReq.DriverId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (ah AssignmentHandler) Unassign(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	err = ah.AssignmentUsecase.Unassign(vehicleId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ah AssignmentHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	assignmentReq := new(assignmentRequest)
+	err = json.NewDecoder(r.Body).Decode(assignmentReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	err = ah.AssignmentUsecase.Transfer(vehicleId, assignmentReq.DriverId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
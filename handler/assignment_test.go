@@ -0,0 +1,469 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestAssignmentHandler_GetByVehicle(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathValue string
+		setup     func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "Should return the assignments",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByVehicle(2).Return([]*entity.Assignment{{VehicleID: 2, DriverID: 2}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `"vehicleId":2,"driverId":2`,
+		},
+		{
+			name:      "Should return bad request error when vehicleId is not a number",
+			pathValue: "abc",
+			setup:     func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:  http.StatusBadRequest,
+			wantBody:  "vehicleId must be a number",
+		},
+		{
+			name:      "Should return not found error",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByVehicle(2).Return(nil, usecase.ErrVehicleNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: usecase.ErrVehicleNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/vehicles/{id}/assignments", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.GetByVehicle(respWriter, req)
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestAssignmentHandler_GetByDriver(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathValue string
+		setup     func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "Should return the assignments",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByDriver(2).Return([]*entity.Assignment{{VehicleID: 2, DriverID: 2}}, nil)
+			},
+			wantCode: http.StatusOK,
+			wantBody: `"vehicleId":2,"driverId":2`,
+		},
+		{
+			name:      "Should return bad request error when driverId is not a number",
+			pathValue: "abc",
+			setup:     func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:  http.StatusBadRequest,
+			wantBody:  "driverId must be a number",
+		},
+		{
+			name:      "Should return not found error",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByDriver(2).Return(nil, usecase.ErrDriverNotFound)
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: usecase.ErrDriverNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/{id}/assignments", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.GetByDriver(respWriter, req)
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestAssignmentHandler_GetByPlateAt(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		setup        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:  "Should return the assignment active at the moment",
+			query: "?plate=ABC1D23&at=2024-05-01T10:00:00Z",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByPlateAt("ABC1D23", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)).Return(new(entity.Assignment), nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:         "Should return bad request error when at is invalid",
+			query:        "?plate=ABC1D23&at=yesterday",
+			setup:        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "at must be a RFC 3339 date time",
+		},
+		{
+			name:  "Should return not found error",
+			query: "?plate=ABC1D23",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().GetByPlateAt("ABC1D23", gomock.Any()).Return(nil, usecase.ErrAssignmentNotFound)
+			},
+			wantCode:     http.StatusNotFound,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrAssignmentNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/assignments"+tt.query, nil)
+			respWriter := httptest.NewRecorder()
+
+			ah.GetByPlateAt(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			}
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
+
+func TestAssignmentHandler_Assign(t *testing.T) {
+	tests := []struct {
+		name         string
+		pathValue    string
+		requestBody  string
+		setup        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:        "Should assign vehicle to driver",
+			pathValue:   "2",
+			requestBody: `{"driverId": 1}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Assign(2, 1, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusCreated,
+			wantError: false,
+		},
+		{
+			name:         "Should return bad request error when vehicleId is not a number",
+			pathValue:    "abc",
+			requestBody:  `{"driverId": 1}`,
+			setup:        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "vehicleId must be a number",
+		},
+		{
+			name:         "Should return bad request error when request body is invalid",
+			pathValue:    "2",
+			requestBody:  `{"driverId"}`,
+			setup:        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "invalid request body",
+		},
+		{
+			name:        "Should return conflict error when vehicle is already assigned",
+			pathValue:   "2",
+			requestBody: `{"driverId": 1}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Assign(2, 1, gomock.Any()).Return(usecase.ErrVehicleAlreadyAssigned)
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrVehicleAlreadyAssigned.Error(),
+		},
+		{
+			name:        "Should return unprocessable entity error when license is incompatible",
+			pathValue:   "2",
+			requestBody: `{"driverId": 1}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Assign(2, 1, gomock.Any()).Return(&entity.ErrorIncompatibleLicense{
+					LicenseType:  "B",
+					VehicleClass: "bus",
+					Plate:        "ABC1234",
+				})
+			},
+			wantCode:     http.StatusUnprocessableEntity,
+			wantError:    true,
+			wantErrorMsg: "does not allow driving vehicle ABC1234",
+		},
+		{
+			name:        "Should return not found error when driver does not exist",
+			pathValue:   "2",
+			requestBody: `{"driverId": 1}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Assign(2, 1, gomock.Any()).Return(usecase.ErrDriverNotFound)
+			},
+			wantCode:     http.StatusNotFound,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrDriverNotFound.Error(),
+		},
+		{
+			name:        "Should return internal server error",
+			pathValue:   "2",
+			requestBody: `{"driverId": 1}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Assign(2, 1, gomock.Any()).Return(fmt.Errorf("some error occurred"))
+			},
+			wantCode:     http.StatusInternalServerError,
+			wantError:    true,
+			wantErrorMsg: "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/assignments", strings.NewReader(tt.requestBody))
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.Assign(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			}
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
+
+func TestAssignmentHandler_Unassign(t *testing.T) {
+	tests := []struct {
+		name         string
+		pathValue    string
+		setup        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:      "Should unassign vehicle",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Unassign(2, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusNoContent,
+			wantError: false,
+		},
+		{
+			name:      "Should return conflict error when vehicle is not assigned",
+			pathValue: "2",
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Unassign(2, gomock.Any()).Return(usecase.ErrVehicleNotAssigned)
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrVehicleNotAssigned.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/vehicles/{id}/assignments", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.Unassign(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			}
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
+
+func TestAssignmentHandler_Transfer(t *testing.T) {
+	tests := []struct {
+		name         string
+		pathValue    string
+		requestBody  string
+		setup        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:        "Should transfer vehicle to another driver",
+			pathValue:   "2",
+			requestBody: `{"driverId": 3}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Transfer(2, 3, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:         "Should return bad request error when request body is invalid",
+			pathValue:    "2",
+			requestBody:  `{"driverId"}`,
+			setup:        func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "invalid request body",
+		},
+		{
+			name:        "Should return conflict error when vehicle is not assigned",
+			pathValue:   "2",
+			requestBody: `{"driverId": 3}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Transfer(2, 3, gomock.Any()).Return(usecase.ErrVehicleNotAssigned)
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrVehicleNotAssigned.Error(),
+		},
+		{
+			name:        "Should return conflict error when vehicle driver changed concurrently",
+			pathValue:   "2",
+			requestBody: `{"driverId": 3}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Transfer(2, 3, gomock.Any()).Return(usecase.ErrVehicleDriverChanged)
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrVehicleDriverChanged.Error(),
+		},
+		{
+			name:        "Should return not found error when driver does not exist",
+			pathValue:   "2",
+			requestBody: `{"driverId": 3}`,
+			setup: func(mockAssignmentUsecase *usecase.MockAssignmentUsecase) {
+				mockAssignmentUsecase.EXPECT().Transfer(2, 3, gomock.Any()).Return(usecase.ErrDriverNotFound)
+			},
+			wantCode:     http.StatusNotFound,
+			wantError:    true,
+			wantErrorMsg: usecase.ErrDriverNotFound.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentUsecase := usecase.NewMockAssignmentUsecase(ctrl)
+			tt.setup(mockAssignmentUsecase)
+
+			ah := AssignmentHandler{
+				AssignmentUsecase: mockAssignmentUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/transfer", strings.NewReader(tt.requestBody))
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.Transfer(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			}
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
+
+func TestAssignmentHandler_TransferDriverChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+	mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+	mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+	uow := repository.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repository.Repositories) error) error {
+		return fn(repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo})
+	})
+
+	// the vehicle was read with driver 1, but the locked row has another driver
+	driverId := uint(1)
+	mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, Class: entity.VehicleClassCar, DriverID: &driverId}, nil)
+	mockDriverRepo.EXPECT().GetById(3, false).Return(&entity.Driver{Model: gorm.Model{ID: 3}, Status: entity.DriverStatusActive, LicenseType: entity.LicenseTypeB}, nil)
+	mockAssignmentRepo.EXPECT().Assign(gomock.Any(), uint(3), gomock.Any()).Return(repository.ErrVehicleDriverChanged)
+
+	ah := AssignmentHandler{
+		AssignmentUsecase: usecase.NewAssignmentUsecase(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo, uow),
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/transfer", strings.NewReader(`{"driverId": 3}`))
+	req.SetPathValue("id", "2")
+	respWriter := httptest.NewRecorder()
+
+	ah.Transfer(respWriter, req)
+	assert.Equal(t, http.StatusConflict, respWriter.Code)
+	assert.Contains(t, respWriter.Body.String(), repository.ErrVehicleDriverChanged.Error())
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+)
+
+type AuditHandler struct {
+	AuditUsecase usecase.AuditUsecase
+}
+
+func (ah AuditHandler) GetByDriver(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	entries, err := ah.AuditUsecase.GetByDriver(driverId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAuditEntryResponses(entries))
+}
+
+func (ah AuditHandler) GetByVehicle(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	entries, err := ah.AuditUsecase.GetByVehicle(vehicleId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newAuditEntryResponses(entries))
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestAuditHandler_GetByDriver(t *testing.T) {
+	createdAt := time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		pathValue  string
+		setup      func(mockAuditUsecase *usecase.MockAuditUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:      "Should return the history of the driver",
+			pathValue: "1",
+			setup: func(mockAuditUsecase *usecase.MockAuditUsecase) {
+				mockAuditUsecase.EXPECT().GetByDriver(1).Return([]*entity.AuditEntry{{
+					ID: 7, EntityType: entity.AuditEntityDriver, EntityID: 1, Operation: entity.AuditOperationUpdate,
+					Actor: "maria", Changes: []entity.AuditChange{{Field: "licenseType", Before: "B", After: "CE"}},
+					CreatedAt: createdAt,
+				}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `[{"id":7,"entity":"driver","entityId":1,"operation":"update","actor":"maria",` +
+				`"changes":[{"field":"licenseType","before":"B","after":"CE"}],"createdAt":"2024-05-02T09:30:00Z"}]`,
+		},
+		{
+			name:       "Should return bad request when id is not a number",
+			pathValue:  "abc",
+			setup:      func(mockAuditUsecase *usecase.MockAuditUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "driverId must be a number",
+		},
+		{
+			name:      "Should return not found",
+			pathValue: "1",
+			setup: func(mockAuditUsecase *usecase.MockAuditUsecase) {
+				mockAuditUsecase.EXPECT().GetByDriver(1).Return(nil, usecase.ErrDriverNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "driver not found",
+		},
+		{
+			name:      "Should return internal server error",
+			pathValue: "1",
+			setup: func(mockAuditUsecase *usecase.MockAuditUsecase) {
+				mockAuditUsecase.EXPECT().GetByDriver(1).Return(nil, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAuditUsecase := usecase.NewMockAuditUsecase(ctrl)
+			tt.setup(mockAuditUsecase)
+
+			ah := AuditHandler{AuditUsecase: mockAuditUsecase}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/"+tt.pathValue+"/history", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			ah.GetByDriver(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, tt.wantBody, respWriter.Body.String())
+				return
+			}
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// date is a calendar day sent as "2006-01-02" in requests.
+type date struct {
+	time.Time
+	// invalid keeps a value that is not a date, so the request can report it
+	// with the name of its field.
+	invalid string
+}
+
+func (d *date) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	d.Time, err = time.Parse(time.DateOnly, value)
+	if err != nil {
+		d.invalid = value
+		return nil
+	}
+	return nil
+}
+
+func (d date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(time.DateOnly))
+}
+
+// newDate converts a time of the entities to the calendar day sent in responses.
+func newDate(t *time.Time) *date {
+	if t == nil {
+		return nil
+	}
+	return &date{Time: *t}
+}
+
+func (d *date) toTime() *time.Time {
+	if d == nil {
+		return nil
+	}
+	return &d.Time
+}
+
+// parseDays parses a duration that also accepts days, e.g. "30d" or "12h".
+func parseDays(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -2,13 +2,11 @@ package handler
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
-	"reflect"
 	"strconv"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/usecase"
 )
 
@@ -17,8 +15,55 @@ type driverRequest struct {
 	LastName    string `json:"lastName"`
 	Email       string `json:"email"`
 	Phone       string `json:"phone"`
+	CPF         string `json:"cpf"`
 	License     string `json:"license"`
 	LicenseType string `json:"licenseType"`
+	// dates are sent as YYYY-MM-DD
+	LicenseIssuedAt  *date `json:"licenseIssuedAt"`
+	LicenseExpiresAt *date `json:"licenseExpiresAt"`
+	FirstLicenseAt   *date `json:"firstLicenseAt"`
+	// the id of the driver in the system it is mastered in, e.g. the HR system
+	ExternalSource *string `json:"externalSource"`
+	ExternalID     *string `json:"externalId"`
+}
+
+func (dr driverRequest) toEntity() *entity.Driver {
+	return &entity.Driver{
+		Name:             dr.Name,
+		LastName:         dr.LastName,
+		Email:            dr.Email,
+		Phone:            dr.Phone,
+		CPF:              dr.CPF,
+		License:          dr.License,
+		LicenseType:      dr.LicenseType,
+		LicenseIssuedAt:  dr.LicenseIssuedAt.toTime(),
+		LicenseExpiresAt: dr.LicenseExpiresAt.toTime(),
+		FirstLicenseAt:   dr.FirstLicenseAt.toTime(),
+		ExternalSource:   dr.ExternalSource,
+		ExternalID:       dr.ExternalID,
+	}
+}
+
+// validate reports the dates that are not in the format YYYY-MM-DD.
+func (dr driverRequest) validate() error {
+	err := new(entity.ErrorInvalidField)
+	dates := []struct {
+		field string
+		value *date
+	}{
+		{"licenseIssuedAt", dr.LicenseIssuedAt},
+		{"licenseExpiresAt", dr.LicenseExpiresAt},
+		{"firstLicenseAt", dr.FirstLicenseAt},
+	}
+	for _, d := range dates {
+		if d.value != nil && d.value.invalid != "" {
+			err.Add(d.field, entity.CodeInvalidFormat, d.value.invalid, i18n.MustBeDate, d.field)
+		}
+	}
+	if len(err.Errors) > 0 {
+		return err
+	}
+	return nil
 }
 
 type DriverHandler struct {
@@ -26,18 +71,99 @@ type DriverHandler struct {
 }
 
 func (dh DriverHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	drivers, err := dh.DriverUsecase.GetAll()
+	opts, err := parseQueryOptions(r)
 	if err != nil {
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
-	json.NewEncoder(w).Encode(drivers)
+
+	drivers, total, err := dh.DriverUsecase.GetAll(opts)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	var lastId uint
+	if len(drivers) > 0 {
+		lastId = drivers[len(drivers)-1].ID
+	}
+	json.NewEncoder(w).Encode(newListResponse(newDriverResponses(drivers), len(drivers), lastId, total, opts))
+}
+
+// Export writes the drivers matching the filters of the listing as a CSV,
+// XLSX or NDJSON file, read from the repository a batch at a time.
+func (dh DriverHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := parseExportFormat(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	opts, err := parseQueryOptions(r, "format")
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	export := newExport(w, format, "drivers", driverExportColumns)
+	err = dh.DriverUsecase.Export(opts, func(drivers []*entity.Driver) error {
+		for _, driver := range drivers {
+			resp := newDriverResponse(driver)
+			err := export.write(resp.record(), resp)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = export.close()
+	}
+	if err != nil {
+		export.fail(r, err)
+	}
+}
+
+// GetByExternalId returns the driver with the id of the system it is mastered in.
+func (dh DriverHandler) GetByExternalId(w http.ResponseWriter, r *http.Request) {
+	driver, err := dh.DriverUsecase.GetByExternalId(r.PathValue("source"), r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	if driver == nil {
+		errorHandler(w, r, usecase.ErrDriverNotFound)
+		return
+	}
+	w.Header().Set("ETag", etag(driver.Version))
+	json.NewEncoder(w).Encode(newDriverResponse(driver))
+}
+
+// ExternalId lets the path value param reference the driver by its external
+// id as source:id, e.g. /drivers/hr:1234, before calling next.
+func (dh DriverHandler) ExternalId(param string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := resolveExternalRef(r, param, "driverId", func(source string, externalId string) (uint, error) {
+			driver, err := dh.DriverUsecase.GetByExternalId(source, externalId)
+			if err != nil {
+				return 0, err
+			}
+			if driver == nil {
+				return 0, usecase.ErrDriverNotFound
+			}
+			return driver.ID, nil
+		})
+		if err != nil {
+			errorHandler(w, r, err)
+			return
+		}
+		next(w, r)
+	}
 }
 
 func (dh DriverHandler) GetById(w http.ResponseWriter, r *http.Request) {
 	driverId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("driverId must be a number"))
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
 		return
 	}
 
@@ -48,121 +174,245 @@ func (dh DriverHandler) GetById(w http.ResponseWriter, r *http.Request) {
 
 	includeVehicleBool, err := strconv.ParseBool(includeVehicle)
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("includeVehicle must be a boolean"))
+		errorHandler(w, r, entity.NewErrorInvalidField("includeVehicle", entity.CodeInvalidFormat, includeVehicle, i18n.MustBeBoolean, "includeVehicle"))
 		return
 	}
 
 	driver, err := dh.DriverUsecase.GetById(driverId, includeVehicleBool)
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
 	if driver == nil {
-		errorHandler(w, http.StatusNotFound, fmt.Errorf("driver not found"))
+		errorHandler(w, r, usecase.ErrDriverNotFound)
 		return
 	}
-	json.NewEncoder(w).Encode(driver)
+	w.Header().Set("ETag", etag(driver.Version))
+	json.NewEncoder(w).Encode(newDriverResponse(driver))
+}
+
+func (dh DriverHandler) GetExpiring(w http.ResponseWriter, r *http.Request) {
+	within := r.URL.Query().Get("within")
+	if within == "" {
+		within = "30d"
+	}
+
+	duration, err := parseDays(within)
+	if err != nil {
+		errorHandler(w, r, entity.NewErrorInvalidField("within", entity.CodeInvalidFormat, within, i18n.MustBeDuration, "within"))
+		return
+	}
+
+	drivers, err := dh.DriverUsecase.GetExpiring(duration)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newDriverResponses(drivers))
 }
 
 func (dh DriverHandler) Create(w http.ResponseWriter, r *http.Request) {
 	driverReq := new(driverRequest)
 	err := json.NewDecoder(r.Body).Decode(driverReq)
 	if err != nil {
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, invalidBody(err))
 		return
 	}
-
-	driver := &entity.Driver{
-		Name:        driverReq.Name,
-		LastName:    driverReq.LastName,
-		Email:       driverReq.Email,
-		Phone:       driverReq.Phone,
-		License:     driverReq.License,
-		LicenseType: driverReq.LicenseType,
+	err = driverReq.validate()
+	if err != nil {
+		errorHandler(w, r, err)
+		return
 	}
 
-	err = dh.DriverUsecase.Create(driver)
+	err = dh.DriverUsecase.Create(driverReq.toEntity(), actor(r))
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 }
 
+// Import creates the drivers of a CSV or XLSX file, see readImportFile.
+func (dh DriverHandler) Import(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseImportOptions(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	rows, err := readImportFile(w, r, driverImportColumns)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	driverRows := make([]entity.DriverImportRow, len(rows))
+	for i, row := range rows {
+		driverRows[i] = row.toDriver()
+	}
+	report, err := dh.DriverUsecase.ImportDrivers(driverRows, opts, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	writeImportReport(w, r, report)
+}
+
 func (dh DriverHandler) AddVehicle(w http.ResponseWriter, r *http.Request) {
 	driverId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("driverId must be a number"))
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
 		return
 	}
 
 	vehicleReq := new(vehicleRequest)
 	err = json.NewDecoder(r.Body).Decode(vehicleReq)
 	if err != nil {
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, invalidBody(err))
 		return
 	}
 
-	vehicle := &entity.Vehicle{
-		Plate:        vehicleReq.Plate,
-		Brand:        vehicleReq.Brand,
-		VehicleModel: vehicleReq.VehicleModel,
-		Year:         vehicleReq.Year,
-	}
-	err = dh.DriverUsecase.AddVehicle(driverId, vehicle)
+	err = dh.DriverUsecase.AddVehicle(driverId, vehicleReq.toEntity(), actor(r))
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 }
 
+func (dh DriverHandler) AttachVehicle(w http.ResponseWriter, r *http.Request) {
+	driverId, vehicleId, err := driverVehiclePath(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+
+	err = dh.DriverUsecase.AttachVehicle(driverId, vehicleId, version, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (dh DriverHandler) DetachVehicle(w http.ResponseWriter, r *http.Request) {
+	driverId, vehicleId, err := driverVehiclePath(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+
+	err = dh.DriverUsecase.DetachVehicle(driverId, vehicleId, version, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (dh DriverHandler) TransferVehicle(w http.ResponseWriter, r *http.Request) {
+	driverId, vehicleId, err := driverVehiclePath(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	assignmentReq := new(assignmentRequest)
+	err = json.NewDecoder(r.Body).Decode(assignmentReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	err = dh.DriverUsecase.TransferVehicle(driverId, vehicleId, assignmentReq.DriverId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (dh DriverHandler) Update(w http.ResponseWriter, r *http.Request) {
 	driverId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("driverId must be a number"))
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	patch, err := readPatch(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+	patch.Version = version
+
+	err = dh.DriverUsecase.Update(driverId, patch, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Replace overwrites the driver with the request body. An unknown numeric id
+// returns 404 Not Found; only a driver referenced by its external id
+// (source:id) is created when it does not exist.
+func (dh DriverHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	ref, err := parsePathRef(r.PathValue("id"), "driverId")
+	if err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
 	driverReq := new(driverRequest)
 	err = json.NewDecoder(r.Body).Decode(driverReq)
 	if err != nil {
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+	err = driverReq.validate()
+	if err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 
-	driver := &entity.Driver{
-		Name:        driverReq.Name,
-		LastName:    driverReq.LastName,
-		Email:       driverReq.Email,
-		Phone:       driverReq.Phone,
-		License:     driverReq.License,
-		LicenseType: driverReq.LicenseType,
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
 	}
+	driver := driverReq.toEntity()
+	driver.Version = version
 
-	err = dh.DriverUsecase.Update(driverId, driver)
+	var created bool
+	if ref.external() {
+		created, err = dh.DriverUsecase.ReplaceByExternalId(ref.source, ref.externalId, driver, actor(r))
+	} else {
+		created, err = dh.DriverUsecase.Replace(ref.id, driver, actor(r))
+	}
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		if errors.Is(err, usecase.ErrDriverNotFound) {
-			errorHandler(w, http.StatusNotFound, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
+		return
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -171,18 +421,72 @@ func (dh DriverHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (dh DriverHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	driverId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("driverId must be a number"))
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
 		return
 	}
 
-	err = dh.DriverUsecase.Delete(driverId)
+	// vehicles decides what happens to the vehicles of the driver
+	policy := r.URL.Query().Get("vehicles")
+	if policy == "" {
+		policy = entity.VehiclePolicyRestrict
+	}
+
+	err = dh.DriverUsecase.Delete(driverId, version, policy, actor(r))
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore undeletes a deleted driver and returns it.
+func (dh DriverHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	driver, err := dh.DriverUsecase.Restore(driverId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(driver.Version))
+	json.NewEncoder(w).Encode(newDriverResponse(driver))
+}
+
+// Purge removes a deleted driver for good. It is restricted to admins.
+func (dh DriverHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	err = dh.DriverUsecase.Purge(driverId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// driverVehiclePath reads the ids of /drivers/{id}/vehicles/{vehicleId}.
+func driverVehiclePath(r *http.Request) (int, int, error) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return 0, 0, invalidNumber("driverId", r.PathValue("id"))
+	}
+	vehicleId, err := strconv.Atoi(r.PathValue("vehicleId"))
+	if err != nil {
+		return 0, 0, invalidNumber("vehicleId", r.PathValue("vehicleId"))
+	}
+	return driverId, vehicleId, nil
+}
@@ -7,32 +7,88 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/usecase"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestDriverHandler_GetAll(t *testing.T) {
 	tests := []struct {
-		name    string
-		setup   func(mockDriverUsecase *usecase.MockDriverUsecase)
-		wantErr bool
+		name       string
+		query      string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantBody   string
 	}{
 		{
 			name: "Should return all drivers",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().GetAll().Return(make([]*entity.Driver, 0), nil)
+				mockDriverUsecase.EXPECT().GetAll(entity.NewQueryOptions()).Return(make([]*entity.Driver, 0), int64(0), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":0`,
+		},
+		{
+			name:  "Should return next cursor when there are more drivers",
+			query: "?pageSize=1&licenseType=CE",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     1,
+					PageSize: 1,
+					Filters:  []entity.Filter{{Field: "licenseType", Operator: entity.FilterOperatorEqual, Value: "CE"}},
+				}).Return([]*entity.Driver{{Model: gorm.Model{ID: 7}}}, int64(3), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"nextCursor":"7"`,
+		},
+		{
+			name:  "Should return the deleted drivers",
+			query: "?deleted=true",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     1,
+					PageSize: entity.DefaultPageSize,
+					Deleted:  true,
+				}).Return([]*entity.Driver{{Model: gorm.Model{ID: 7}}}, int64(1), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"id":7`,
+		},
+		{
+			name:       "Should return bad request error when deleted is not a boolean",
+			query:      "?deleted=yes",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "deleted must be a boolean",
+		},
+		{
+			name:       "Should return bad request error when page is not a number",
+			query:      "?page=abc",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "page must be a number",
+		},
+		{
+			name:  "Should return bad request error when query options are invalid",
+			query: "?sort=unknown",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), entity.NewErrorInvalidField("sort", entity.CodeUnknownField, "unknown", "sort unknown is invalid"))
 			},
-			wantErr: false,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "sort unknown is invalid",
 		},
 		{
 			name: "Should return error",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().GetAll().Return(nil, fmt.Errorf("some error occurred"))
+				mockDriverUsecase.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), fmt.Errorf("some error occurred"))
 			},
-			wantErr: true,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
 		},
 	}
 	for _, tt := range tests {
@@ -45,15 +101,12 @@ func TestDriverHandler_GetAll(t *testing.T) {
 				DriverUsecase: mockDriverUsecase,
 			}
 
-			req := httptest.NewRequest(http.MethodGet, "/drivers", nil)
+			req := httptest.NewRequest(http.MethodGet, "/drivers"+tt.query, nil)
 			respWritter := httptest.NewRecorder()
 
 			dh.GetAll(respWritter, req)
-			if tt.wantErr {
-				assert.Equal(t, http.StatusInternalServerError, respWritter.Code)
-				return
-			}
-			assert.Equal(t, http.StatusOK, respWritter.Code)
+			assert.Contains(t, respWritter.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantStatus, respWritter.Code)
 		})
 	}
 }
@@ -111,9 +164,7 @@ func TestDriverHandler_GetById(t *testing.T) {
 			pathValue:           "0",
 			queryIncludeVehicle: "true",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().GetById(0, true).Return(nil, &entity.ErrorInvalidField{
-					Message: []string{"driverId is invalid"},
-				})
+				mockDriverUsecase.EXPECT().GetById(0, true).Return(nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, "driverId is invalid"))
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
@@ -155,6 +206,67 @@ func TestDriverHandler_GetById(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_GetExpiring(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantErrMsg string
+	}{
+		{
+			name: "Should return drivers expiring in 30 days by default",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetExpiring(30*24*time.Hour).Return(make([]*entity.Driver, 0), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "Should return drivers expiring within the given duration",
+			query: "?within=72h",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetExpiring(72*time.Hour).Return(make([]*entity.Driver, 0), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should return bad request error when within is invalid",
+			query:      "?within=abc",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantErrMsg: "within must be a duration like 30d",
+		},
+		{
+			name:  "Should return internal server error",
+			query: "?within=10d",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetExpiring(10*24*time.Hour).Return(nil, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantErrMsg: "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{
+				DriverUsecase: mockDriverUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/expiring"+tt.query, nil)
+			respWriter := httptest.NewRecorder()
+
+			dh.GetExpiring(respWriter, req)
+			assert.Contains(t, respWriter.Body.String(), tt.wantErrMsg)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+		})
+	}
+}
+
 func TestDriverHandler_Create(t *testing.T) {
 	mockBody := `{"name": "John", "lastName": "Doe", "email": "john.doe@example.com", "phone": "1234567890", "license": "ABC123", "licenseType": "car"}`
 	tests := []struct {
@@ -169,36 +281,72 @@ func TestDriverHandler_Create(t *testing.T) {
 			name:        "Should create driver successfully",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Create(gomock.Any()).Return(nil)
+				mockDriverUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantStatus: http.StatusCreated,
 			wantError:  false,
 		},
+		{
+			name:        "Should create driver with license dates",
+			requestBody: `{"name": "John", "licenseIssuedAt": "2020-01-15", "licenseExpiresAt": "2030-01-15"}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				issuedAt := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+				expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+				mockDriverUsecase.EXPECT().Create(&entity.Driver{
+					Name:             "John",
+					LicenseIssuedAt:  &issuedAt,
+					LicenseExpiresAt: &expiresAt,
+				}, gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantError:  false,
+		},
+		{
+			name:        "Should return error when license date is not in the expected format",
+			requestBody: `{"name": "John", "licenseExpiresAt": "15/01/2030"}`,
+			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:  http.StatusBadRequest,
+			wantError:   true,
+			wantErrMsg:  `{"field":"licenseExpiresAt","code":"invalid_format","value":"15/01/2030","message":"licenseExpiresAt must be in the format YYYY-MM-DD"}`,
+		},
 		{
 			name:        "Should return bad request error when request body is not a valid JSON",
 			requestBody: `{"name"}`,
 			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
-			wantStatus:  http.StatusInternalServerError,
+			wantStatus:  http.StatusBadRequest,
 			wantError:   true,
-			wantErrMsg:  "{\"error\":\"invalid character '}' after object key\"}",
+			wantErrMsg:  "invalid request body: invalid character '}' after object key",
 		},
 		{
 			name:        "Should return bad request error when returns invalid field error",
 			requestBody: `{"name": "John", "lastName": "Doe", "email": "john.doe@example.com", "phone": "1234567890", "license": "21232123", "licenseType": "Y"}`,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Create(gomock.Any()).Return(&entity.ErrorInvalidField{
-					Message: []string{"license is invalid", "licenseType is invalid"},
+				mockDriverUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.ErrorInvalidField{
+					Errors: []entity.FieldError{
+						{Field: "license", Code: entity.CodeInvalid, Message: "license is invalid"},
+						{Field: "licenseType", Code: entity.CodeInvalid, Message: "licenseType is invalid"},
+					},
 				})
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
 			wantErrMsg: "license is invalid,licenseType is invalid",
 		},
+		{
+			name:        "Should return conflict error when email already exists",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.ErrorConflict{Entity: "driver", Field: "email"})
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: "driver email already exists",
+		},
 		{
 			name:        "Should return internal server error",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Create(gomock.Any()).Return(errors.New("some error occurred"))
+				mockDriverUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("some error occurred"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantError:  true,
@@ -244,7 +392,7 @@ func TestDriverHandler_AddVehicle(t *testing.T) {
 			pathValue:   "1",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any()).Return(nil)
+				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantStatus: http.StatusCreated,
 			wantError:  false,
@@ -263,29 +411,49 @@ func TestDriverHandler_AddVehicle(t *testing.T) {
 			pathValue:   "1",
 			requestBody: `{"plate"}`,
 			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
-			wantStatus:  http.StatusInternalServerError,
+			wantStatus:  http.StatusBadRequest,
 			wantError:   true,
-			wantErrMsg:  "{\"error\":\"invalid character '}' after object key\"}",
+			wantErrMsg:  "invalid request body: invalid character '}' after object key",
 		},
 		{
 			name:        "Should return bad request error when returns invalid field error",
 			pathValue:   "1",
 			requestBody: `{"plate": "ABC123", "brand": "T", "vehicleModel": "Camry", "year": 2022}`,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any()).Return(&entity.ErrorInvalidField{
-					Message: []string{"vehicle brand is invalid"},
-				})
+				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any(), gomock.Any()).Return(entity.NewErrorInvalidField("brand", entity.CodeTooShort, "T", i18n.VehicleBrandInvalid))
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
 			wantErrMsg: "vehicle brand is invalid",
 		},
+		{
+			name:        "Should return unprocessable entity error when driver license is expired",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any(), gomock.Any()).Return(usecase.ErrDriverLicenseExpired)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  true,
+			wantErrMsg: "driver license is expired",
+		},
+		{
+			name:        "Should return not found error when driver does not exist",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any(), gomock.Any()).Return(usecase.ErrDriverNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+			wantErrMsg: "driver not found",
+		},
 		{
 			name:        "Should return internal server error",
 			pathValue:   "1",
 			requestBody: `{"plate": "ABC123", "brand": "Toyota", "vehicleModel": "Camry", "year": 2022}`,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any()).Return(errors.New("some error occurred"))
+				mockDriverUsecase.EXPECT().AddVehicle(1, gomock.Any(), gomock.Any()).Return(errors.New("some error occurred"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantError:  true,
@@ -317,6 +485,318 @@ func TestDriverHandler_AddVehicle(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_AttachVehicle(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathValue      string
+		vehiclePathVal string
+		ifMatch        string
+		setup          func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus     int
+		wantError      bool
+		wantErrMsg     string
+	}{
+		{
+			name:           "Should attach vehicle successfully",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AttachVehicle(1, 2, uint(0), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:           "Should attach vehicle when If-Match matches",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"3"`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AttachVehicle(1, 2, uint(3), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:           "Should return bad request error when vehicleId is not a number",
+			pathValue:      "1",
+			vehiclePathVal: "abc",
+			setup:          func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:     http.StatusBadRequest,
+			wantError:      true,
+			wantErrMsg:     "vehicleId must be a number",
+		},
+		{
+			name:           "Should return not found error when vehicle does not exist",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AttachVehicle(1, 2, uint(0), gomock.Any()).Return(usecase.ErrVehicleNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleNotFound.Error(),
+		},
+		{
+			name:           "Should return conflict error when vehicle is attached to another driver",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AttachVehicle(1, 2, uint(0), gomock.Any()).Return(usecase.ErrVehicleAlreadyAssigned)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleAlreadyAssigned.Error(),
+		},
+		{
+			name:           "Should return precondition failed error when vehicle version does not match",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"3"`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().AttachVehicle(1, 2, uint(3), gomock.Any()).Return(usecase.ErrVersionMismatch)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVersionMismatch.Error(),
+		},
+		{
+			name:           "Should return precondition failed error when If-Match is invalid",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"abc"`,
+			setup:          func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:     http.StatusPreconditionFailed,
+			wantError:      true,
+			wantErrMsg:     usecase.ErrVersionMismatch.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{
+				DriverUsecase: mockDriverUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/drivers/{id}/vehicles/{vehicleId}", nil)
+			req.SetPathValue("id", tt.pathValue)
+			req.SetPathValue("vehicleId", tt.vehiclePathVal)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			respWriter := httptest.NewRecorder()
+
+			dh.AttachVehicle(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrMsg)
+			}
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+		})
+	}
+}
+
+func TestDriverHandler_DetachVehicle(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathValue      string
+		vehiclePathVal string
+		ifMatch        string
+		setup          func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus     int
+		wantError      bool
+		wantErrMsg     string
+	}{
+		{
+			name:           "Should detach vehicle successfully",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(0), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:           "Should detach vehicle when If-Match matches",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"3"`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(3), gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:           "Should return not found error when vehicle does not exist",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(0), gomock.Any()).Return(usecase.ErrVehicleNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleNotFound.Error(),
+		},
+		{
+			name:           "Should return not found error when vehicle is not attached to the driver",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(0), gomock.Any()).Return(usecase.ErrVehicleNotOwned)
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleNotOwned.Error(),
+		},
+		{
+			name:           "Should return conflict error when vehicle changed concurrently",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(0), gomock.Any()).Return(usecase.ErrVehicleDriverChanged)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleDriverChanged.Error(),
+		},
+		{
+			name:           "Should return precondition failed error when vehicle version does not match",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"3"`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().DetachVehicle(1, 2, uint(3), gomock.Any()).Return(usecase.ErrVersionMismatch)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVersionMismatch.Error(),
+		},
+		{
+			name:           "Should return precondition failed error when If-Match is invalid",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			ifMatch:        `"abc"`,
+			setup:          func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:     http.StatusPreconditionFailed,
+			wantError:      true,
+			wantErrMsg:     usecase.ErrVersionMismatch.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{
+				DriverUsecase: mockDriverUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/drivers/{id}/vehicles/{vehicleId}", nil)
+			req.SetPathValue("id", tt.pathValue)
+			req.SetPathValue("vehicleId", tt.vehiclePathVal)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			respWriter := httptest.NewRecorder()
+
+			dh.DetachVehicle(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrMsg)
+			}
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+		})
+	}
+}
+
+func TestDriverHandler_TransferVehicle(t *testing.T) {
+	tests := []struct {
+		name           string
+		pathValue      string
+		vehiclePathVal string
+		requestBody    string
+		setup          func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus     int
+		wantError      bool
+		wantErrMsg     string
+	}{
+		{
+			name:           "Should transfer vehicle successfully",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			requestBody:    `{"driverId": 3}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().TransferVehicle(1, 2, 3, gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:           "Should return bad request error when vehicleId is not a number",
+			pathValue:      "1",
+			vehiclePathVal: "abc",
+			requestBody:    `{"driverId": 3}`,
+			setup:          func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:     http.StatusBadRequest,
+			wantError:      true,
+			wantErrMsg:     "vehicleId must be a number",
+		},
+		{
+			name:           "Should return not found error when vehicle is not assigned to the driver",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			requestBody:    `{"driverId": 3}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().TransferVehicle(1, 2, 3, gomock.Any()).Return(usecase.ErrVehicleNotOwned)
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleNotOwned.Error(),
+		},
+		{
+			name:           "Should return conflict error when vehicle changed concurrently",
+			pathValue:      "1",
+			vehiclePathVal: "2",
+			requestBody:    `{"driverId": 3}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().TransferVehicle(1, 2, 3, gomock.Any()).Return(usecase.ErrVehicleDriverChanged)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: usecase.ErrVehicleDriverChanged.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{
+				DriverUsecase: mockDriverUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/drivers/{id}/vehicles/{vehicleId}/transfer", strings.NewReader(tt.requestBody))
+			req.SetPathValue("id", tt.pathValue)
+			req.SetPathValue("vehicleId", tt.vehiclePathVal)
+			respWriter := httptest.NewRecorder()
+
+			dh.TransferVehicle(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrMsg)
+			}
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+		})
+	}
+}
+
 func TestDriverHandler_Update(t *testing.T) {
 	mockBody := `{"name": "John", "lastName": "Doe", "email": "john.doe@example.com", "phone": "1234567890", "license": "ABC123", "licenseType": "B"}`
 	tests := []struct {
@@ -333,7 +813,7 @@ func TestDriverHandler_Update(t *testing.T) {
 			pathValue:   "1",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Update(1, gomock.Any()).Return(nil)
+				mockDriverUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(nil)
 			},
 			wantStatus: http.StatusOK,
 			wantError:  false,
@@ -352,29 +832,47 @@ func TestDriverHandler_Update(t *testing.T) {
 			pathValue:   "1",
 			requestBody: `{"name"}`,
 			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
-			wantStatus:  http.StatusInternalServerError,
+			wantStatus:  http.StatusBadRequest,
 			wantError:   true,
-			wantErrMsg:  "{\"error\":\"invalid character '}' after object key\"}",
+			wantErrMsg:  "invalid request body: invalid character '}' after object key",
 		},
 		{
 			name:        "Should return bad request error when returns invalid field error",
 			pathValue:   "1",
 			requestBody: `{"name": "John", "lastName": "Doe", "email": "john.doe@example.com", "phone": "1234567890", "license": "21232123", "licenseType": "Y"}`,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Update(1, gomock.Any()).Return(&entity.ErrorInvalidField{
-					Message: []string{"license is invalid", "licenseType is invalid"},
+				mockDriverUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(&entity.ErrorInvalidField{
+					Errors: []entity.FieldError{
+						{Field: "license", Code: entity.CodeInvalid, Message: "license is invalid"},
+						{Field: "licenseType", Code: entity.CodeInvalid, Message: "licenseType is invalid"},
+					},
 				})
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
 			wantErrMsg: "license is invalid,licenseType is invalid",
 		},
+		{
+			name:        "Should return unprocessable entity error when license does not allow driver vehicles",
+			pathValue:   "1",
+			requestBody: `{"licenseType": "A"}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(&entity.ErrorIncompatibleLicense{
+					LicenseType:  "A",
+					VehicleClass: "heavy_truck",
+					Plate:        "ABC1234",
+				})
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  true,
+			wantErrMsg: "driver license type A does not allow driving vehicle ABC1234 of class heavy_truck",
+		},
 		{
 			name:        "Should return not found error when driver is not found",
 			pathValue:   "1",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Update(1, gomock.Any()).Return(usecase.ErrDriverNotFound)
+				mockDriverUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(usecase.ErrDriverNotFound)
 			},
 			wantStatus: http.StatusNotFound,
 			wantError:  true,
@@ -385,7 +883,7 @@ func TestDriverHandler_Update(t *testing.T) {
 			pathValue:   "1",
 			requestBody: mockBody,
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Update(1, gomock.Any()).Return(errors.New("some error occurred"))
+				mockDriverUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(errors.New("some error occurred"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantError:  true,
@@ -404,7 +902,7 @@ func TestDriverHandler_Update(t *testing.T) {
 			}
 
 			reqBody := strings.NewReader(tt.requestBody)
-			req := httptest.NewRequest(http.MethodPut, "/drivers/{id}", reqBody)
+			req := httptest.NewRequest(http.MethodPatch, "/drivers/{id}", reqBody)
 			req.SetPathValue("id", tt.pathValue)
 			respWriter := httptest.NewRecorder()
 
@@ -417,10 +915,163 @@ func TestDriverHandler_Update(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_Replace(t *testing.T) {
+	mockBody := `{"name": "John", "lastName": "Doe", "email": "john.doe@example.com", "phone": "1234567890", ` +
+		`"cpf": "52998224725", "license": "12345678900", "licenseType": "B"}`
+	tests := []struct {
+		name        string
+		pathValue   string
+		requestBody string
+		ifMatch     string
+		setup       func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus  int
+		wantError   bool
+		wantErrMsg  string
+	}{
+		{
+			name:        "Should return ok when the driver is replaced",
+			pathValue:   "1",
+			requestBody: mockBody,
+			ifMatch:     `"2"`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Replace(1, &entity.Driver{
+					Name:        "John",
+					LastName:    "Doe",
+					Email:       "john.doe@example.com",
+					Phone:       "1234567890",
+					CPF:         "52998224725",
+					License:     "12345678900",
+					LicenseType: "B",
+					Version:     2,
+				}, gomock.Any()).Return(false, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantError:  false,
+		},
+		{
+			name:        "Should return created when the driver does not exist",
+			pathValue:   "10",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Replace(10, gomock.Any(), gomock.Any()).Return(true, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantError:  false,
+		},
+		{
+			name:        "Should replace the driver by its external id",
+			pathValue:   "hr:E-1",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().ReplaceByExternalId("hr", "E-1", gomock.Any(), gomock.Any()).Return(true, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantError:  false,
+		},
+		{
+			name:        "Should return bad request error for an invalid external reference",
+			pathValue:   ":E-1",
+			requestBody: mockBody,
+			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:  http.StatusBadRequest,
+			wantError:   true,
+			wantErrMsg:  "driverId must be a number or an external id as source:id",
+		},
+		{
+			name:        "Should return bad request error when driverId is not a number",
+			pathValue:   "abc",
+			requestBody: mockBody,
+			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:  http.StatusBadRequest,
+			wantError:   true,
+			wantErrMsg:  "driverId must be a number",
+		},
+		{
+			name:        "Should return bad request error when request body is not a valid JSON",
+			pathValue:   "1",
+			requestBody: `{"name"}`,
+			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:  http.StatusBadRequest,
+			wantError:   true,
+			wantErrMsg:  "invalid request body",
+		},
+		{
+			name:        "Should return precondition failed when If-Match is invalid",
+			pathValue:   "1",
+			requestBody: mockBody,
+			ifMatch:     `"abc"`,
+			setup:       func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus:  http.StatusPreconditionFailed,
+			wantError:   true,
+			wantErrMsg:  "resource was modified",
+		},
+		{
+			name:        "Should return bad request error when returns invalid field error",
+			pathValue:   "1",
+			requestBody: `{"name": "J"}`,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Replace(1, gomock.Any(), gomock.Any()).Return(false,
+					entity.NewErrorInvalidField("name", entity.CodeTooShort, "J", i18n.DriverNameInvalid))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  true,
+			wantErrMsg: "driver name is invalid",
+		},
+		{
+			name:        "Should return conflict error when the id belongs to a deleted driver",
+			pathValue:   "3",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Replace(3, gomock.Any(), gomock.Any()).Return(false, usecase.ErrRecordDeleted)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: "id belongs to a deleted record",
+		},
+		{
+			name:        "Should return internal server error",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Replace(1, gomock.Any(), gomock.Any()).Return(false, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantError:  true,
+			wantErrMsg: "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{
+				DriverUsecase: mockDriverUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/drivers/{id}", strings.NewReader(tt.requestBody))
+			req.SetPathValue("id", tt.pathValue)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			respWriter := httptest.NewRecorder()
+
+			dh.Replace(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrMsg)
+			}
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+		})
+	}
+}
+
 func TestDriverHandler_Delete(t *testing.T) {
 	tests := []struct {
 		name       string
 		pathValue  string
+		query      string
 		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
 		wantStatus int
 		wantError  bool
@@ -430,11 +1081,31 @@ func TestDriverHandler_Delete(t *testing.T) {
 			name:      "Should delete driver successfully",
 			pathValue: "1",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Delete(1).Return(nil)
+				mockDriverUsecase.EXPECT().Delete(1, uint(0), entity.VehiclePolicyRestrict, gomock.Any()).Return(nil)
 			},
 			wantStatus: http.StatusNoContent,
 			wantError:  false,
 		},
+		{
+			name:      "Should delete driver with its vehicles",
+			pathValue: "1",
+			query:     "?vehicles=cascade",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Delete(1, uint(0), entity.VehiclePolicyCascade, gomock.Any()).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantError:  false,
+		},
+		{
+			name:      "Should return conflict when the driver has vehicles",
+			pathValue: "1",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Delete(1, uint(0), entity.VehiclePolicyRestrict, gomock.Any()).Return(usecase.ErrDriverHasVehicles)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  true,
+			wantErrMsg: "driver has vehicles",
+		},
 		{
 			name:       "Should return bad request error when driverId is not a number",
 			pathValue:  "abc",
@@ -447,9 +1118,7 @@ func TestDriverHandler_Delete(t *testing.T) {
 			name:      "Should return bad request error when driverId is invalid",
 			pathValue: "0",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Delete(0).Return(&entity.ErrorInvalidField{
-					Message: []string{"driverId is invalid"},
-				})
+				mockDriverUsecase.EXPECT().Delete(0, uint(0), entity.VehiclePolicyRestrict, gomock.Any()).Return(entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, "driverId is invalid"))
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
@@ -459,7 +1128,7 @@ func TestDriverHandler_Delete(t *testing.T) {
 			name:      "Should return internal server error",
 			pathValue: "1",
 			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
-				mockDriverUsecase.EXPECT().Delete(1).Return(errors.New("some error occurred"))
+				mockDriverUsecase.EXPECT().Delete(1, uint(0), entity.VehiclePolicyRestrict, gomock.Any()).Return(errors.New("some error occurred"))
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantError:  true,
@@ -477,7 +1146,7 @@ func TestDriverHandler_Delete(t *testing.T) {
 				DriverUsecase: mockDriverUsecase,
 			}
 
-			req := httptest.NewRequest(http.MethodDelete, "/drivers/{id}", nil)
+			req := httptest.NewRequest(http.MethodDelete, "/drivers/{id}"+tt.query, nil)
 			req.SetPathValue("id", tt.pathValue)
 			respWriter := httptest.NewRecorder()
 
@@ -489,3 +1158,108 @@ func TestDriverHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestDriverHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathValue  string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:      "Should restore the driver",
+			pathValue: "1",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Restore(1, gomock.Any()).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Name: "John", Version: 3}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"John"`,
+		},
+		{
+			name:       "Should return bad request error when driverId is not a number",
+			pathValue:  "abc",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "driverId must be a number",
+		},
+		{
+			name:      "Should return conflict when the driver is not deleted",
+			pathValue: "1",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Restore(1, gomock.Any()).Return(nil, usecase.ErrRecordNotDeleted)
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   "record is not deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/drivers/{id}/restore", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			dh.Restore(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, `"3"`, respWriter.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestDriverHandler_Purge(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:  "Should purge the driver",
+			token: "secret",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Purge(1, entity.NewActor("root", entity.RoleAdmin)).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:  "Should return forbidden when the actor is not an admin",
+			token: "other",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Purge(1, entity.NewActor("root", "")).Return(usecase.ErrPurgeNotAllowed)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   "only admins can purge records",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/drivers/{id}/purge", nil)
+			req.SetPathValue("id", "1")
+			req.Header.Set(headerActor, "root")
+			req.Header.Set(headerAdminToken, tt.token)
+			respWriter := httptest.NewRecorder()
+
+			AdminHandler{Token: "secret"}.Middleware(dh.Purge)(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -2,10 +2,152 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 )
 
-func errorHandler(w http.ResponseWriter, status int, err error) {
+const contentTypeProblem = "application/problem+json"
+
+// problem is an RFC 7807 problem details response.
+type problem struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail"`
+	Errors []problemField `json:"errors,omitempty"`
+}
+
+// problemField is a single violation of a validation error.
+type problemField struct {
+	Field   string      `json:"field"`
+	Code    string      `json:"code"`
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message"`
+}
+
+// statusByKind maps the kinds of domain errors to HTTP status codes. It is
+// the only place deciding the status of an error.
+var statusByKind = map[domainerr.Kind]int{
+	domainerr.Internal:      http.StatusInternalServerError,
+	domainerr.Validation:    http.StatusBadRequest,
+	domainerr.NotFound:      http.StatusNotFound,
+	domainerr.Conflict:      http.StatusConflict,
+	domainerr.Precondition:  http.StatusPreconditionFailed,
+	domainerr.Unprocessable: http.StatusUnprocessableEntity,
+	domainerr.Unavailable:   http.StatusServiceUnavailable,
+	domainerr.Unsupported:   http.StatusUnsupportedMediaType,
+	domainerr.Forbidden:     http.StatusForbidden,
+}
+
+// conflictMessages are the i18n message keys of the unique fields.
+var conflictMessages = map[entity.ErrorConflict]string{
+	{Entity: "driver", Field: "email"}:       i18n.DriverEmailConflict,
+	{Entity: "driver", Field: "cpf"}:         i18n.DriverCPFConflict,
+	{Entity: "driver", Field: "license"}:     i18n.DriverLicenseConflict,
+	{Entity: "vehicle", Field: "plate"}:      i18n.VehiclePlateConflict,
+	{Entity: "driver", Field: "externalId"}:  i18n.DriverExternalIdConflict,
+	{Entity: "vehicle", Field: "externalId"}: i18n.VehicleExternalIdConflict,
+}
+
+// statusOf returns the HTTP status of err by its kind.
+func statusOf(err error) int {
+	return statusByKind[domainerr.KindOf(err)]
+}
+
+// errorHandler writes err as problem details with the status of its kind.
+func errorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusOf(err)
+	lang := language(r)
+	resp := problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: localize(lang, err),
+		Errors: problemFields(lang, err),
+	}
+
+	w.Header().Set("Content-Type", contentTypeProblem)
+	w.Header().Set("Content-Language", lang)
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	json.NewEncoder(w).Encode(resp)
+}
+
+// problemFields returns the violations of a validation error in lang.
+func problemFields(lang string, err error) []problemField {
+	var invalidField *entity.ErrorInvalidField
+	if !errors.As(err, &invalidField) {
+		return nil
+	}
+	fields := make([]problemField, len(invalidField.Errors))
+	for i, fieldErr := range invalidField.Errors {
+		fields[i] = problemField{
+			Field:   fieldErr.Field,
+			Code:    fieldErr.Code,
+			Value:   fieldErr.Value,
+			Message: localizeField(lang, fieldErr),
+		}
+	}
+	return fields
+}
+
+// localize returns the message of err in lang. Errors without a message
+// key, e.g. unexpected ones, keep their own message.
+func localize(lang string, err error) string {
+	var invalidField *entity.ErrorInvalidField
+	if errors.As(err, &invalidField) {
+		messages := make([]string, len(invalidField.Errors))
+		for i, fieldErr := range invalidField.Errors {
+			messages[i] = localizeField(lang, fieldErr)
+		}
+		return strings.Join(messages, ",")
+	}
+	var conflict *entity.ErrorConflict
+	if errors.As(err, &conflict) {
+		if key, ok := conflictMessages[*conflict]; ok {
+			return i18n.Message(lang, key)
+		}
+	}
+	var incompatible *entity.ErrorIncompatibleLicense
+	if errors.As(err, &incompatible) {
+		allowed := strings.Join(entity.AllowedVehicleClasses(incompatible.LicenseType), ", ")
+		if allowed == "" {
+			allowed = i18n.Message(lang, i18n.VehicleClassNone)
+		}
+		return i18n.Message(lang, i18n.DriverLicenseIncompatible,
+			incompatible.LicenseType, incompatible.Plate, incompatible.VehicleClass, allowed)
+	}
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) {
+		return i18n.Message(lang, domainErr.Key, domainErr.Args...)
+	}
+	return err.Error()
+}
+
+func localizeField(lang string, fieldErr entity.FieldError) string {
+	if fieldErr.Key == "" {
+		return fieldErr.Message
+	}
+	return i18n.Message(lang, fieldErr.Key, fieldErr.Args...)
+}
+
+// invalidNumber is returned when a path or query parameter is not a number.
+func invalidNumber(field, value string) error {
+	return entity.NewErrorInvalidField(field, entity.CodeInvalidFormat, value, i18n.MustBeNumber, field)
+}
+
+// invalidBody converts an error of json decoding into a validation error,
+// pointing at the field of the body when the decoder knows it.
+func invalidBody(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return entity.NewErrorInvalidField(typeErr.Field, entity.CodeInvalid, nil,
+			i18n.MustBeType, typeErr.Field, typeErr.Type.String())
+	}
+	return entity.NewErrorInvalidField("body", entity.CodeInvalidFormat, nil,
+		i18n.BodyInvalid, err.Error())
 }
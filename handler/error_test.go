@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_errorHandler(t *testing.T) {
+	invalidField := entity.NewErrorInvalidField("licenseType", entity.CodeInvalid, "Z", i18n.DriverLicenseTypeInvalid)
+	invalidField.Add("vehicles[0].plate", entity.CodeRequired, nil, i18n.VehiclePlateInvalid)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantStatus     int
+		err            error
+		wantBody       string
+		wantLanguage   string
+	}{
+		{
+			name:         "Should write problem details",
+			wantStatus:   http.StatusInternalServerError,
+			err:          fmt.Errorf("some error occurred"),
+			wantBody:     `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"some error occurred"}`,
+			wantLanguage: "en",
+		},
+		{
+			name:       "Should write a violation per invalid field",
+			wantStatus: http.StatusBadRequest,
+			err:        invalidField,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"driver license type is invalid,vehicle plate is invalid","errors":[` +
+				`{"field":"licenseType","code":"invalid","value":"Z","message":"driver license type is invalid"},` +
+				`{"field":"vehicles[0].plate","code":"required","message":"vehicle plate is invalid"}]}`,
+			wantLanguage: "en",
+		},
+		{
+			name:           "Should translate the violations to the accepted language",
+			acceptLanguage: "fr-FR,pt-BR;q=0.9,en;q=0.8",
+			wantStatus:     http.StatusBadRequest,
+			err:            invalidField,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"categoria da CNH do motorista é inválida,placa do veículo é inválida","errors":[` +
+				`{"field":"licenseType","code":"invalid","value":"Z","message":"categoria da CNH do motorista é inválida"},` +
+				`{"field":"vehicles[0].plate","code":"required","message":"placa do veículo é inválida"}]}`,
+			wantLanguage: "pt-BR",
+		},
+		{
+			name:           "Should translate not found errors",
+			acceptLanguage: "pt",
+			wantStatus:     http.StatusNotFound,
+			err:            usecase.ErrDriverNotFound,
+			wantBody:       `{"type":"about:blank","title":"Not Found","status":404,"detail":"motorista não encontrado"}`,
+			wantLanguage:   "pt-BR",
+		},
+		{
+			name:           "Should translate conflict errors",
+			acceptLanguage: "pt-BR",
+			wantStatus:     http.StatusConflict,
+			err:            &entity.ErrorConflict{Entity: "vehicle", Field: "plate"},
+			wantBody:       `{"type":"about:blank","title":"Conflict","status":409,"detail":"placa do veículo já cadastrada"}`,
+			wantLanguage:   "pt-BR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			respWriter := httptest.NewRecorder()
+
+			errorHandler(respWriter, req, tt.err)
+
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Equal(t, "application/problem+json", respWriter.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantLanguage, respWriter.Header().Get("Content-Language"))
+			assert.JSONEq(t, tt.wantBody, respWriter.Body.String())
+		})
+	}
+}
+
+func Test_statusOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "validation", err: entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, i18n.DriverIdInvalid), want: http.StatusBadRequest},
+		{name: "not found", err: usecase.ErrVehicleNotFound, want: http.StatusNotFound},
+		{name: "wrapped not found", err: fmt.Errorf("adding vehicle: %w", usecase.ErrDriverNotFound), want: http.StatusNotFound},
+		{name: "conflict", err: &entity.ErrorConflict{Entity: "driver", Field: "cpf"}, want: http.StatusConflict},
+		{name: "precondition", err: usecase.ErrVersionMismatch, want: http.StatusPreconditionFailed},
+		{name: "unprocessable", err: &entity.ErrorIncompatibleLicense{LicenseType: "B", VehicleClass: "bus"}, want: http.StatusUnprocessableEntity},
+		{name: "unavailable", err: domainerr.Wrap(domainerr.Unavailable, i18n.ServiceUnavailable, fmt.Errorf("connection refused")), want: http.StatusServiceUnavailable},
+		{name: "internal", err: fmt.Errorf("some error occurred"), want: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, statusOf(tt.err))
+		})
+	}
+}
+
+func Test_invalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{
+			name: "Should point at the field with the wrong type",
+			body: `{"year": "2020"}`,
+			want: entity.NewErrorInvalidField("year", entity.CodeInvalid, nil, i18n.MustBeType, "year", "int"),
+		},
+		{
+			name: "Should point at the body when it is not a valid JSON",
+			body: `{"year"}`,
+			want: entity.NewErrorInvalidField("body", entity.CodeInvalidFormat, nil, i18n.BodyInvalid, "invalid character '}' after object key"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.NewDecoder(strings.NewReader(tt.body)).Decode(new(vehicleRequest))
+
+			assert.Equal(t, tt.want, invalidBody(err))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+)
+
+// etag formats the version of a record as a strong entity tag, e.g. "3".
+func etag(version uint) string {
+	return fmt.Sprintf("%q", strconv.FormatUint(uint64(version), 10))
+}
+
+// parseIfMatch reads the version expected by the If-Match header. Zero means
+// the header is absent or "*", so the request is not conditional. A value
+// that is not a version of ours can never match and is reported as false.
+func parseIfMatch(r *http.Request) (uint, bool) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, true
+	}
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+func preconditionFailed(w http.ResponseWriter, r *http.Request) {
+	errorHandler(w, r, usecase.ErrVersionMismatch)
+}
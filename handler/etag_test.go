@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_parseIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    uint
+		wantOk  bool
+	}{
+		{name: "Should not be conditional without header", ifMatch: "", want: 0, wantOk: true},
+		{name: "Should not be conditional for any version", ifMatch: "*", want: 0, wantOk: true},
+		{name: "Should read strong entity tag", ifMatch: `"3"`, want: 3, wantOk: true},
+		{name: "Should read weak entity tag", ifMatch: `W/"4"`, want: 4, wantOk: true},
+		{name: "Should not match unknown entity tag", ifMatch: `"abc"`, want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/drivers/1", nil)
+			req.Header.Set("If-Match", tt.ifMatch)
+
+			got, ok := parseIfMatch(req)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestDriverHandler_GetById_ETag(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+	mockDriverUsecase.EXPECT().GetById(1, false).Return(&entity.Driver{Version: 7}, nil)
+
+	dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+	req := httptest.NewRequest(http.MethodGet, "/drivers/{id}", nil)
+	req.SetPathValue("id", "1")
+	respWriter := httptest.NewRecorder()
+
+	dh.GetById(respWriter, req)
+	assert.Equal(t, `"7"`, respWriter.Header().Get("ETag"))
+}
+
+func TestVehicleHandler_Update_IfMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		setup    func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantCode int
+	}{
+		{
+			name:    "Should send the expected version to the usecase",
+			ifMatch: `"3"`,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, &entity.Patch{
+					Type:     entity.PatchTypeMerge,
+					Document: []byte(`{"brand": "Volvo"}`),
+					Version:  3,
+				}, gomock.Any()).Return(nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:    "Should return precondition failed when version does not match",
+			ifMatch: `"2"`,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(usecase.ErrVersionMismatch)
+			},
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "Should return precondition failed when entity tag is unknown",
+			ifMatch:  `"abc"`,
+			setup:    func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantCode: http.StatusPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+			req := httptest.NewRequest(http.MethodPatch, "/vehicles/{id}", strings.NewReader(`{"brand": "Volvo"}`))
+			req.SetPathValue("id", "1")
+			req.Header.Set("If-Match", tt.ifMatch)
+			respWriter := httptest.NewRecorder()
+
+			vh.Update(respWriter, req)
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/xuri/excelize/v2"
+)
+
+// Formats of the exported files.
+const (
+	exportFormatCSV    = "csv"
+	exportFormatXLSX   = "xlsx"
+	exportFormatNDJSON = "ndjson"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+var exportContentTypes = map[string]string{
+	exportFormatCSV:    contentTypeCSV + "; charset=utf-8",
+	exportFormatXLSX:   contentTypeXLSX,
+	exportFormatNDJSON: contentTypeNDJSON,
+}
+
+// Columns of the exported spreadsheets, named as the fields of the responses.
+var (
+	driverExportColumns = []string{"id", "name", "lastName", "email", "phone", "cpf", "license", "licenseType",
+		"status", "licenseIssuedAt", "licenseExpiresAt", "firstLicenseAt", "externalSource", "externalId",
+		"version", "createdAt", "updatedAt"}
+	vehicleExportColumns = []string{"id", "plate", "plateCountry", "plateFormat", "brand", "vehicleModel", "year",
+		"class", "status", "driverId", "externalSource", "externalId", "version", "createdAt", "updatedAt"}
+)
+
+// record returns the values of the driver in the order of driverExportColumns.
+func (dr *driverResponse) record() []interface{} {
+	return []interface{}{dr.ID, dr.Name, dr.LastName, dr.Email, dr.Phone, dr.CPF, dr.License, dr.LicenseType,
+		dr.Status, exportDate(dr.LicenseIssuedAt), exportDate(dr.LicenseExpiresAt), exportDate(dr.FirstLicenseAt),
+		exportString(dr.ExternalSource), exportString(dr.ExternalID),
+		dr.Version, dr.CreatedAt.Format(time.RFC3339), dr.UpdatedAt.Format(time.RFC3339)}
+}
+
+// record returns the values of the vehicle in the order of vehicleExportColumns.
+func (vr *vehicleResponse) record() []interface{} {
+	var driverId interface{} = ""
+	if vr.DriverID != nil {
+		driverId = *vr.DriverID
+	}
+	return []interface{}{vr.ID, vr.Plate, vr.PlateCountry, vr.PlateFormat, vr.Brand, vr.VehicleModel, vr.Year,
+		vr.Class, vr.Status, driverId, exportString(vr.ExternalSource), exportString(vr.ExternalID),
+		vr.Version, vr.CreatedAt.Format(time.RFC3339), vr.UpdatedAt.Format(time.RFC3339)}
+}
+
+func exportDate(d *date) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format(time.DateOnly)
+}
+
+func exportString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// parseExportFormat reads the format of an export from the query string,
+// csv by default.
+func parseExportFormat(r *http.Request) (string, error) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		return exportFormatCSV, nil
+	}
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", entity.NewErrorInvalidField("format", entity.CodeInvalid, format, i18n.ExportFormatInvalid,
+			strings.Join([]string{exportFormatCSV, exportFormatXLSX, exportFormatNDJSON}, ", "))
+	}
+	return format, nil
+}
+
+// exportWriter encodes the records of an export. Spreadsheets use the values
+// of record while NDJSON encodes value.
+type exportWriter interface {
+	write(record []interface{}, value any) error
+	close() error
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+// newCSVExportWriter writes a byte order mark before the header so
+// spreadsheets open the file as UTF-8.
+func newCSVExportWriter(out io.Writer, columns []string) (*csvExportWriter, error) {
+	_, err := io.WriteString(out, "\ufeff")
+	if err != nil {
+		return nil, err
+	}
+	cw := &csvExportWriter{writer: csv.NewWriter(out)}
+	return cw, cw.writer.Write(columns)
+}
+
+func (cw *csvExportWriter) write(record []interface{}, _ any) error {
+	values := make([]string, len(record))
+	for i, value := range record {
+		values[i] = fmt.Sprint(value)
+	}
+	return cw.writer.Write(values)
+}
+
+func (cw *csvExportWriter) close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// xlsxExportWriter streams the rows to a temporary file of excelize, as a
+// XLSX can only be written to the response once complete.
+type xlsxExportWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(out io.Writer, columns []string) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	xw := &xlsxExportWriter{out: out, file: file, stream: stream}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	return xw, xw.write(header, nil)
+}
+
+func (xw *xlsxExportWriter) write(record []interface{}, _ any) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	return xw.stream.SetRow(cell, record)
+}
+
+func (xw *xlsxExportWriter) close() error {
+	defer xw.file.Close()
+	err := xw.stream.Flush()
+	if err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw ndjsonExportWriter) write(_ []interface{}, value any) error {
+	return nw.encoder.Encode(value)
+}
+
+func (nw ndjsonExportWriter) close() error {
+	return nil
+}
+
+// export writes an exported file to the response. The headers are only sent
+// with the first record, so an error found before it, e.g. an invalid filter,
+// can still be answered as problem details.
+type export struct {
+	w       http.ResponseWriter
+	format  string
+	name    string
+	columns []string
+	writer  exportWriter
+	sent    bool
+}
+
+func newExport(w http.ResponseWriter, format string, name string, columns []string) *export {
+	return &export{w: w, format: format, name: name, columns: columns}
+}
+
+// Write marks the response as sent before writing to it.
+func (e *export) Write(p []byte) (int, error) {
+	e.sent = true
+	return e.w.Write(p)
+}
+
+func (e *export) start() error {
+	filename := fmt.Sprintf("%s-%s.%s", e.name, time.Now().UTC().Format(time.DateOnly), e.format)
+	e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	var err error
+	switch e.format {
+	case exportFormatXLSX:
+		e.writer, err = newXLSXExportWriter(e, e.columns)
+	case exportFormatNDJSON:
+		e.writer = ndjsonExportWriter{encoder: json.NewEncoder(e)}
+	default:
+		e.writer, err = newCSVExportWriter(e, e.columns)
+	}
+	return err
+}
+
+func (e *export) write(record []interface{}, value any) error {
+	if e.writer == nil {
+		err := e.start()
+		if err != nil {
+			return err
+		}
+	}
+	return e.writer.write(record, value)
+}
+
+// close finishes the file, writing just the header when nothing was exported.
+func (e *export) close() error {
+	if e.writer == nil {
+		err := e.start()
+		if err != nil {
+			return err
+		}
+	}
+	return e.writer.close()
+}
+
+// fail answers err as problem details while nothing was sent. Otherwise the
+// connection is aborted, so the client does not take a truncated file as
+// complete.
+func (e *export) fail(r *http.Request, err error) {
+	if xw, ok := e.writer.(*xlsxExportWriter); ok {
+		xw.file.Close()
+	}
+	if e.sent {
+		panic(http.ErrAbortHandler)
+	}
+	e.w.Header().Del("Content-Disposition")
+	errorHandler(e.w, r, err)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDriverHandler_Export(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	hr, externalId := "hr", "1234"
+	drivers := []*entity.Driver{
+		{Model: gorm.Model{ID: 1, CreatedAt: createdAt, UpdatedAt: createdAt}, Name: "João", LastName: "Silva",
+			Email: "joao@test.com", CPF: "52998224725", License: "12345678026", LicenseType: "B",
+			Status: entity.DriverStatusActive, LicenseExpiresAt: &expiresAt, ExternalSource: &hr, ExternalID: &externalId, Version: 2},
+		{Model: gorm.Model{ID: 2, CreatedAt: createdAt, UpdatedAt: createdAt}, Name: "Ana", Status: entity.DriverStatusOnLeave, Version: 1},
+	}
+	// export calls write with the drivers in two batches, as the repository does
+	export := func(opts *entity.QueryOptions, write func([]*entity.Driver) error) error {
+		err := write(drivers[:1])
+		if err != nil {
+			return err
+		}
+		return write(drivers[1:])
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		setup           func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus      int
+		wantContentType string
+		wantFilename    string
+		wantBody        string
+	}{
+		{
+			name:  "Should export the filtered drivers as CSV",
+			query: "?licenseType=B",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Export(&entity.QueryOptions{
+					Page:     1,
+					PageSize: entity.DefaultPageSize,
+					Filters:  []entity.Filter{{Field: "licenseType", Operator: entity.FilterOperatorEqual, Value: "B"}},
+				}, gomock.Any()).DoAndReturn(export)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantFilename:    `attachment; filename="drivers-`,
+			wantBody: "\ufeffid,name,lastName,email,phone,cpf,license,licenseType,status,licenseIssuedAt,licenseExpiresAt,firstLicenseAt,externalSource,externalId,version,createdAt,updatedAt\n" +
+				"1,João,Silva,joao@test.com,,52998224725,12345678026,B,active,,2030-01-15,,hr,1234,2,2024-05-01T12:00:00Z,2024-05-01T12:00:00Z\n" +
+				"2,Ana,,,,,,,on_leave,,,,,,1,2024-05-01T12:00:00Z,2024-05-01T12:00:00Z\n",
+		},
+		{
+			name:  "Should export the drivers as NDJSON",
+			query: "?format=ndjson",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Export(gomock.Any(), gomock.Any()).DoAndReturn(export)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: contentTypeNDJSON,
+			wantFilename:    ".ndjson",
+			wantBody:        `"id":1,"name":"João"`,
+		},
+		{
+			name:  "Should export only the header when no driver matches",
+			query: "?format=csv",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Export(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantFilename:    ".csv",
+			wantBody:        "\ufeffid,name,",
+		},
+		{
+			name:       "Should return bad request for an unknown format",
+			query:      "?format=pdf",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "format must be one of: csv, xlsx, ndjson",
+		},
+		{
+			name:  "Should return the error found before exporting any driver",
+			query: "?sort=name",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().Export(gomock.Any(), gomock.Any()).Return(errors.New("some error occurred"))
+			},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: contentTypeProblem,
+			wantBody:        "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/export"+tt.query, nil)
+			respWriter := httptest.NewRecorder()
+
+			dh.Export(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, respWriter.Header().Get("Content-Type"))
+			}
+			if tt.wantFilename != "" {
+				assert.Contains(t, respWriter.Header().Get("Content-Disposition"), tt.wantFilename)
+			} else {
+				assert.Empty(t, respWriter.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestVehicleHandler_Export(t *testing.T) {
+	driverId := uint(3)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+	mockVehicleUsecase.EXPECT().Export(&entity.QueryOptions{
+		Page:     1,
+		PageSize: entity.DefaultPageSize,
+		Filters:  []entity.Filter{{Field: "driverId", Operator: entity.FilterOperatorNull, Value: "false"}},
+	}, gomock.Any()).DoAndReturn(func(opts *entity.QueryOptions, write func([]*entity.Vehicle) error) error {
+		return write([]*entity.Vehicle{{
+			Model: gorm.Model{ID: 1, CreatedAt: createdAt, UpdatedAt: createdAt}, Plate: "ABC1D23", PlateCountry: "BR",
+			PlateFormat: "mercosul", Brand: "Volvo", VehicleModel: "FH 540", Year: 2021, Class: "heavy_truck",
+			Status: entity.VehicleStatusInUse, DriverID: &driverId, Version: 1,
+		}})
+	})
+
+	vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+
+	req := httptest.NewRequest(http.MethodGet, "/vehicles/export?format=XLSX&unassigned=false", nil)
+	respWriter := httptest.NewRecorder()
+
+	vh.Export(respWriter, req)
+	assert.Equal(t, http.StatusOK, respWriter.Code)
+	assert.Equal(t, contentTypeXLSX, respWriter.Header().Get("Content-Type"))
+	assert.Contains(t, respWriter.Header().Get("Content-Disposition"), ".xlsx")
+
+	file, err := excelize.OpenReader(respWriter.Body)
+	assert.Nil(t, err)
+	defer file.Close()
+	rows, err := file.GetRows("Sheet1")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		vehicleExportColumns,
+		{"1", "ABC1D23", "BR", "mercosul", "Volvo", "FH 540", "2021", "heavy_truck", "in_use", "3", "", "", "1",
+			"2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z"},
+	}, rows)
+}
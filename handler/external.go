@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// pathRef is a record referenced in a path by its id or by its external id
+// as source:id, e.g. /drivers/hr:1234.
+type pathRef struct {
+	id         int
+	source     string
+	externalId string
+}
+
+func (p pathRef) external() bool {
+	return p.source != ""
+}
+
+func parsePathRef(value string, field string) (pathRef, error) {
+	if !strings.Contains(value, ":") {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return pathRef{}, invalidNumber(field, value)
+		}
+		return pathRef{id: id}, nil
+	}
+	source, externalId, ok := entity.ParseExternalRef(value)
+	if !ok {
+		return pathRef{}, entity.NewErrorInvalidField(field, entity.CodeInvalidFormat, value, i18n.ExternalRefInvalid, field)
+	}
+	return pathRef{source: source, externalId: externalId}, nil
+}
+
+// resolveExternalRef replaces the path value param of r, when it is an
+// external reference, by the id of the record returned by get. Numeric ids
+// are left for the handler to parse.
+func resolveExternalRef(r *http.Request, param string, field string, get func(source string, externalId string) (uint, error)) error {
+	value := r.PathValue(param)
+	if !strings.Contains(value, ":") {
+		return nil
+	}
+	ref, err := parsePathRef(value, field)
+	if err != nil {
+		return err
+	}
+	id, err := get(ref.source, ref.externalId)
+	if err != nil {
+		return err
+	}
+	r.SetPathValue(param, strconv.FormatUint(uint64(id), 10))
+	return nil
+}
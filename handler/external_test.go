@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDriverHandler_GetByExternalId(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Should return the driver with the external id",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetByExternalId("hr", "E-1").Return(&entity.Driver{Model: gorm.Model{ID: 1}, Version: 2}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"id":1`,
+		},
+		{
+			name: "Should return not found when no driver has the external id",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetByExternalId("hr", "E-1").Return(nil, nil)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "driver not found",
+		},
+		{
+			name: "Should return internal server error",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetByExternalId("hr", "E-1").Return(nil, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/by-external/{source}/{id}", nil)
+			req.SetPathValue("source", "hr")
+			req.SetPathValue("id", "E-1")
+			respWriter := httptest.NewRecorder()
+
+			dh.GetByExternalId(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestDriverHandler_ExternalId(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathValue  string
+		setup      func(mockDriverUsecase *usecase.MockDriverUsecase)
+		wantStatus int
+		wantId     string
+		wantBody   string
+	}{
+		{
+			name:       "Should keep a numeric id",
+			pathValue:  "3",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusNoContent,
+			wantId:     "3",
+		},
+		{
+			name:      "Should replace an external reference by the driver id",
+			pathValue: "hr:E-1",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetByExternalId("hr", "E-1").Return(&entity.Driver{Model: gorm.Model{ID: 7}}, nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantId:     "7",
+		},
+		{
+			name:      "Should return not found when no driver has the external id",
+			pathValue: "hr:E-1",
+			setup: func(mockDriverUsecase *usecase.MockDriverUsecase) {
+				mockDriverUsecase.EXPECT().GetByExternalId("hr", "E-1").Return(nil, nil)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "driver not found",
+		},
+		{
+			name:       "Should return bad request error for an invalid external reference",
+			pathValue:  "hr:",
+			setup:      func(mockDriverUsecase *usecase.MockDriverUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "driverId must be a number or an external id as source:id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriverUsecase := usecase.NewMockDriverUsecase(ctrl)
+			tt.setup(mockDriverUsecase)
+
+			dh := DriverHandler{DriverUsecase: mockDriverUsecase}
+
+			var gotId string
+			next := func(w http.ResponseWriter, r *http.Request) {
+				gotId = r.PathValue("id")
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/drivers/{id}", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			dh.ExternalId("id", next)(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Equal(t, tt.wantId, gotId)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestVehicleHandler_ExternalId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+	mockVehicleUsecase.EXPECT().GetByExternalId("erp", "TRK:42").Return(&entity.Vehicle{Model: gorm.Model{ID: 5}}, nil)
+
+	vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+
+	var gotId string
+	next := func(w http.ResponseWriter, r *http.Request) {
+		gotId = r.PathValue("vehicleId")
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/drivers/{id}/vehicles/{vehicleId}", nil)
+	req.SetPathValue("vehicleId", "erp:TRK:42")
+	vh.ExternalId("vehicleId", next)(httptest.NewRecorder(), req)
+	assert.Equal(t, "5", gotId)
+}
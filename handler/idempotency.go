@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+type IdempotencyHandler struct {
+	IdempotencyUsecase usecase.IdempotencyUsecase
+}
+
+// Middleware makes next safe to retry when the client sends an
+// Idempotency-Key header: the first response is stored and replayed for
+// retries with the same key, method, path and body. Requests without the
+// header are passed through.
+func (ih IdempotencyHandler) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(headerIdempotencyKey)
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			errorHandler(w, r, invalidBody(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(r, body)
+
+		stored, err := ih.IdempotencyUsecase.Begin(key, fingerprint)
+		if err != nil {
+			errorHandler(w, r, err)
+			return
+		}
+		if stored != nil {
+			if stored.ContentType != "" {
+				w.Header().Set("Content-Type", stored.ContentType)
+			}
+			w.Header().Set(headerIdempotentReplayed, "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next(recorder, r)
+
+		// the response was already sent, failures to store it are logged by
+		// the repository and only cost the client a real retry
+		if recorder.statusCode() >= http.StatusInternalServerError {
+			ih.IdempotencyUsecase.Abort(key)
+			return
+		}
+		ih.IdempotencyUsecase.Complete(&entity.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			StatusCode:  recorder.statusCode(),
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder writes the response to the client while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) statusCode() int {
+	if rr.status == 0 {
+		return http.StatusOK
+	}
+	return rr.status
+}
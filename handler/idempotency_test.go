@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyHandler_Middleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		setup        func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase)
+		next         http.HandlerFunc
+		wantCode     int
+		wantBody     string
+		wantReplayed bool
+	}{
+		{
+			name:  "Should pass through requests without key",
+			key:   "",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {},
+			next: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "Should store the response of the first request",
+			key:  "abc",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {
+				mockIdempotencyUsecase.EXPECT().Begin("abc", gomock.Any()).Return(nil, nil)
+				mockIdempotencyUsecase.EXPECT().Complete(gomock.Any()).DoAndReturn(func(key *entity.IdempotencyKey) error {
+					assert.Equal(t, http.StatusCreated, key.StatusCode)
+					assert.Equal(t, `{"id":1}`, string(key.Body))
+					return nil
+				})
+			},
+			next: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id":1}`))
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1}`,
+		},
+		{
+			name: "Should replay the stored response",
+			key:  "abc",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {
+				mockIdempotencyUsecase.EXPECT().Begin("abc", gomock.Any()).Return(&entity.IdempotencyKey{
+					Key:        "abc",
+					StatusCode: http.StatusCreated,
+					Body:       []byte(`{"id":1}`),
+				}, nil)
+			},
+			next: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("request must not be executed again")
+			},
+			wantCode:     http.StatusCreated,
+			wantBody:     `{"id":1}`,
+			wantReplayed: true,
+		},
+		{
+			name: "Should release the key when the request fails",
+			key:  "abc",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {
+				mockIdempotencyUsecase.EXPECT().Begin("abc", gomock.Any()).Return(nil, nil)
+				mockIdempotencyUsecase.EXPECT().Abort("abc").Return(nil)
+			},
+			next: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Should return unprocessable entity when key is reused with another body",
+			key:  "abc",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {
+				mockIdempotencyUsecase.EXPECT().Begin("abc", gomock.Any()).Return(nil, usecase.ErrIdempotencyKeyReused)
+			},
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: usecase.ErrIdempotencyKeyReused.Error(),
+		},
+		{
+			name: "Should return conflict when first request is still running",
+			key:  "abc",
+			setup: func(mockIdempotencyUsecase *usecase.MockIdempotencyUsecase) {
+				mockIdempotencyUsecase.EXPECT().Begin("abc", gomock.Any()).Return(nil, usecase.ErrIdempotencyKeyInProgress)
+			},
+			wantCode: http.StatusConflict,
+			wantBody: usecase.ErrIdempotencyKeyInProgress.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockIdempotencyUsecase := usecase.NewMockIdempotencyUsecase(ctrl)
+			tt.setup(mockIdempotencyUsecase)
+
+			ih := IdempotencyHandler{IdempotencyUsecase: mockIdempotencyUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/drivers", strings.NewReader(`{"name": "John"}`))
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			respWriter := httptest.NewRecorder()
+
+			ih.Middleware(tt.next)(respWriter, req)
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantReplayed, respWriter.Header().Get("Idempotent-Replayed") == "true")
+		})
+	}
+}
+
+func Test_requestFingerprint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/drivers", nil)
+	other := httptest.NewRequest(http.MethodPost, "/drivers/1/vehicle", nil)
+
+	assert.Equal(t, requestFingerprint(req, []byte("a")), requestFingerprint(req, []byte("a")))
+	assert.NotEqual(t, requestFingerprint(req, []byte("a")), requestFingerprint(req, []byte("b")))
+	assert.NotEqual(t, requestFingerprint(req, []byte("a")), requestFingerprint(other, []byte("a")))
+}
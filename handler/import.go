@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/xuri/excelize/v2"
+)
+
+// Media types of the files accepted by the imports.
+const (
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// maxImportSize is the maximum size of an imported file, in bytes.
+const maxImportSize = 10 << 20
+
+// Columns of the imported files, named as the fields of the requests.
+var (
+	driverImportColumns = []string{"name", "lastName", "email", "phone", "cpf", "license", "licenseType",
+		"licenseIssuedAt", "licenseExpiresAt", "firstLicenseAt", "externalSource", "externalId"}
+	vehicleImportColumns = []string{"plate", "plateCountry", "brand", "vehicleModel", "year", "class",
+		"externalSource", "externalId"}
+)
+
+// importRow is a line of an imported file with its values by column.
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+func (ir importRow) get(column string) string {
+	return ir.values[column]
+}
+
+// optional returns nil for an empty value, e.g. a driver without an external id.
+func (ir importRow) optional(column string) *string {
+	value := ir.values[column]
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// date parses a value as YYYY-MM-DD, adding a violation to err when it is not.
+func (ir importRow) date(err *entity.ErrorInvalidField, column string) *time.Time {
+	value := ir.values[column]
+	if value == "" {
+		return nil
+	}
+	t, parseErr := time.Parse(time.DateOnly, value)
+	if parseErr != nil {
+		err.Add(column, entity.CodeInvalidFormat, value, i18n.MustBeDate, column)
+		return nil
+	}
+	return &t
+}
+
+func (ir importRow) number(err *entity.ErrorInvalidField, column string) int {
+	value := ir.values[column]
+	if value == "" {
+		return 0
+	}
+	n, parseErr := strconv.Atoi(value)
+	if parseErr != nil {
+		err.Add(column, entity.CodeInvalidFormat, value, i18n.MustBeNumber, column)
+		return 0
+	}
+	return n
+}
+
+func (ir importRow) toDriver() entity.DriverImportRow {
+	err := new(entity.ErrorInvalidField)
+	row := entity.DriverImportRow{
+		Line: ir.line,
+		Driver: &entity.Driver{
+			Name:             ir.get("name"),
+			LastName:         ir.get("lastName"),
+			Email:            ir.get("email"),
+			Phone:            ir.get("phone"),
+			CPF:              ir.get("cpf"),
+			License:          ir.get("license"),
+			LicenseType:      ir.get("licenseType"),
+			LicenseIssuedAt:  ir.date(err, "licenseIssuedAt"),
+			LicenseExpiresAt: ir.date(err, "licenseExpiresAt"),
+			FirstLicenseAt:   ir.date(err, "firstLicenseAt"),
+			ExternalSource:   ir.optional("externalSource"),
+			ExternalID:       ir.optional("externalId"),
+		},
+	}
+	if len(err.Errors) > 0 {
+		row.Err = err
+	}
+	return row
+}
+
+func (ir importRow) toVehicle() entity.VehicleImportRow {
+	err := new(entity.ErrorInvalidField)
+	row := entity.VehicleImportRow{
+		Line: ir.line,
+		Vehicle: &entity.Vehicle{
+			Plate:          ir.get("plate"),
+			PlateCountry:   ir.get("plateCountry"),
+			Brand:          ir.get("brand"),
+			VehicleModel:   ir.get("vehicleModel"),
+			Year:           ir.number(err, "year"),
+			Class:          ir.get("class"),
+			ExternalSource: ir.optional("externalSource"),
+			ExternalID:     ir.optional("externalId"),
+		},
+	}
+	if len(err.Errors) > 0 {
+		row.Err = err
+	}
+	return row
+}
+
+// parseImportOptions reads the mode and the dry run flag of an import from
+// the query string.
+func parseImportOptions(r *http.Request) (*entity.ImportOptions, error) {
+	opts := entity.NewImportOptions()
+	query := r.URL.Query()
+	if mode := query.Get("mode"); mode != "" {
+		opts.Mode = mode
+	}
+	if value := query.Get("dryRun"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, entity.NewErrorInvalidField("dryRun", entity.CodeInvalidFormat, value, i18n.MustBeBoolean, "dryRun")
+		}
+		opts.DryRun = dryRun
+	}
+	return opts, opts.Validate()
+}
+
+// readImportFile reads the rows of the file of an import, sent as the body
+// with its media type or as the field "file" of a multipart form. The first
+// row of the file names the columns.
+func readImportFile(w http.ResponseWriter, r *http.Request, columns []string) ([]importRow, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = r.Header.Get("Content-Type")
+	}
+
+	body := io.Reader(r.Body)
+	if mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			if errors.Is(err, http.ErrMissingFile) {
+				return nil, entity.NewErrorInvalidField("file", entity.CodeRequired, nil, i18n.ImportFileRequired)
+			}
+			return nil, invalidImportFile(err)
+		}
+		defer file.Close()
+		body = file
+		switch strings.ToLower(filepath.Ext(header.Filename)) {
+		case ".csv":
+			mediaType = contentTypeCSV
+		case ".xlsx":
+			mediaType = contentTypeXLSX
+		default:
+			mediaType = header.Header.Get("Content-Type")
+		}
+	}
+
+	var records [][]string
+	var lines []int
+	switch mediaType {
+	case contentTypeCSV:
+		records, lines, err = readCSV(body)
+	case contentTypeXLSX:
+		records, lines, err = readXLSX(body)
+	default:
+		return nil, domainerr.New(domainerr.Unsupported, i18n.MediaTypeUnsupported,
+			mediaType, strings.Join([]string{contentTypeCSV, contentTypeXLSX}, ", "))
+	}
+	if err != nil {
+		return nil, invalidImportFile(err)
+	}
+	return importRows(records, lines, columns)
+}
+
+// readCSV reads the records of a CSV file separated by commas or, as
+// spreadsheets in Portuguese save them, by semicolons. It also returns the
+// line of each record.
+func readCSV(body io.Reader) ([][]string, []int, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(buffered.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, nil, err
+	}
+	header, _, _ = bytes.Cut(header, []byte("\n"))
+
+	reader := csv.NewReader(buffered)
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	if len(records) > 0 && len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+	return records, lines, nil
+}
+
+// readXLSX reads the rows of the first sheet of a XLSX file and the line of
+// each of them.
+func readXLSX(body io.Reader) ([][]string, []int, error) {
+	file, err := excelize.OpenReader(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil, nil
+	}
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	lines := make([]int, len(rows))
+	for i := range rows {
+		lines[i] = i + 1
+	}
+	return rows, lines, nil
+}
+
+// importRows maps the values of each record to the column named in the
+// header. Blank records are skipped.
+func importRows(records [][]string, lines []int, columns []string) ([]importRow, error) {
+	for len(records) > 0 && blank(records[0]) {
+		records, lines = records[1:], lines[1:]
+	}
+	if len(records) == 0 {
+		return nil, entity.NewErrorInvalidField("file", entity.CodeRequired, nil, i18n.ImportEmpty)
+	}
+
+	known := make(map[string]string, len(columns))
+	for _, column := range columns {
+		known[strings.ToLower(column)] = column
+	}
+	header := make([]string, len(records[0]))
+	unknown := new(entity.ErrorInvalidField)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(name)
+		column, ok := known[strings.ToLower(name)]
+		if !ok && name != "" {
+			unknown.Add(name, entity.CodeUnknownField, nil, i18n.FieldUnknown, name)
+		}
+		header[i] = column
+	}
+	if len(unknown.Errors) > 0 {
+		return nil, unknown
+	}
+
+	var rows []importRow
+	for i, record := range records[1:] {
+		if blank(record) {
+			continue
+		}
+		if len(rows) == entity.MaxImportRows {
+			return nil, entity.NewErrorInvalidField("file", entity.CodeOutOfRange, nil, i18n.ImportTooManyRows, entity.MaxImportRows)
+		}
+		row := importRow{line: lines[i+1], values: make(map[string]string, len(header))}
+		for j, value := range record {
+			if j < len(header) && header[j] != "" {
+				row.values[header[j]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func blank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidImportFile converts an error reading an imported file into a
+// validation error of the field "file".
+func invalidImportFile(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return entity.NewErrorInvalidField("file", entity.CodeOutOfRange, nil, i18n.ImportFileTooLarge, maxImportSize>>20)
+	}
+	return entity.NewErrorInvalidField("file", entity.CodeInvalidFormat, nil, i18n.ImportFileInvalid, err.Error())
+}
+
+// importReportResponse is the result of an import.
+type importReportResponse struct {
+	Mode     string              `json:"mode"`
+	DryRun   bool                `json:"dryRun"`
+	Total    int                 `json:"total"`
+	Valid    int                 `json:"valid"`
+	Imported int                 `json:"imported"`
+	Failed   int                 `json:"failed"`
+	Errors   []importRowResponse `json:"errors"`
+}
+
+// importRowResponse is the reason a line of an imported file was rejected.
+type importRowResponse struct {
+	Line    int            `json:"line"`
+	Message string         `json:"message"`
+	Errors  []problemField `json:"errors,omitempty"`
+}
+
+func newImportReportResponse(lang string, report *entity.ImportReport) *importReportResponse {
+	resp := &importReportResponse{
+		Mode:     report.Mode,
+		DryRun:   report.DryRun,
+		Total:    report.Total,
+		Valid:    report.Valid,
+		Imported: report.Imported,
+		Failed:   report.Failed(),
+		Errors:   make([]importRowResponse, len(report.Errors)),
+	}
+	for i, rowErr := range report.Errors {
+		resp.Errors[i] = importRowResponse{
+			Line:    rowErr.Line,
+			Message: localize(lang, rowErr.Err),
+			Errors:  problemFields(lang, rowErr.Err),
+		}
+	}
+	return resp
+}
+
+// writeImportReport writes the report of an import. An all or nothing import
+// rejected because of invalid rows is answered with 422.
+func writeImportReport(w http.ResponseWriter, r *http.Request, report *entity.ImportReport) {
+	status := http.StatusOK
+	if report.Mode == entity.ImportModeAllOrNothing && !report.DryRun && report.Failed() > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	lang := language(r)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(newImportReportResponse(lang, report))
+}
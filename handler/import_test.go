@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// newXLSX returns a XLSX file with rows in its first sheet.
+func newXLSX(t *testing.T, rows [][]interface{}) []byte {
+	file := excelize.NewFile()
+	defer file.Close()
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		assert.Nil(t, err)
+		assert.Nil(t, file.SetSheetRow("Sheet1", cell, &row))
+	}
+	buf, err := file.WriteToBuffer()
+	assert.Nil(t, err)
+	return buf.Bytes()
+}
+
+// newMultipart returns a multipart form with file sent as the field "file".
+func newMultipart(t *testing.T, filename string, file []byte) (string, []byte) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	assert.Nil(t, err)
+	part.Write(file)
+	assert.Nil(t, writer.Close())
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+func Test_readImportFile(t *testing.T) {
+	multipartType, multipartBody := newMultipart(t, "vehicles.CSV", []byte("plate,year\nABC1D23,2021\n"))
+	xlsx := newXLSX(t, [][]interface{}{{"Plate", "Year"}, {"ABC1D23", 2021}, {}, {"DEF4G56", "2020"}})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        []importRow
+		wantErr     error
+	}{
+		{
+			name:        "Should read a CSV separated by commas",
+			contentType: "text/csv; charset=utf-8",
+			body:        []byte("plate,brand,year\nABC1D23, Volvo ,2021\n\nDEF4G56,Scania\n"),
+			want: []importRow{
+				{line: 2, values: map[string]string{"plate": "ABC1D23", "brand": "Volvo", "year": "2021"}},
+				{line: 4, values: map[string]string{"plate": "DEF4G56", "brand": "Scania"}},
+			},
+		},
+		{
+			name:        "Should read a CSV separated by semicolons with a byte order mark",
+			contentType: contentTypeCSV,
+			body:        []byte("\ufeffPLATE;vehiclemodel\r\nABC1D23;\"FH 540, 6x4\"\r\n"),
+			want: []importRow{
+				{line: 2, values: map[string]string{"plate": "ABC1D23", "vehicleModel": "FH 540, 6x4"}},
+			},
+		},
+		{
+			name:        "Should read the first sheet of a XLSX",
+			contentType: contentTypeXLSX,
+			body:        xlsx,
+			want: []importRow{
+				{line: 2, values: map[string]string{"plate": "ABC1D23", "year": "2021"}},
+				{line: 4, values: map[string]string{"plate": "DEF4G56", "year": "2020"}},
+			},
+		},
+		{
+			name:        "Should read the file of a multipart form by its extension",
+			contentType: multipartType,
+			body:        multipartBody,
+			want: []importRow{
+				{line: 2, values: map[string]string{"plate": "ABC1D23", "year": "2021"}},
+			},
+		},
+		{
+			name:        "Should return error for unknown columns",
+			contentType: contentTypeCSV,
+			body:        []byte("plate,color\nABC1D23,red\n"),
+			wantErr:     entity.NewErrorInvalidField("color", entity.CodeUnknownField, nil, i18n.FieldUnknown, "color"),
+		},
+		{
+			name:        "Should return error for a file without header",
+			contentType: contentTypeCSV,
+			body:        []byte("\n\n"),
+			wantErr:     entity.NewErrorInvalidField("file", entity.CodeRequired, nil, i18n.ImportEmpty),
+		},
+		{
+			name:        "Should return error for an invalid XLSX",
+			contentType: contentTypeXLSX,
+			body:        []byte("plate\n"),
+			wantErr:     entity.NewErrorInvalidField("file", entity.CodeInvalidFormat, nil, i18n.ImportFileInvalid, "zip: not a valid zip file"),
+		},
+		{
+			name:        "Should return error for an unsupported media type",
+			contentType: "application/json",
+			body:        []byte(`[]`),
+			wantErr: domainerr.New(domainerr.Unsupported, i18n.MediaTypeUnsupported,
+				"application/json", contentTypeCSV+", "+contentTypeXLSX),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/import", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			got, err := readImportFile(httptest.NewRecorder(), req, vehicleImportColumns)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_importRow_toDriver(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	source := "hr"
+
+	got := importRow{line: 3, values: map[string]string{
+		"name":             "John",
+		"licenseExpiresAt": "2030-01-15",
+		"firstLicenseAt":   "15/01/2010",
+		"externalSource":   "hr",
+	}}.toDriver()
+
+	assert.Equal(t, entity.DriverImportRow{
+		Line:   3,
+		Driver: &entity.Driver{Name: "John", LicenseExpiresAt: &expiresAt, ExternalSource: &source},
+		Err:    entity.NewErrorInvalidField("firstLicenseAt", entity.CodeInvalidFormat, "15/01/2010", i18n.MustBeDate, "firstLicenseAt"),
+	}, got)
+}
+
+func TestVehicleHandler_Import(t *testing.T) {
+	csv := "plate,brand,vehicleModel,year,class\nABC1D23,Volvo,FH 540,2021,heavy_truck\nDEF4G56,Scania,R 450,20x0,heavy_truck\n"
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:  "Should return the report of the import",
+			query: "?mode=best-effort&dryRun=true",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().ImportVehicles(gomock.Len(2), &entity.ImportOptions{Mode: entity.ImportModeBestEffort, DryRun: true}, gomock.Any()).
+					DoAndReturn(func(rows []entity.VehicleImportRow, opts *entity.ImportOptions, _ entity.Actor) (*entity.ImportReport, error) {
+						assert.Equal(t, 3, rows[1].Line)
+						assert.Equal(t, entity.NewErrorInvalidField("year", entity.CodeInvalidFormat, "20x0", i18n.MustBeNumber, "year"), rows[1].Err)
+						report := entity.NewImportReport(*opts, 2)
+						report.Valid = 1
+						report.Reject(3, rows[1].Err)
+						return report, nil
+					})
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `{"mode":"best-effort","dryRun":true,"total":2,"valid":1,"imported":0,"failed":1,"errors":[` +
+				`{"line":3,"message":"year must be a number","errors":[{"field":"year","code":"invalid_format","value":"20x0","message":"year must be a number"}]}]}`,
+		},
+		{
+			name: "Should return unprocessable entity when an all or nothing import is rejected",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().ImportVehicles(gomock.Any(), entity.NewImportOptions(), gomock.Any()).Return(&entity.ImportReport{
+					Mode: entity.ImportModeAllOrNothing, Total: 2, Valid: 1,
+					Errors: []entity.ImportRowError{{Line: 2, Err: &entity.ErrorConflict{Entity: "vehicle", Field: "plate"}}},
+				}, nil)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: `{"mode":"all-or-nothing","dryRun":false,"total":2,"valid":1,"imported":0,"failed":1,"errors":[` +
+				`{"line":2,"message":"vehicle plate already exists"}]}`,
+		},
+		{
+			name:       "Should return bad request for an unknown mode",
+			query:      "?mode=some",
+			setup:      func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "mode must be one of: all-or-nothing, best-effort",
+		},
+		{
+			name: "Should return internal server error",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().ImportVehicles(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/import"+tt.query, strings.NewReader(csv))
+			req.Header.Set("Content-Type", contentTypeCSV)
+			respWriter := httptest.NewRecorder()
+
+			vh.Import(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			if strings.HasPrefix(tt.wantBody, "{") {
+				assert.JSONEq(t, tt.wantBody, respWriter.Body.String())
+				return
+			}
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// language negotiates the language of the response from the Accept-Language
+// header, e.g. "pt-BR,pt;q=0.9,en;q=0.8", falling back to the default one.
+func language(r *http.Request) string {
+	if r == nil {
+		return i18n.Default
+	}
+
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	for _, tag := range tags {
+		if lang, ok := i18n.Match(tag.tag); ok {
+			return lang
+		}
+	}
+	return i18n.Default
+}
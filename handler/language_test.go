@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_language(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{
+			name:           "Should default to english without the header",
+			acceptLanguage: "",
+			want:           "en",
+		},
+		{
+			name:           "Should pick portuguese for a brazilian client",
+			acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8",
+			want:           "pt-BR",
+		},
+		{
+			name:           "Should pick the supported language with the highest quality",
+			acceptLanguage: "en;q=0.5,fr;q=0.9,pt;q=0.7",
+			want:           "pt-BR",
+		},
+		{
+			name:           "Should ignore languages with quality zero",
+			acceptLanguage: "pt;q=0,en-US",
+			want:           "en",
+		},
+		{
+			name:           "Should default to english when no language is supported",
+			acceptLanguage: "fr-FR,de",
+			want:           "en",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			assert.Equal(t, tt.want, language(req))
+		})
+	}
+}
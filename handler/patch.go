@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+)
+
+// readPatch reads the patch document of a PATCH request. A body sent as
+// application/json, or without a Content-Type, is taken as a merge patch.
+// Other media types are left to the usecase to reject.
+func readPatch(r *http.Request) (*entity.Patch, error) {
+	patchType := entity.PatchTypeMerge
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			mediaType = contentType
+		}
+		if mediaType != "application/json" {
+			patchType = mediaType
+		}
+	}
+
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, invalidBody(err)
+	}
+	if len(document) == 0 {
+		return nil, invalidBody(errors.New("empty body"))
+	}
+	var value interface{}
+	err = json.Unmarshal(document, &value)
+	if err != nil {
+		return nil, invalidBody(err)
+	}
+	return &entity.Patch{Type: patchType, Document: document}, nil
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+type listResponse struct {
+	Data       any    `json:"data"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"pageSize"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// parseQueryOptions reads pagination, sorting and filters from the query string.
+// Every parameter not listed in ignore and not used for pagination is a filter,
+// except deleted, which lists the soft deleted records when true.
+func parseQueryOptions(r *http.Request, ignore ...string) (*entity.QueryOptions, error) {
+	opts := entity.NewQueryOptions()
+	query := r.URL.Query()
+
+	var err error
+	if page := query.Get("page"); page != "" {
+		opts.Page, err = strconv.Atoi(page)
+		if err != nil {
+			return nil, invalidNumber("page", page)
+		}
+	}
+	if pageSize := query.Get("pageSize"); pageSize != "" {
+		opts.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			return nil, invalidNumber("pageSize", pageSize)
+		}
+	}
+	if after := query.Get("after"); after != "" {
+		cursor, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return nil, entity.NewErrorInvalidField("after", entity.CodeInvalidFormat, after, i18n.MustBeCursor, "after")
+		}
+		opts.After = uint(cursor)
+	}
+	if deleted := query.Get("deleted"); deleted != "" {
+		opts.Deleted, err = strconv.ParseBool(deleted)
+		if err != nil {
+			return nil, entity.NewErrorInvalidField("deleted", entity.CodeInvalidFormat, deleted, i18n.MustBeBoolean, "deleted")
+		}
+	}
+	opts.Sort = entity.ParseSort(query.Get("sort"))
+
+	reserved := map[string]bool{"page": true, "pageSize": true, "after": true, "deleted": true, "sort": true}
+	for _, key := range ignore {
+		reserved[key] = true
+	}
+	for key, values := range query {
+		if reserved[key] || len(values) == 0 {
+			continue
+		}
+		opts.Filters = append(opts.Filters, entity.ParseFilter(key, values[0]))
+	}
+	return opts, nil
+}
+
+// newListResponse wraps a page of items. The next cursor is only offered when
+// the listing follows the default id order and there are items left.
+func newListResponse(data any, count int, lastId uint, total int64, opts *entity.QueryOptions) listResponse {
+	resp := listResponse{Data: data, Total: total, PageSize: opts.PageSize}
+	hasMore := count == opts.PageSize
+	if opts.After == 0 {
+		resp.Page = opts.Page
+		hasMore = int64(opts.Offset()+count) < total
+	}
+	if hasMore && len(opts.Sort) == 0 && lastId > 0 {
+		resp.NextCursor = strconv.FormatUint(uint64(lastId), 10)
+	}
+	return resp
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+)
+
+// driverResponse is the driver returned by the API. It is decoupled from
+// entity.Driver so the schema can change without breaking clients.
+type driverResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	LastName    string `json:"lastName"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	CPF         string `json:"cpf"`
+	License     string `json:"license"`
+	LicenseType string `json:"licenseType"`
+	Status      string `json:"status"`
+	// dates are sent as YYYY-MM-DD
+	LicenseIssuedAt  *date `json:"licenseIssuedAt"`
+	LicenseExpiresAt *date `json:"licenseExpiresAt"`
+	FirstLicenseAt   *date `json:"firstLicenseAt"`
+	// the external id is null for drivers not mastered in another system
+	ExternalSource *string `json:"externalSource"`
+	ExternalID     *string `json:"externalId"`
+	// Vehicles is only sent when the vehicles were requested
+	Vehicles  []vehicleResponse `json:"vehicles,omitempty"`
+	Version   uint              `json:"version"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+func newDriverResponse(driver *entity.Driver) *driverResponse {
+	if driver == nil {
+		return nil
+	}
+	resp := &driverResponse{
+		ID:               driver.ID,
+		Name:             driver.Name,
+		LastName:         driver.LastName,
+		Email:            driver.Email,
+		Phone:            driver.Phone,
+		CPF:              driver.CPF,
+		License:          driver.License,
+		LicenseType:      driver.LicenseType,
+		Status:           driver.Status,
+		LicenseIssuedAt:  newDate(driver.LicenseIssuedAt),
+		LicenseExpiresAt: newDate(driver.LicenseExpiresAt),
+		FirstLicenseAt:   newDate(driver.FirstLicenseAt),
+		ExternalSource:   driver.ExternalSource,
+		ExternalID:       driver.ExternalID,
+		Version:          driver.Version,
+		CreatedAt:        driver.CreatedAt.UTC(),
+		UpdatedAt:        driver.UpdatedAt.UTC(),
+	}
+	for i := range driver.Vehicles {
+		resp.Vehicles = append(resp.Vehicles, *newVehicleResponse(&driver.Vehicles[i]))
+	}
+	return resp
+}
+
+func newDriverResponses(drivers []*entity.Driver) []*driverResponse {
+	resp := make([]*driverResponse, len(drivers))
+	for i, driver := range drivers {
+		resp[i] = newDriverResponse(driver)
+	}
+	return resp
+}
+
+// vehicleResponse is the vehicle returned by the API.
+type vehicleResponse struct {
+	ID           uint   `json:"id"`
+	Brand        string `json:"brand"`
+	VehicleModel string `json:"vehicleModel"`
+	Year         int    `json:"year"`
+	Class        string `json:"class"`
+	Plate        string `json:"plate"`
+	PlateCountry string `json:"plateCountry"`
+	PlateFormat  string `json:"plateFormat"`
+	Status       string `json:"status"`
+	// DriverID is null while the vehicle is not assigned
+	DriverID *uint `json:"driverId"`
+	// the external id is null for vehicles not mastered in another system
+	ExternalSource *string   `json:"externalSource"`
+	ExternalID     *string   `json:"externalId"`
+	Version        uint      `json:"version"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+func newVehicleResponse(vehicle *entity.Vehicle) *vehicleResponse {
+	if vehicle == nil {
+		return nil
+	}
+	return &vehicleResponse{
+		ID:             vehicle.ID,
+		Brand:          vehicle.Brand,
+		VehicleModel:   vehicle.VehicleModel,
+		Year:           vehicle.Year,
+		Class:          vehicle.Class,
+		Plate:          vehicle.Plate,
+		PlateCountry:   vehicle.PlateCountry,
+		PlateFormat:    vehicle.PlateFormat,
+		Status:         vehicle.Status,
+		DriverID:       vehicle.DriverID,
+		ExternalSource: vehicle.ExternalSource,
+		ExternalID:     vehicle.ExternalID,
+		Version:        vehicle.Version,
+		CreatedAt:      vehicle.CreatedAt.UTC(),
+		UpdatedAt:      vehicle.UpdatedAt.UTC(),
+	}
+}
+
+func newVehicleResponses(vehicles []*entity.Vehicle) []*vehicleResponse {
+	resp := make([]*vehicleResponse, len(vehicles))
+	for i, vehicle := range vehicles {
+		resp[i] = newVehicleResponse(vehicle)
+	}
+	return resp
+}
+
+// assignmentResponse is a period in which a driver was responsible for a vehicle.
+type assignmentResponse struct {
+	ID        uint             `json:"id"`
+	VehicleID uint             `json:"vehicleId"`
+	DriverID  uint             `json:"driverId"`
+	StartedAt time.Time        `json:"startedAt"`
+	EndedAt   *time.Time       `json:"endedAt"`
+	Vehicle   *vehicleResponse `json:"vehicle,omitempty"`
+	Driver    *driverResponse  `json:"driver,omitempty"`
+}
+
+func newAssignmentResponse(assignment *entity.Assignment) *assignmentResponse {
+	if assignment == nil {
+		return nil
+	}
+	resp := &assignmentResponse{
+		ID:        assignment.ID,
+		VehicleID: assignment.VehicleID,
+		DriverID:  assignment.DriverID,
+		StartedAt: assignment.StartedAt.UTC(),
+		Vehicle:   newVehicleResponse(assignment.Vehicle),
+		Driver:    newDriverResponse(assignment.Driver),
+	}
+	if assignment.EndedAt != nil {
+		endedAt := assignment.EndedAt.UTC()
+		resp.EndedAt = &endedAt
+	}
+	return resp
+}
+
+func newAssignmentResponses(assignments []*entity.Assignment) []*assignmentResponse {
+	resp := make([]*assignmentResponse, len(assignments))
+	for i, assignment := range assignments {
+		resp[i] = newAssignmentResponse(assignment)
+	}
+	return resp
+}
+
+// auditEntryResponse is a change of a driver or vehicle in its history.
+type auditEntryResponse struct {
+	ID        uint                  `json:"id"`
+	Entity    string                `json:"entity"`
+	EntityID  uint                  `json:"entityId"`
+	Operation string                `json:"operation"`
+	Actor     string                `json:"actor"`
+	Changes   []auditChangeResponse `json:"changes"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+// auditChangeResponse is the value of a field before and after a change.
+type auditChangeResponse struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func newAuditEntryResponse(entry *entity.AuditEntry) *auditEntryResponse {
+	resp := &auditEntryResponse{
+		ID:        entry.ID,
+		Entity:    entry.EntityType,
+		EntityID:  entry.EntityID,
+		Operation: entry.Operation,
+		Actor:     entry.Actor,
+		Changes:   make([]auditChangeResponse, len(entry.Changes)),
+		CreatedAt: entry.CreatedAt.UTC(),
+	}
+	for i, change := range entry.Changes {
+		resp.Changes[i] = auditChangeResponse{Field: change.Field, Before: change.Before, After: change.After}
+	}
+	return resp
+}
+
+func newAuditEntryResponses(entries []*entity.AuditEntry) []*auditEntryResponse {
+	resp := make([]*auditEntryResponse, len(entries))
+	for i, entry := range entries {
+		resp[i] = newAuditEntryResponse(entry)
+	}
+	return resp
+}
+
+// statusChangeResponse is a change of the status of a driver or vehicle.
+type statusChangeResponse struct {
+	ID        uint      `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newStatusChangeResponses(changes []*entity.StatusChange) []*statusChangeResponse {
+	resp := make([]*statusChangeResponse, len(changes))
+	for i, change := range changes {
+		resp[i] = &statusChangeResponse{
+			ID:        change.ID,
+			From:      change.FromStatus,
+			To:        change.ToStatus,
+			Reason:    change.Reason,
+			Actor:     change.Actor,
+			CreatedAt: change.CreatedAt.UTC(),
+		}
+	}
+	return resp
+}
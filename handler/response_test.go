@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_newDriverResponse(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	driverId := uint(1)
+	source, externalId := "hr", "E-1"
+
+	tests := []struct {
+		name   string
+		driver *entity.Driver
+		want   string
+	}{
+		{
+			name: "Should use camelCase fields and RFC 3339 timestamps",
+			driver: &entity.Driver{
+				Model:            gorm.Model{ID: 1, CreatedAt: createdAt, UpdatedAt: createdAt, DeletedAt: gorm.DeletedAt{Time: createdAt, Valid: true}},
+				Name:             "John",
+				LastName:         "Doe",
+				Email:            "john.doe@example.com",
+				Phone:            "11999999999",
+				CPF:              "52998224725",
+				License:          "12345678900",
+				LicenseType:      entity.LicenseTypeB,
+				Status:           entity.DriverStatusActive,
+				LicenseExpiresAt: &expiresAt,
+				ExternalSource:   &source,
+				ExternalID:       &externalId,
+				Version:          2,
+			},
+			want: `{"id":1,"name":"John","lastName":"Doe","email":"john.doe@example.com","phone":"11999999999",` +
+				`"cpf":"52998224725","license":"12345678900","licenseType":"B","status":"active","licenseIssuedAt":null,` +
+				`"licenseExpiresAt":"2030-01-15","firstLicenseAt":null,"externalSource":"hr","externalId":"E-1","version":2,` +
+				`"createdAt":"2024-05-01T13:00:00Z","updatedAt":"2024-05-01T13:00:00Z"}`,
+		},
+		{
+			name: "Should embed the vehicles of the driver",
+			driver: &entity.Driver{
+				Model: gorm.Model{ID: 1, CreatedAt: createdAt, UpdatedAt: createdAt},
+				Vehicles: []entity.Vehicle{
+					{Model: gorm.Model{ID: 2, CreatedAt: createdAt, UpdatedAt: createdAt}, Plate: "ABC1D23", Status: entity.VehicleStatusInUse, DriverID: &driverId, Version: 1},
+				},
+			},
+			want: `{"id":1,"name":"","lastName":"","email":"","phone":"","cpf":"","license":"","licenseType":"","status":"",` +
+				`"licenseIssuedAt":null,"licenseExpiresAt":null,"firstLicenseAt":null,"externalSource":null,"externalId":null,` +
+				`"vehicles":[{"id":2,"brand":"","vehicleModel":"","year":0,"class":"","plate":"ABC1D23","plateCountry":"",` +
+				`"plateFormat":"","status":"in_use","driverId":1,"externalSource":null,"externalId":null,"version":1,"createdAt":"2024-05-01T13:00:00Z","updatedAt":"2024-05-01T13:00:00Z"}],` +
+				`"version":0,"createdAt":"2024-05-01T13:00:00Z","updatedAt":"2024-05-01T13:00:00Z"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(newDriverResponse(tt.driver))
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_newAssignmentResponse(t *testing.T) {
+	startedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	got, err := json.Marshal(newAssignmentResponse(&entity.Assignment{
+		Model:     gorm.Model{ID: 3},
+		VehicleID: 2,
+		DriverID:  1,
+		StartedAt: startedAt,
+	}))
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id":3,"vehicleId":2,"driverId":1,"startedAt":"2024-05-01T10:00:00Z","endedAt":null}`, string(got))
+}
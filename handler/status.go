@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+)
+
+// statusRequest moves a driver or vehicle to another status.
+type statusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type StatusHandler struct {
+	StatusUsecase usecase.StatusUsecase
+}
+
+func (sh StatusHandler) GetByDriver(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	changes, err := sh.StatusUsecase.GetByDriver(driverId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newStatusChangeResponses(changes))
+}
+
+func (sh StatusHandler) GetByVehicle(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	changes, err := sh.StatusUsecase.GetByVehicle(vehicleId)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newStatusChangeResponses(changes))
+}
+
+// ChangeDriverStatus moves the driver to the status of the request body and
+// returns it.
+func (sh StatusHandler) ChangeDriverStatus(w http.ResponseWriter, r *http.Request) {
+	driverId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("driverId", r.PathValue("id")))
+		return
+	}
+
+	statusReq := new(statusRequest)
+	err = json.NewDecoder(r.Body).Decode(statusReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+
+	driver, err := sh.StatusUsecase.ChangeDriverStatus(driverId, version, statusReq.Status, statusReq.Reason, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(driver.Version))
+	json.NewEncoder(w).Encode(newDriverResponse(driver))
+}
+
+// ChangeVehicleStatus moves the vehicle to the status of the request body
+// and returns it.
+func (sh StatusHandler) ChangeVehicleStatus(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	statusReq := new(statusRequest)
+	err = json.NewDecoder(r.Body).Decode(statusReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+
+	vehicle, err := sh.StatusUsecase.ChangeVehicleStatus(vehicleId, version, statusReq.Status, statusReq.Reason, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(vehicle.Version))
+	json.NewEncoder(w).Encode(newVehicleResponse(vehicle))
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/usecase"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestStatusHandler_GetByVehicle(t *testing.T) {
+	createdAt := time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		pathValue  string
+		setup      func(mockStatusUsecase *usecase.MockStatusUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:      "Should return the status history of the vehicle",
+			pathValue: "2",
+			setup: func(mockStatusUsecase *usecase.MockStatusUsecase) {
+				mockStatusUsecase.EXPECT().GetByVehicle(2).Return([]*entity.StatusChange{{
+					ID: 4, EntityType: entity.AuditEntityVehicle, EntityID: 2, FromStatus: entity.VehicleStatusAvailable,
+					ToStatus: entity.VehicleStatusInMaintenance, Reason: "oil change", Actor: "maria", CreatedAt: createdAt,
+				}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `[{"id":4,"from":"available","to":"in_maintenance","reason":"oil change","actor":"maria",` +
+				`"createdAt":"2024-05-02T09:30:00Z"}]`,
+		},
+		{
+			name:       "Should return bad request when id is not a number",
+			pathValue:  "abc",
+			setup:      func(mockStatusUsecase *usecase.MockStatusUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "vehicleId must be a number",
+		},
+		{
+			name:      "Should return not found",
+			pathValue: "2",
+			setup: func(mockStatusUsecase *usecase.MockStatusUsecase) {
+				mockStatusUsecase.EXPECT().GetByVehicle(2).Return(nil, usecase.ErrVehicleNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "vehicle not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStatusUsecase := usecase.NewMockStatusUsecase(ctrl)
+			tt.setup(mockStatusUsecase)
+
+			sh := StatusHandler{StatusUsecase: mockStatusUsecase}
+
+			req := httptest.NewRequest(http.MethodGet, "/vehicles/"+tt.pathValue+"/status-history", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			sh.GetByVehicle(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, tt.wantBody, respWriter.Body.String())
+				return
+			}
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestStatusHandler_ChangeDriverStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathValue  string
+		body       string
+		ifMatch    string
+		setup      func(mockStatusUsecase *usecase.MockStatusUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:      "Should change the status of the driver",
+			pathValue: "1",
+			body:      `{"status":"on_leave","reason":"vacation"}`,
+			ifMatch:   `"2"`,
+			setup: func(mockStatusUsecase *usecase.MockStatusUsecase) {
+				mockStatusUsecase.EXPECT().ChangeDriverStatus(1, uint(2), entity.DriverStatusOnLeave, "vacation", gomock.Any()).
+					Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusOnLeave, Version: 3}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"on_leave"`,
+		},
+		{
+			name:       "Should return bad request error when driverId is not a number",
+			pathValue:  "abc",
+			body:       `{}`,
+			setup:      func(mockStatusUsecase *usecase.MockStatusUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "driverId must be a number",
+		},
+		{
+			name:       "Should return bad request error when body is invalid",
+			pathValue:  "1",
+			body:       `{"status":`,
+			setup:      func(mockStatusUsecase *usecase.MockStatusUsecase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Should return precondition failed when If-Match is invalid",
+			pathValue:  "1",
+			body:       `{"status":"on_leave","reason":"vacation"}`,
+			ifMatch:    "abc",
+			setup:      func(mockStatusUsecase *usecase.MockStatusUsecase) {},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:      "Should return unprocessable entity when the transition is not allowed",
+			pathValue: "1",
+			body:      `{"status":"active","reason":"rehired"}`,
+			setup: func(mockStatusUsecase *usecase.MockStatusUsecase) {
+				mockStatusUsecase.EXPECT().ChangeDriverStatus(1, uint(0), entity.DriverStatusActive, "rehired", gomock.Any()).
+					Return(nil, domainerr.New(domainerr.Unprocessable, i18n.StatusTransitionInvalid, entity.DriverStatusTerminated, entity.DriverStatusActive))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   "status can not change from terminated to active",
+		},
+		{
+			name:      "Should return internal server error",
+			pathValue: "1",
+			body:      `{"status":"on_leave","reason":"vacation"}`,
+			setup: func(mockStatusUsecase *usecase.MockStatusUsecase) {
+				mockStatusUsecase.EXPECT().ChangeDriverStatus(1, uint(0), entity.DriverStatusOnLeave, "vacation", gomock.Any()).
+					Return(nil, errors.New("some error occurred"))
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStatusUsecase := usecase.NewMockStatusUsecase(ctrl)
+			tt.setup(mockStatusUsecase)
+
+			sh := StatusHandler{StatusUsecase: mockStatusUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/drivers/{id}/status", strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.pathValue)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			respWriter := httptest.NewRecorder()
+
+			sh.ChangeDriverStatus(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, `"3"`, respWriter.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestStatusHandler_ChangeVehicleStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStatusUsecase := usecase.NewMockStatusUsecase(ctrl)
+	mockStatusUsecase.EXPECT().ChangeVehicleStatus(2, uint(0), entity.VehicleStatusInMaintenance, "oil change", gomock.Any()).
+		Return(nil, usecase.ErrVehicleInUse)
+
+	sh := StatusHandler{StatusUsecase: mockStatusUsecase}
+
+	req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/status", strings.NewReader(`{"status":"in_maintenance","reason":"oil change"}`))
+	req.SetPathValue("id", "2")
+	respWriter := httptest.NewRecorder()
+
+	sh.ChangeVehicleStatus(respWriter, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, respWriter.Code)
+	assert.Contains(t, respWriter.Body.String(), "vehicle is in use")
+}
@@ -2,20 +2,37 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"reflect"
 	"strconv"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/usecase"
 )
 
 type vehicleRequest struct {
 	Plate        string `json:"plate"`
+	PlateCountry string `json:"plateCountry"`
 	Brand        string `json:"brand"`
 	VehicleModel string `json:"vehicleModel"`
 	Year         int    `json:"year"`
+	Class        string `json:"class"`
+	// the id of the vehicle in the system it is mastered in, e.g. the ERP
+	ExternalSource *string `json:"externalSource"`
+	ExternalID     *string `json:"externalId"`
+}
+
+func (vr vehicleRequest) toEntity() *entity.Vehicle {
+	return &entity.Vehicle{
+		Plate:          vr.Plate,
+		PlateCountry:   vr.PlateCountry,
+		Brand:          vr.Brand,
+		VehicleModel:   vr.VehicleModel,
+		Year:           vr.Year,
+		Class:          vr.Class,
+		ExternalSource: vr.ExternalSource,
+		ExternalID:     vr.ExternalID,
+	}
 }
 
 type VehicleHandler struct {
@@ -23,69 +40,221 @@ type VehicleHandler struct {
 }
 
 func (vh VehicleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	vehicles, err := vh.VehicleUsecase.GetAll()
+	opts, err := parseVehicleQueryOptions(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	vehicles, total, err := vh.VehicleUsecase.GetAll(opts)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	var lastId uint
+	if len(vehicles) > 0 {
+		lastId = vehicles[len(vehicles)-1].ID
+	}
+	json.NewEncoder(w).Encode(newListResponse(newVehicleResponses(vehicles), len(vehicles), lastId, total, opts))
+}
+
+func (vh VehicleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var vehicleReq vehicleRequest
+	err := json.NewDecoder(r.Body).Decode(&vehicleReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	err = vh.VehicleUsecase.Create(vehicleReq.toEntity(), actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Export writes the vehicles matching the filters of the listing as a CSV,
+// XLSX or NDJSON file, read from the repository a batch at a time.
+func (vh VehicleHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format, err := parseExportFormat(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	opts, err := parseVehicleQueryOptions(r, "format")
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	export := newExport(w, format, "vehicles", vehicleExportColumns)
+	err = vh.VehicleUsecase.Export(opts, func(vehicles []*entity.Vehicle) error {
+		for _, vehicle := range vehicles {
+			resp := newVehicleResponse(vehicle)
+			err := export.write(resp.record(), resp)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = export.close()
+	}
+	if err != nil {
+		export.fail(r, err)
+	}
+}
+
+// GetByExternalId returns the vehicle with the id of the system it is mastered in.
+func (vh VehicleHandler) GetByExternalId(w http.ResponseWriter, r *http.Request) {
+	vehicle, err := vh.VehicleUsecase.GetByExternalId(r.PathValue("source"), r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
-	json.NewEncoder(w).Encode(vehicles)
+	if vehicle == nil {
+		errorHandler(w, r, usecase.ErrVehicleNotFound)
+		return
+	}
+	w.Header().Set("ETag", etag(vehicle.Version))
+	json.NewEncoder(w).Encode(newVehicleResponse(vehicle))
+}
+
+// ExternalId lets the path value param reference the vehicle by its external
+// id as source:id, e.g. /vehicles/erp:TRK-0042, before calling next.
+func (vh VehicleHandler) ExternalId(param string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := resolveExternalRef(r, param, "vehicleId", func(source string, externalId string) (uint, error) {
+			vehicle, err := vh.VehicleUsecase.GetByExternalId(source, externalId)
+			if err != nil {
+				return 0, err
+			}
+			if vehicle == nil {
+				return 0, usecase.ErrVehicleNotFound
+			}
+			return vehicle.ID, nil
+		})
+		if err != nil {
+			errorHandler(w, r, err)
+			return
+		}
+		next(w, r)
+	}
 }
 
 func (vh VehicleHandler) GetById(w http.ResponseWriter, r *http.Request) {
 	vehicleId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("vehicleId must be a number"))
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
 		return
 	}
 
 	vehicle, err := vh.VehicleUsecase.GetById(vehicleId)
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
 	if vehicle == nil {
-		errorHandler(w, http.StatusNotFound, fmt.Errorf("vehicle not found"))
+		errorHandler(w, r, usecase.ErrVehicleNotFound)
 		return
 	}
-	json.NewEncoder(w).Encode(vehicle)
+	w.Header().Set("ETag", etag(vehicle.Version))
+	json.NewEncoder(w).Encode(newVehicleResponse(vehicle))
+}
+
+// Import creates the vehicles of a CSV or XLSX file, see readImportFile.
+func (vh VehicleHandler) Import(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseImportOptions(r)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	rows, err := readImportFile(w, r, vehicleImportColumns)
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	vehicleRows := make([]entity.VehicleImportRow, len(rows))
+	for i, row := range rows {
+		vehicleRows[i] = row.toVehicle()
+	}
+	report, err := vh.VehicleUsecase.ImportVehicles(vehicleRows, opts, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	writeImportReport(w, r, report)
 }
 
 func (vh VehicleHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vehicleId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("vehicleId must be a number"))
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
 		return
 	}
 
-	var vehicle vehicleRequest
-	err = json.NewDecoder(r.Body).Decode(&vehicle)
+	patch, err := readPatch(r)
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("invalid request body"))
+		errorHandler(w, r, err)
 		return
 	}
 
-	updateVehicle := &entity.Vehicle{
-		Plate:        vehicle.Plate,
-		Brand:        vehicle.Brand,
-		VehicleModel: vehicle.VehicleModel,
-		Year:         vehicle.Year,
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
 	}
+	patch.Version = version
 
-	err = vh.VehicleUsecase.Update(vehicleId, updateVehicle)
+	err = vh.VehicleUsecase.Update(vehicleId, patch, actor(r))
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		if err == usecase.ErrVehicleNotFound {
-			errorHandler(w, http.StatusNotFound, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Replace overwrites the vehicle with the request body. An unknown numeric id
+// returns 404 Not Found; only a vehicle referenced by its external id
+// (source:id) is created when it does not exist.
+func (vh VehicleHandler) Replace(w http.ResponseWriter, r *http.Request) {
+	ref, err := parsePathRef(r.PathValue("id"), "vehicleId")
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+
+	var vehicleReq vehicleRequest
+	err = json.NewDecoder(r.Body).Decode(&vehicleReq)
+	if err != nil {
+		errorHandler(w, r, invalidBody(err))
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
+		return
+	}
+	vehicle := vehicleReq.toEntity()
+	vehicle.Version = version
+
+	var created bool
+	if ref.external() {
+		created, err = vh.VehicleUsecase.ReplaceByExternalId(ref.source, ref.externalId, vehicle, actor(r))
+	} else {
+		created, err = vh.VehicleUsecase.Replace(ref.id, vehicle, actor(r))
+	}
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -94,18 +263,74 @@ func (vh VehicleHandler) Update(w http.ResponseWriter, r *http.Request) {
 func (vh VehicleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vehicleId, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		errorHandler(w, http.StatusBadRequest, fmt.Errorf("vehicleId must be a number"))
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	version, ok := parseIfMatch(r)
+	if !ok {
+		preconditionFailed(w, r)
 		return
 	}
 
-	err = vh.VehicleUsecase.Delete(vehicleId)
+	err = vh.VehicleUsecase.Delete(vehicleId, version, actor(r))
 	if err != nil {
-		if reflect.TypeOf(err).String() == "*entity.ErrorInvalidField" {
-			errorHandler(w, http.StatusBadRequest, err)
-			return
-		}
-		errorHandler(w, http.StatusInternalServerError, err)
+		errorHandler(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+// Restore undeletes a deleted vehicle and returns it, without a driver.
+func (vh VehicleHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	vehicle, err := vh.VehicleUsecase.Restore(vehicleId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag(vehicle.Version))
+	json.NewEncoder(w).Encode(newVehicleResponse(vehicle))
+}
+
+// Purge removes a deleted vehicle for good. It is restricted to admins.
+func (vh VehicleHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	vehicleId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorHandler(w, r, invalidNumber("vehicleId", r.PathValue("id")))
+		return
+	}
+
+	err = vh.VehicleUsecase.Purge(vehicleId, actor(r))
+	if err != nil {
+		errorHandler(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseVehicleQueryOptions reads the query options of a vehicle listing,
+// where unassigned=true filters the vehicles without a driver.
+func parseVehicleQueryOptions(r *http.Request, ignore ...string) (*entity.QueryOptions, error) {
+	opts, err := parseQueryOptions(r, append(ignore, "unassigned")...)
+	if err != nil {
+		return nil, err
+	}
+	if value := r.URL.Query().Get("unassigned"); value != "" {
+		unassigned, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, entity.NewErrorInvalidField("unassigned", entity.CodeInvalidFormat, value, i18n.MustBeBoolean, "unassigned")
+		}
+		opts.Filters = append(opts.Filters, entity.Filter{
+			Field:    "driverId",
+			Operator: entity.FilterOperatorNull,
+			Value:    strconv.FormatBool(unassigned),
+		})
+	}
+	return opts, nil
+}
@@ -7,31 +7,87 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/usecase"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestVehicleHandler_GetAll(t *testing.T) {
 	tests := []struct {
-		name    string
-		setup   func(mockVehicleUsecase *usecase.MockVehicleUsecase)
-		wantErr bool
+		name       string
+		query      string
+		setup      func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantStatus int
+		wantBody   string
 	}{
 		{
 			name: "Should return all vehicles",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().GetAll().Return(make([]*entity.Vehicle, 0), nil)
+				mockVehicleUsecase.EXPECT().GetAll(entity.NewQueryOptions()).Return(make([]*entity.Vehicle, 0), int64(0), nil)
 			},
-			wantErr: false,
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":0`,
+		},
+		{
+			name:  "Should return next cursor when there are more vehicles",
+			query: "?pageSize=1&yearFrom=2015",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     1,
+					PageSize: 1,
+					Filters:  []entity.Filter{{Field: "year", Operator: entity.FilterOperatorGreaterThan, Value: "2015"}},
+				}).Return([]*entity.Vehicle{{Model: gorm.Model{ID: 7}}}, int64(3), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"nextCursor":"7"`,
+		},
+		{
+			name:  "Should filter unassigned vehicles",
+			query: "?unassigned=true",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     1,
+					PageSize: entity.DefaultPageSize,
+					Filters:  []entity.Filter{{Field: "driverId", Operator: entity.FilterOperatorNull, Value: "true"}},
+				}).Return(make([]*entity.Vehicle, 0), int64(0), nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"total":0`,
+		},
+		{
+			name:       "Should return bad request error when unassigned is not a boolean",
+			query:      "?unassigned=maybe",
+			setup:      func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "unassigned must be a boolean",
+		},
+		{
+			name:       "Should return bad request error when page is not a number",
+			query:      "?page=abc",
+			setup:      func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "page must be a number",
+		},
+		{
+			name:  "Should return bad request error when query options are invalid",
+			query: "?sort=unknown",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), entity.NewErrorInvalidField("sort", entity.CodeUnknownField, "unknown", "sort unknown is invalid"))
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "sort unknown is invalid",
 		},
 		{
 			name: "Should return error",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().GetAll().Return(nil, fmt.Errorf("some error occurred"))
+				mockVehicleUsecase.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), fmt.Errorf("some error occurred"))
 			},
-			wantErr: true,
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "some error occurred",
 		},
 	}
 	for _, tt := range tests {
@@ -44,15 +100,91 @@ func TestVehicleHandler_GetAll(t *testing.T) {
 				VehicleUsecase: mockVehicleUsecase,
 			}
 
-			req := httptest.NewRequest(http.MethodGet, "/vehicles", nil)
+			req := httptest.NewRequest(http.MethodGet, "/vehicles"+tt.query, nil)
 			respWritter := httptest.NewRecorder()
 
 			vh.GetAll(respWritter, req)
-			if tt.wantErr {
-				assert.Equal(t, http.StatusInternalServerError, respWritter.Code)
-				return
+			assert.Contains(t, respWritter.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.wantStatus, respWritter.Code)
+		})
+	}
+}
+
+func TestVehicleHandler_Create(t *testing.T) {
+	mockBody := `{"plate": "ABC1D23", "brand": "Volvo", "vehicleModel": "FH 540", "year": 2021, "class": "heavy_truck"}`
+	tests := []struct {
+		name         string
+		requestBody  string
+		setup        func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:        "Should create vehicle",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Create(&entity.Vehicle{
+					Plate:        "ABC1D23",
+					Brand:        "Volvo",
+					VehicleModel: "FH 540",
+					Year:         2021,
+					Class:        "heavy_truck",
+				}, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusCreated,
+			wantError: false,
+		},
+		{
+			name:         "Should return bad request error when request body is invalid",
+			requestBody:  `{"plate"}`,
+			setup:        func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "invalid request body",
+		},
+		{
+			name:        "Should return conflict error when plate already exists",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.ErrorConflict{Entity: "vehicle", Field: "plate"})
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: "vehicle plate already exists",
+		},
+		{
+			name:        "Should return internal server error",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Create(gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error occurred"))
+			},
+			wantCode:     http.StatusInternalServerError,
+			wantError:    true,
+			wantErrorMsg: "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{
+				VehicleUsecase: mockVehicleUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles", strings.NewReader(tt.requestBody))
+			respWriter := httptest.NewRecorder()
+
+			vh.Create(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			} else {
+				assert.Empty(t, respWriter.Body.String())
 			}
-			assert.Equal(t, http.StatusOK, respWritter.Code)
+			assert.Equal(t, tt.wantCode, respWriter.Code)
 		})
 	}
 }
@@ -87,9 +219,7 @@ func TestVehicleHandler_GetById(t *testing.T) {
 			name:      "Should return bad request error when vehicleId is invalid",
 			pathValue: "0",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().GetById(0).Return(nil, &entity.ErrorInvalidField{
-					Message: []string{"vehicle id is invalid"},
-				})
+				mockVehicleUsecase.EXPECT().GetById(0).Return(nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, i18n.VehicleIdInvalid))
 			},
 			wantCode:     http.StatusBadRequest,
 			wantError:    true,
@@ -146,6 +276,7 @@ func TestVehicleHandler_Update(t *testing.T) {
 		name         string
 		pathValue    string
 		requestBody  string
+		contentType  string
 		setup        func(mockVehicleUsecase *usecase.MockVehicleUsecase)
 		wantCode     int
 		wantError    bool
@@ -156,16 +287,77 @@ func TestVehicleHandler_Update(t *testing.T) {
 			pathValue:   "1",
 			requestBody: mockBody,
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Update(1, &entity.Vehicle{
-					Plate:        "ABC123",
-					Brand:        "Toyota",
-					VehicleModel: "Corolla",
-					Year:         2022,
-				}).Return(nil)
+				mockVehicleUsecase.EXPECT().Update(1, &entity.Patch{
+					Type:     entity.PatchTypeMerge,
+					Document: []byte(mockBody),
+				}, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:        "Should update vehicle with a merge patch",
+			pathValue:   "1",
+			requestBody: `{"brand": "Volvo", "plateCountry": null}`,
+			contentType: "application/merge-patch+json; charset=utf-8",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, &entity.Patch{
+					Type:     entity.PatchTypeMerge,
+					Document: []byte(`{"brand": "Volvo", "plateCountry": null}`),
+				}, gomock.Any()).Return(nil)
 			},
 			wantCode:  http.StatusOK,
 			wantError: false,
 		},
+		{
+			name:        "Should update vehicle with a JSON patch",
+			pathValue:   "1",
+			requestBody: `[{"op": "replace", "path": "/brand", "value": "Volvo"}]`,
+			contentType: "application/json-patch+json",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, &entity.Patch{
+					Type:     entity.PatchTypeJSON,
+					Document: []byte(`[{"op": "replace", "path": "/brand", "value": "Volvo"}]`),
+				}, gomock.Any()).Return(nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:        "Should return unsupported media type error",
+			pathValue:   "1",
+			requestBody: `{"brand": "Volvo"}`,
+			contentType: "application/xml",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(
+					domainerr.New(domainerr.Unsupported, i18n.MediaTypeUnsupported, "application/xml", entity.PatchTypeMerge))
+			},
+			wantCode:     http.StatusUnsupportedMediaType,
+			wantError:    true,
+			wantErrorMsg: "media type application/xml is not supported",
+		},
+		{
+			name:        "Should return conflict error when a JSON patch test fails",
+			pathValue:   "1",
+			requestBody: `[{"op": "test", "path": "/brand", "value": "Fiat"}]`,
+			contentType: "application/json-patch+json",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(1, gomock.Any(), gomock.Any()).Return(
+					domainerr.New(domainerr.Conflict, i18n.PatchTestFailed, "/brand"))
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: "patch test operation failed",
+		},
+		{
+			name:         "Should return bad request error when request body is empty",
+			pathValue:    "1",
+			requestBody:  "",
+			setup:        func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "invalid request body",
+		},
 		{
 			name:         "Should return bad request error when vehicleId is not a number",
 			pathValue:    "abc",
@@ -189,7 +381,7 @@ func TestVehicleHandler_Update(t *testing.T) {
 			pathValue:   "2",
 			requestBody: mockBody,
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Update(2, gomock.Any()).Return(usecase.ErrVehicleNotFound)
+				mockVehicleUsecase.EXPECT().Update(2, gomock.Any(), gomock.Any()).Return(usecase.ErrVehicleNotFound)
 			},
 			wantCode:     http.StatusNotFound,
 			wantError:    true,
@@ -200,20 +392,44 @@ func TestVehicleHandler_Update(t *testing.T) {
 			pathValue:   "3",
 			requestBody: `{"brand": "T"}`,
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Update(3, gomock.Any()).Return(&entity.ErrorInvalidField{
-					Message: []string{"vehicle brand is invalid"},
-				})
+				mockVehicleUsecase.EXPECT().Update(3, gomock.Any(), gomock.Any()).Return(entity.NewErrorInvalidField("brand", entity.CodeTooShort, "T", i18n.VehicleBrandInvalid))
 			},
 			wantCode:     http.StatusBadRequest,
 			wantError:    true,
 			wantErrorMsg: "vehicle brand is invalid",
 		},
+		{
+			name:        "Should return unprocessable entity error when class is not allowed for the driver",
+			pathValue:   "3",
+			requestBody: `{"class": "bus"}`,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(3, gomock.Any(), gomock.Any()).Return(&entity.ErrorIncompatibleLicense{
+					LicenseType:  "B",
+					VehicleClass: "bus",
+					Plate:        "ABC1234",
+				})
+			},
+			wantCode:     http.StatusUnprocessableEntity,
+			wantError:    true,
+			wantErrorMsg: "driver license type B does not allow driving vehicle ABC1234 of class bus",
+		},
+		{
+			name:        "Should return conflict error when plate already exists",
+			pathValue:   "3",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Update(3, gomock.Any(), gomock.Any()).Return(&entity.ErrorConflict{Entity: "vehicle", Field: "plate"})
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: "vehicle plate already exists",
+		},
 		{
 			name:        "Should return internal server error",
 			pathValue:   "3",
 			requestBody: mockBody,
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Update(3, gomock.Any()).Return(fmt.Errorf("some error occurred"))
+				mockVehicleUsecase.EXPECT().Update(3, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error occurred"))
 			},
 			wantCode:     http.StatusInternalServerError,
 			wantError:    true,
@@ -231,8 +447,11 @@ func TestVehicleHandler_Update(t *testing.T) {
 				VehicleUsecase: mockVehicleUsecase,
 			}
 
-			req := httptest.NewRequest(http.MethodPut, "/vehicles/{id}", strings.NewReader(tt.requestBody))
+			req := httptest.NewRequest(http.MethodPatch, "/vehicles/{id}", strings.NewReader(tt.requestBody))
 			req.SetPathValue("id", tt.pathValue)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
 			respWriter := httptest.NewRecorder()
 
 			vh.Update(respWriter, req)
@@ -244,6 +463,118 @@ func TestVehicleHandler_Update(t *testing.T) {
 	}
 }
 
+func TestVehicleHandler_Replace(t *testing.T) {
+	mockBody := `{"plate": "ABC1D23", "brand": "Toyota", "vehicleModel": "Corolla", "year": 2022, "class": "car"}`
+	tests := []struct {
+		name         string
+		pathValue    string
+		requestBody  string
+		setup        func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantCode     int
+		wantError    bool
+		wantErrorMsg string
+	}{
+		{
+			name:        "Should return ok when the vehicle is replaced",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Replace(1, &entity.Vehicle{
+					Plate:        "ABC1D23",
+					Brand:        "Toyota",
+					VehicleModel: "Corolla",
+					Year:         2022,
+					Class:        "car",
+				}, gomock.Any()).Return(false, nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:        "Should return created when the vehicle does not exist",
+			pathValue:   "10",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Replace(10, gomock.Any(), gomock.Any()).Return(true, nil)
+			},
+			wantCode:  http.StatusCreated,
+			wantError: false,
+		},
+		{
+			name:        "Should replace the vehicle by its external id",
+			pathValue:   "erp:TRK-0042",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().ReplaceByExternalId("erp", "TRK-0042", gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			wantCode:  http.StatusOK,
+			wantError: false,
+		},
+		{
+			name:         "Should return bad request error when vehicleId is not a number",
+			pathValue:    "abc",
+			requestBody:  mockBody,
+			setup:        func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "vehicleId must be a number",
+		},
+		{
+			name:         "Should return bad request error when request body is invalid",
+			pathValue:    "1",
+			requestBody:  `{"plate"}`,
+			setup:        func(mockVehicleUsecase *usecase.MockVehicleUsecase) {},
+			wantCode:     http.StatusBadRequest,
+			wantError:    true,
+			wantErrorMsg: "invalid request body",
+		},
+		{
+			name:        "Should return conflict error when plate already exists",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Replace(1, gomock.Any(), gomock.Any()).Return(false, &entity.ErrorConflict{Entity: "vehicle", Field: "plate"})
+			},
+			wantCode:     http.StatusConflict,
+			wantError:    true,
+			wantErrorMsg: "vehicle plate already exists",
+		},
+		{
+			name:        "Should return internal server error",
+			pathValue:   "1",
+			requestBody: mockBody,
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Replace(1, gomock.Any(), gomock.Any()).Return(false, fmt.Errorf("some error occurred"))
+			},
+			wantCode:     http.StatusInternalServerError,
+			wantError:    true,
+			wantErrorMsg: "some error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{
+				VehicleUsecase: mockVehicleUsecase,
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/vehicles/{id}", strings.NewReader(tt.requestBody))
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			vh.Replace(respWriter, req)
+			if tt.wantError {
+				assert.Contains(t, respWriter.Body.String(), tt.wantErrorMsg)
+			}
+			assert.Equal(t, tt.wantCode, respWriter.Code)
+		})
+	}
+}
+
 func TestVehicleHandler_Delete(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -257,7 +588,7 @@ func TestVehicleHandler_Delete(t *testing.T) {
 			name:      "Should delete vehicle successfully",
 			pathValue: "1",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Delete(1).Return(nil)
+				mockVehicleUsecase.EXPECT().Delete(1, uint(0), gomock.Any()).Return(nil)
 			},
 			wantCode:  http.StatusOK,
 			wantError: false,
@@ -274,9 +605,7 @@ func TestVehicleHandler_Delete(t *testing.T) {
 			name:      "Should return bad request error when vehicleId is invalid",
 			pathValue: "0",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Delete(0).Return(&entity.ErrorInvalidField{
-					Message: []string{"vehicle id is invalid"},
-				})
+				mockVehicleUsecase.EXPECT().Delete(0, uint(0), gomock.Any()).Return(entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, i18n.VehicleIdInvalid))
 			},
 			wantCode:     http.StatusBadRequest,
 			wantError:    true,
@@ -286,7 +615,7 @@ func TestVehicleHandler_Delete(t *testing.T) {
 			name:      "Should return internal server error",
 			pathValue: "3",
 			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
-				mockVehicleUsecase.EXPECT().Delete(3).Return(fmt.Errorf("some error occurred"))
+				mockVehicleUsecase.EXPECT().Delete(3, uint(0), gomock.Any()).Return(fmt.Errorf("some error occurred"))
 			},
 			wantCode:     http.StatusInternalServerError,
 			wantError:    true,
@@ -316,3 +645,109 @@ func TestVehicleHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestVehicleHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathValue  string
+		setup      func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:      "Should restore the vehicle without a driver",
+			pathValue: "1",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Restore(1, gomock.Any()).Return(&entity.Vehicle{Model: gorm.Model{ID: 1}, Plate: "ABC1D23", Version: 3}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `"driverId":null`,
+		},
+		{
+			name:      "Should return not found",
+			pathValue: "1",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Restore(1, gomock.Any()).Return(nil, usecase.ErrVehicleNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+			wantBody:   "vehicle not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/restore", nil)
+			req.SetPathValue("id", tt.pathValue)
+			respWriter := httptest.NewRecorder()
+
+			vh.Restore(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestVehicleHandler_Purge(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		setup      func(mockVehicleUsecase *usecase.MockVehicleUsecase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:  "Should purge the vehicle",
+			token: "secret",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Purge(1, entity.NewActor("root", entity.RoleAdmin)).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:  "Should return forbidden without the admin token",
+			token: "",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Purge(1, entity.NewActor("root", "")).Return(usecase.ErrPurgeNotAllowed)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   "only admins can purge records",
+		},
+		{
+			name:  "Should return forbidden when the admin token does not match",
+			token: "other",
+			setup: func(mockVehicleUsecase *usecase.MockVehicleUsecase) {
+				mockVehicleUsecase.EXPECT().Purge(1, entity.NewActor("root", "")).Return(usecase.ErrPurgeNotAllowed)
+			},
+			wantStatus: http.StatusForbidden,
+			wantBody:   "only admins can purge records",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleUsecase := usecase.NewMockVehicleUsecase(ctrl)
+			tt.setup(mockVehicleUsecase)
+
+			vh := VehicleHandler{VehicleUsecase: mockVehicleUsecase}
+
+			req := httptest.NewRequest(http.MethodPost, "/vehicles/{id}/purge", nil)
+			req.SetPathValue("id", "1")
+			req.Header.Set(headerActor, "root")
+			if tt.token != "" {
+				req.Header.Set(headerAdminToken, tt.token)
+			}
+			respWriter := httptest.NewRecorder()
+
+			AdminHandler{Token: "secret"}.Middleware(vh.Purge)(respWriter, req)
+			assert.Equal(t, tt.wantStatus, respWriter.Code)
+			assert.Contains(t, respWriter.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package i18n
+
+var en = map[string]string{
+	DriverNameInvalid:               "driver name is invalid",
+	DriverLastNameInvalid:           "driver last name is invalid",
+	DriverEmailInvalid:              "driver email is invalid",
+	DriverPhoneInvalid:              "driver phone is invalid",
+	DriverCPFRequired:               "driver cpf is required",
+	DriverCPFInvalidLength:          "driver cpf must have 11 digits",
+	DriverCPFInvalidCheckDigits:     "driver cpf check digits are invalid",
+	DriverLicenseRequired:           "driver license is required",
+	DriverLicenseInvalidLength:      "driver license must have 11 digits",
+	DriverLicenseInvalidCheckDigits: "driver license check digits are invalid",
+	DriverLicenseTypeInvalid:        "driver license type is invalid",
+	DriverLicenseIssuedAtInvalid:    "driver license issue date is invalid",
+	DriverLicenseExpiresAtInvalid:   "driver license expiration date must be after issue date",
+	DriverFirstLicenseAtInvalid:     "driver first license date must not be after issue date",
+	DriverIdInvalid:                 "driver id is invalid",
+	DriverRequired:                  "driver is invalid",
+	DriverNotFound:                  "driver not found",
+	DriverLicenseExpired:            "driver license is expired",
+	DriverLicenseIncompatible:       "driver license type %s does not allow driving vehicle %s of class %s, allowed classes: %s",
+	DriverEmailConflict:             "driver email already exists",
+	DriverCPFConflict:               "driver cpf already exists",
+	DriverLicenseConflict:           "driver license already exists",
+	DriverExternalIdConflict:        "driver external id already exists",
+
+	VehicleBrandInvalid:            "vehicle brand is invalid",
+	VehicleModelInvalid:            "vehicle model is invalid",
+	VehicleYearInvalid:             "vehicle year is invalid",
+	VehicleClassInvalid:            "vehicle class is invalid",
+	VehicleClassNone:               "none",
+	VehiclePlateInvalid:            "vehicle plate is invalid",
+	VehiclePlateConflict:           "vehicle plate already exists",
+	VehicleExternalIdConflict:      "vehicle external id already exists",
+	VehicleIdInvalid:               "vehicle id is invalid",
+	VehicleRequired:                "vehicle is invalid",
+	VehicleNotFound:                "vehicle not found",
+	VehicleNotOwned:                "vehicle is not assigned to the driver",
+	VehicleAlreadyAssigned:         "vehicle is already assigned to a driver",
+	VehicleAlreadyAssignedToDriver: "vehicle is already assigned to this driver",
+	VehicleNotAssigned:             "vehicle is not assigned to a driver",
+	VehicleDriverChanged:           "vehicle driver changed, reload the vehicle and try again",
+	AssignmentNotFound:             "no driver was assigned to the vehicle at the given time",
+
+	PageInvalid:              "page is invalid",
+	PageSizeOutOfRange:       "pageSize must be between 1 and %d",
+	SortNotAllowed:           "sort can not be combined with after",
+	SortUnknownField:         "sort %s is invalid",
+	FilterUnknownField:       "filter %s is invalid",
+	WithinInvalid:            "within is invalid",
+	MustBeNumber:             "%s must be a number",
+	MustBeBoolean:            "%s must be a boolean",
+	MustBeDate:               "%s must be in the format YYYY-MM-DD",
+	MustBeDateTime:           "%s must be a RFC 3339 date time",
+	MustBeDuration:           "%s must be a duration like 30d",
+	MustBeCursor:             "%s must be a valid cursor",
+	MustBeType:               "%s must be of type %s",
+	BodyInvalid:              "invalid request body: %s",
+	FieldUnknown:             "%s is not a known field",
+	ExternalSourceInvalid:    "external source is invalid",
+	ExternalIdInvalid:        "external id is invalid",
+	ExternalRefInvalid:       "%s must be a number or an external id as source:id",
+	PatchInvalid:             "invalid patch: %s",
+	PatchTestFailed:          "patch test operation failed: %s",
+	MediaTypeUnsupported:     "media type %s is not supported, use one of: %s",
+	ImportModeInvalid:        "mode must be one of: %s",
+	ImportTooManyRows:        "file has more than %d rows",
+	ImportEmpty:              "file has no header row",
+	ImportDuplicate:          "%s was already used in line %d",
+	ExportFormatInvalid:      "format must be one of: %s",
+	ExportSortNotAllowed:     "exports are ordered by id and can not be sorted",
+	ImportFileRequired:       "file is required",
+	ImportFileInvalid:        "file could not be read: %s",
+	ImportFileTooLarge:       "file is larger than %d MB",
+	VersionMismatch:          "resource was modified, reload it and try again",
+	RecordDeleted:            "id belongs to a deleted record",
+	RecordNotDeleted:         "record is not deleted",
+	PurgeNotAllowed:          "only admins can purge records",
+	VehiclePolicyInvalid:     "vehicles must be one of: %s",
+	DriverHasVehicles:        "driver has vehicles, unassign them or delete it with vehicles=unassign or vehicles=cascade",
+	StatusInvalid:            "status must be one of: %s",
+	StatusReasonRequired:     "reason is required",
+	StatusReasonTooLong:      "reason must have at most %d characters",
+	StatusTransitionInvalid:  "status can not change from %s to %s",
+	DriverNotActive:          "driver is %s and can not be assigned vehicles",
+	DriverStatusHasVehicles:  "driver has vehicles, unassign or transfer them before changing the status",
+	VehicleNotAvailable:      "vehicle is %s and can not be assigned",
+	VehicleInUse:             "vehicle is in use, unassign it before changing the status",
+	ServiceUnavailable:       "service is unavailable, try again later",
+	IdempotencyKeyInvalid:    "idempotency key is invalid",
+	IdempotencyKeyReused:     "idempotency key was already used with a different request",
+	IdempotencyKeyInProgress: "a request with this idempotency key is still being processed",
+}
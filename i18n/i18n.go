@@ -0,0 +1,62 @@
+// Package i18n holds the catalogue of the messages returned by the API,
+// keyed by message code, in each supported language.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported languages, as BCP 47 tags.
+const (
+	En   = "en"
+	PtBR = "pt-BR"
+)
+
+// Default is the language used when the client accepts none of the supported ones.
+const Default = En
+
+var catalogue = map[string]map[string]string{
+	En:   en,
+	PtBR: ptBR,
+}
+
+// Languages returns the supported languages.
+func Languages() []string {
+	return []string{En, PtBR}
+}
+
+// Message returns the message of key in lang formatted with args, falling
+// back to the default language and then to the key itself.
+func Message(lang, key string, args ...interface{}) string {
+	format, ok := catalogue[lang][key]
+	if !ok {
+		format, ok = catalogue[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Has reports whether key has a translation in lang.
+func Has(lang, key string) bool {
+	_, ok := catalogue[lang][key]
+	return ok
+}
+
+// Match returns the supported language for a language tag, matching
+// "pt", "pt-BR" or "pt-PT" to pt-BR and any English variant to en.
+func Match(tag string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	switch base {
+	case "pt":
+		return PtBR, true
+	case "en":
+		return En, true
+	}
+	return "", false
+}
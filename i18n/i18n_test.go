@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// messageCodes reads the message codes declared in keys.go.
+func messageCodes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "keys.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for _, value := range spec.Values {
+			if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				code, _ := strconv.Unquote(lit.Value)
+				codes = append(codes, code)
+			}
+		}
+		return true
+	})
+	return codes
+}
+
+func TestCatalogue_Complete(t *testing.T) {
+	codes := messageCodes(t)
+	assert.NotEmpty(t, codes)
+
+	for _, lang := range Languages() {
+		t.Run(lang, func(t *testing.T) {
+			for _, code := range codes {
+				assert.Truef(t, Has(lang, code), "message %s has no translation in %s", code, lang)
+			}
+			assert.Len(t, catalogue[lang], len(codes), "catalogue has messages without a code")
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		key  string
+		args []interface{}
+		want string
+	}{
+		{
+			name: "Should return the message in english",
+			lang: En,
+			key:  DriverNotFound,
+			want: "driver not found",
+		},
+		{
+			name: "Should return the message in portuguese",
+			lang: PtBR,
+			key:  DriverNotFound,
+			want: "motorista não encontrado",
+		},
+		{
+			name: "Should format the message with the arguments",
+			lang: PtBR,
+			key:  MustBeNumber,
+			args: []interface{}{"page"},
+			want: "page deve ser um número",
+		},
+		{
+			name: "Should fall back to the default language",
+			lang: "fr",
+			key:  DriverNotFound,
+			want: "driver not found",
+		},
+		{
+			name: "Should return the key when there is no message",
+			lang: En,
+			key:  "unknown",
+			want: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Message(tt.lang, tt.key, tt.args...))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   string
+		wantOk bool
+	}{
+		{tag: "pt-BR", want: PtBR, wantOk: true},
+		{tag: "pt", want: PtBR, wantOk: true},
+		{tag: "en-US", want: En, wantOk: true},
+		{tag: "fr-FR", want: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := Match(tt.tag)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package i18n
+
+// Message codes. Every code must have a translation in every language of
+// the catalogue.
+const (
+	DriverNameInvalid               = "driver.name.invalid"
+	DriverLastNameInvalid           = "driver.lastName.invalid"
+	DriverEmailInvalid              = "driver.email.invalid"
+	DriverPhoneInvalid              = "driver.phone.invalid"
+	DriverCPFRequired               = "driver.cpf.required"
+	DriverCPFInvalidLength          = "driver.cpf.invalidLength"
+	DriverCPFInvalidCheckDigits     = "driver.cpf.invalidCheckDigits"
+	DriverLicenseRequired           = "driver.license.required"
+	DriverLicenseInvalidLength      = "driver.license.invalidLength"
+	DriverLicenseInvalidCheckDigits = "driver.license.invalidCheckDigits"
+	DriverLicenseTypeInvalid        = "driver.licenseType.invalid"
+	DriverLicenseIssuedAtInvalid    = "driver.licenseIssuedAt.invalid"
+	DriverLicenseExpiresAtInvalid   = "driver.licenseExpiresAt.invalid"
+	DriverFirstLicenseAtInvalid     = "driver.firstLicenseAt.invalid"
+	DriverIdInvalid                 = "driver.id.invalid"
+	DriverRequired                  = "driver.required"
+	DriverNotFound                  = "driver.notFound"
+	DriverLicenseExpired            = "driver.licenseExpired"
+	DriverLicenseIncompatible       = "driver.licenseIncompatible"
+	DriverEmailConflict             = "driver.email.conflict"
+	DriverCPFConflict               = "driver.cpf.conflict"
+	DriverLicenseConflict           = "driver.license.conflict"
+	DriverExternalIdConflict        = "driver.externalId.conflict"
+
+	VehicleBrandInvalid            = "vehicle.brand.invalid"
+	VehicleModelInvalid            = "vehicle.vehicleModel.invalid"
+	VehicleYearInvalid             = "vehicle.year.invalid"
+	VehicleClassInvalid            = "vehicle.class.invalid"
+	VehicleClassNone               = "vehicle.class.none"
+	VehiclePlateInvalid            = "vehicle.plate.invalid"
+	VehiclePlateConflict           = "vehicle.plate.conflict"
+	VehicleExternalIdConflict      = "vehicle.externalId.conflict"
+	VehicleIdInvalid               = "vehicle.id.invalid"
+	VehicleRequired                = "vehicle.required"
+	VehicleNotFound                = "vehicle.notFound"
+	VehicleNotOwned                = "vehicle.notOwned"
+	VehicleAlreadyAssigned         = "vehicle.alreadyAssigned"
+	VehicleAlreadyAssignedToDriver = "vehicle.alreadyAssignedToDriver"
+	VehicleNotAssigned             = "vehicle.notAssigned"
+	VehicleDriverChanged           = "vehicle.driverChanged"
+	AssignmentNotFound             = "assignment.notFound"
+
+	PageInvalid              = "query.page.invalid"
+	PageSizeOutOfRange       = "query.pageSize.outOfRange"
+	SortNotAllowed           = "query.sort.notAllowed"
+	SortUnknownField         = "query.sort.unknownField"
+	FilterUnknownField       = "query.filter.unknownField"
+	WithinInvalid            = "query.within.invalid"
+	MustBeNumber             = "param.mustBeNumber"
+	MustBeBoolean            = "param.mustBeBoolean"
+	MustBeDate               = "param.mustBeDate"
+	MustBeDateTime           = "param.mustBeDateTime"
+	MustBeDuration           = "param.mustBeDuration"
+	MustBeCursor             = "param.mustBeCursor"
+	MustBeType               = "body.mustBeType"
+	BodyInvalid              = "body.invalid"
+	FieldUnknown             = "body.fieldUnknown"
+	ExternalSourceInvalid    = "external.source.invalid"
+	ExternalIdInvalid        = "external.id.invalid"
+	ExternalRefInvalid       = "external.ref.invalid"
+	PatchInvalid             = "patch.invalid"
+	PatchTestFailed          = "patch.testFailed"
+	MediaTypeUnsupported     = "mediaType.unsupported"
+	ImportModeInvalid        = "import.mode.invalid"
+	ImportTooManyRows        = "import.tooManyRows"
+	ImportEmpty              = "import.empty"
+	ImportDuplicate          = "import.duplicate"
+	ExportFormatInvalid      = "export.format.invalid"
+	ExportSortNotAllowed     = "export.sort.notAllowed"
+	ImportFileRequired       = "import.file.required"
+	ImportFileInvalid        = "import.file.invalid"
+	ImportFileTooLarge       = "import.file.tooLarge"
+	VersionMismatch          = "version.mismatch"
+	RecordDeleted            = "record.deleted"
+	RecordNotDeleted         = "record.notDeleted"
+	PurgeNotAllowed          = "purge.notAllowed"
+	VehiclePolicyInvalid     = "vehicle.policy.invalid"
+	DriverHasVehicles        = "driver.hasVehicles"
+	StatusInvalid            = "status.invalid"
+	StatusReasonRequired     = "status.reason.required"
+	StatusReasonTooLong      = "status.reason.tooLong"
+	StatusTransitionInvalid  = "status.transition.invalid"
+	DriverNotActive          = "driver.notActive"
+	DriverStatusHasVehicles  = "driver.status.hasVehicles"
+	VehicleNotAvailable      = "vehicle.notAvailable"
+	VehicleInUse             = "vehicle.inUse"
+	ServiceUnavailable       = "service.unavailable"
+	IdempotencyKeyInvalid    = "idempotencyKey.invalid"
+	IdempotencyKeyReused     = "idempotencyKey.reused"
+	IdempotencyKeyInProgress = "idempotencyKey.inProgress"
+)
@@ -0,0 +1,94 @@
+package i18n
+
+var ptBR = map[string]string{
+	DriverNameInvalid:               "nome do motorista é inválido",
+	DriverLastNameInvalid:           "sobrenome do motorista é inválido",
+	DriverEmailInvalid:              "e-mail do motorista é inválido",
+	DriverPhoneInvalid:              "telefone do motorista é inválido",
+	DriverCPFRequired:               "CPF do motorista é obrigatório",
+	DriverCPFInvalidLength:          "CPF do motorista deve ter 11 dígitos",
+	DriverCPFInvalidCheckDigits:     "dígitos verificadores do CPF do motorista são inválidos",
+	DriverLicenseRequired:           "CNH do motorista é obrigatória",
+	DriverLicenseInvalidLength:      "CNH do motorista deve ter 11 dígitos",
+	DriverLicenseInvalidCheckDigits: "dígitos verificadores da CNH do motorista são inválidos",
+	DriverLicenseTypeInvalid:        "categoria da CNH do motorista é inválida",
+	DriverLicenseIssuedAtInvalid:    "data de emissão da CNH do motorista é inválida",
+	DriverLicenseExpiresAtInvalid:   "data de validade da CNH do motorista deve ser posterior à data de emissão",
+	DriverFirstLicenseAtInvalid:     "data da primeira habilitação do motorista não pode ser posterior à data de emissão",
+	DriverIdInvalid:                 "id do motorista é inválido",
+	DriverRequired:                  "motorista é inválido",
+	DriverNotFound:                  "motorista não encontrado",
+	DriverLicenseExpired:            "CNH do motorista está vencida",
+	DriverLicenseIncompatible:       "categoria de CNH %s não permite dirigir o veículo %s da classe %s, classes permitidas: %s",
+	DriverEmailConflict:             "e-mail do motorista já cadastrado",
+	DriverCPFConflict:               "CPF do motorista já cadastrado",
+	DriverLicenseConflict:           "CNH do motorista já cadastrada",
+	DriverExternalIdConflict:        "id externo do motorista já cadastrado",
+
+	VehicleBrandInvalid:            "marca do veículo é inválida",
+	VehicleModelInvalid:            "modelo do veículo é inválido",
+	VehicleYearInvalid:             "ano do veículo é inválido",
+	VehicleClassInvalid:            "classe do veículo é inválida",
+	VehicleClassNone:               "nenhuma",
+	VehiclePlateInvalid:            "placa do veículo é inválida",
+	VehiclePlateConflict:           "placa do veículo já cadastrada",
+	VehicleExternalIdConflict:      "id externo do veículo já cadastrado",
+	VehicleIdInvalid:               "id do veículo é inválido",
+	VehicleRequired:                "veículo é inválido",
+	VehicleNotFound:                "veículo não encontrado",
+	VehicleNotOwned:                "veículo não está vinculado ao motorista",
+	VehicleAlreadyAssigned:         "veículo já está vinculado a um motorista",
+	VehicleAlreadyAssignedToDriver: "veículo já está vinculado a este motorista",
+	VehicleNotAssigned:             "veículo não está vinculado a um motorista",
+	VehicleDriverChanged:           "o motorista do veículo mudou, recarregue o veículo e tente novamente",
+	AssignmentNotFound:             "nenhum motorista estava vinculado ao veículo no momento informado",
+
+	PageInvalid:              "página é inválida",
+	PageSizeOutOfRange:       "pageSize deve estar entre 1 e %d",
+	SortNotAllowed:           "sort não pode ser combinado com after",
+	SortUnknownField:         "ordenação por %s é inválida",
+	FilterUnknownField:       "filtro %s é inválido",
+	WithinInvalid:            "within é inválido",
+	MustBeNumber:             "%s deve ser um número",
+	MustBeBoolean:            "%s deve ser true ou false",
+	MustBeDate:               "%s deve estar no formato AAAA-MM-DD",
+	MustBeDateTime:           "%s deve ser uma data e hora RFC 3339",
+	MustBeDuration:           "%s deve ser uma duração como 30d",
+	MustBeCursor:             "%s deve ser um cursor válido",
+	MustBeType:               "%s deve ser do tipo %s",
+	BodyInvalid:              "corpo da requisição inválido: %s",
+	FieldUnknown:             "%s não é um campo conhecido",
+	ExternalSourceInvalid:    "origem externa é inválida",
+	ExternalIdInvalid:        "id externo é inválido",
+	ExternalRefInvalid:       "%s deve ser um número ou um id externo no formato origem:id",
+	PatchInvalid:             "patch inválido: %s",
+	PatchTestFailed:          "operação test do patch falhou: %s",
+	MediaTypeUnsupported:     "tipo de mídia %s não é suportado, use um de: %s",
+	ImportModeInvalid:        "mode deve ser um de: %s",
+	ImportTooManyRows:        "arquivo tem mais de %d linhas",
+	ImportEmpty:              "arquivo não tem linha de cabeçalho",
+	ImportDuplicate:          "%s já usado(a) na linha %d",
+	ExportFormatInvalid:      "format deve ser um de: %s",
+	ExportSortNotAllowed:     "exportações são ordenadas por id e não podem ser ordenadas",
+	ImportFileRequired:       "arquivo é obrigatório",
+	ImportFileInvalid:        "não foi possível ler o arquivo: %s",
+	ImportFileTooLarge:       "arquivo é maior que %d MB",
+	VersionMismatch:          "o registro foi alterado, recarregue e tente novamente",
+	RecordDeleted:            "o id pertence a um registro removido",
+	RecordNotDeleted:         "o registro não está removido",
+	PurgeNotAllowed:          "apenas administradores podem remover registros definitivamente",
+	VehiclePolicyInvalid:     "vehicles deve ser um de: %s",
+	DriverHasVehicles:        "o motorista possui veículos, desvincule-os ou remova-o com vehicles=unassign ou vehicles=cascade",
+	StatusInvalid:            "status deve ser um de: %s",
+	StatusReasonRequired:     "reason é obrigatório",
+	StatusReasonTooLong:      "reason deve ter no máximo %d caracteres",
+	StatusTransitionInvalid:  "o status não pode mudar de %s para %s",
+	DriverNotActive:          "o motorista está com status %s e não pode receber veículos",
+	DriverStatusHasVehicles:  "o motorista possui veículos, desvincule-os ou transfira-os antes de mudar o status",
+	VehicleNotAvailable:      "o veículo está com status %s e não pode ser vinculado",
+	VehicleInUse:             "o veículo está em uso, desvincule-o antes de mudar o status",
+	ServiceUnavailable:       "serviço indisponível, tente novamente mais tarde",
+	IdempotencyKeyInvalid:    "chave de idempotência é inválida",
+	IdempotencyKeyReused:     "chave de idempotência já foi usada em outra requisição",
+	IdempotencyKeyInProgress: "uma requisição com esta chave de idempotência ainda está em processamento",
+}
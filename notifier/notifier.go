@@ -0,0 +1,26 @@
+package notifier
+
+import (
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers alerts about drivers, e.g. by log, email or SMS.
+type Notifier interface {
+	LicenseExpiring(driver *entity.Driver) error
+}
+
+type logNotifier struct {
+	log *zap.SugaredLogger
+}
+
+func NewLogNotifier(log *zap.SugaredLogger) *logNotifier {
+	return &logNotifier{log: log}
+}
+
+func (ln logNotifier) LicenseExpiring(driver *entity.Driver) error {
+	ln.log.Warnw("driver license is expiring",
+		"driverId", driver.ID, "name", driver.Name, "lastName", driver.LastName,
+		"email", driver.Email, "licenseExpiresAt", driver.LicenseExpiresAt)
+	return nil
+}
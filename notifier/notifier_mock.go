@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notifier/notifier.go
+
+// Package notifier is a generated GoMock package.
+package notifier
+
+import (
+	reflect "reflect"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// LicenseExpiring mocks base method.
+func (m *MockNotifier) LicenseExpiring(driver *entity.Driver) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LicenseExpiring", driver)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LicenseExpiring indicates an expected call of LicenseExpiring.
+func (mr *MockNotifierMockRecorder) LicenseExpiring(driver interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LicenseExpiring", reflect.TypeOf((*MockNotifier)(nil).LicenseExpiring), driver)
+}
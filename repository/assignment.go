@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssignmentRepository interface {
+	GetByVehicle(vehicleId uint) ([]*entity.Assignment, error)
+	GetByDriver(driverId uint) ([]*entity.Assignment, error)
+	GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error)
+	Assign(vehicle *entity.Vehicle, driverId uint, actor entity.Actor) error
+	Unassign(vehicle *entity.Vehicle, actor entity.Actor) error
+}
+
+type assignmentRepository struct {
+	log *zap.SugaredLogger
+	db  *gorm.DB
+}
+
+func NewAssignmentRepository(log *zap.SugaredLogger, db *gorm.DB) *assignmentRepository {
+	return &assignmentRepository{log: log, db: db}
+}
+
+func (ar assignmentRepository) GetByVehicle(vehicleId uint) ([]*entity.Assignment, error) {
+	var assignments []*entity.Assignment
+	err := ar.db.Preload("Driver").Where("vehicle_id = ?", vehicleId).Order("started_at desc").Find(&assignments).Error
+	if err != nil {
+		ar.log.Errorw("error getting assignments by vehicle", "vehicleId", vehicleId, "error", err)
+		return nil, err
+	}
+	return assignments, nil
+}
+
+func (ar assignmentRepository) GetByDriver(driverId uint) ([]*entity.Assignment, error) {
+	var assignments []*entity.Assignment
+	err := ar.db.Preload("Vehicle").Where("driver_id = ?", driverId).Order("started_at desc").Find(&assignments).Error
+	if err != nil {
+		ar.log.Errorw("error getting assignments by driver", "driverId", driverId, "error", err)
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// GetByPlateAt finds who was driving the vehicle with the given plate at a
+// moment. Deleted vehicles and drivers are considered, since a fine can
+// arrive after the vehicle or driver left the fleet.
+func (ar assignmentRepository) GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error) {
+	assignment := new(entity.Assignment)
+	err := ar.db.
+		Preload("Driver", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Vehicle", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Joins("JOIN vehicles ON vehicles.id = assignments.vehicle_id").
+		Where("vehicles.plate = ?", entity.NormalizePlate(plate)).
+		Where("assignments.started_at <= ?", at).
+		Where("assignments.ended_at IS NULL OR assignments.ended_at > ?", at).
+		Order("assignments.started_at desc").
+		First(assignment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		ar.log.Errorw("error getting assignment by plate", "plate", plate, "at", at, "error", err)
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Assign assigns the vehicle to the driver. It fails with
+// ErrVehicleDriverChanged when the driver of the vehicle is no longer the one
+// it was read with.
+func (ar assignmentRepository) Assign(vehicle *entity.Vehicle, driverId uint, actor entity.Actor) error {
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		return moveVehicle(tx, vehicle, &driverId, actor)
+	})
+	if err != nil {
+		ar.log.Errorw("error assigning vehicle", "vehicleId", vehicle.ID, "driverId", driverId, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Unassign ends the assignment of the vehicle, with the same check as Assign.
+func (ar assignmentRepository) Unassign(vehicle *entity.Vehicle, actor entity.Actor) error {
+	err := ar.db.Transaction(func(tx *gorm.DB) error {
+		return moveVehicle(tx, vehicle, nil, actor)
+	})
+	if err != nil {
+		ar.log.Errorw("error unassigning vehicle", "vehicleId", vehicle.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Backfill starts the history of vehicles assigned before assignments were
+// recorded, using the vehicle creation date as the start of the assignment,
+// and marks the vehicles assigned before statuses were recorded as in use.
+func (ar assignmentRepository) Backfill() error {
+	err := ar.db.Exec(`INSERT INTO assignments (created_at, updated_at, vehicle_id, driver_id, started_at)
+		SELECT NOW(), NOW(), v.id, v.driver_id, v.created_at FROM vehicles v
+		WHERE v.driver_id IS NOT NULL AND v.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM assignments a WHERE a.vehicle_id = v.id)`).Error
+	if err == nil {
+		err = ar.db.Model(&entity.Vehicle{}).
+			Where("driver_id IS NOT NULL AND status = ?", entity.VehicleStatusAvailable).
+			Update("status", entity.VehicleStatusInUse).Error
+	}
+	if err != nil {
+		ar.log.Errorw("error backfilling assignments", "error", err)
+		return err
+	}
+	return nil
+}
+
+// moveVehicle changes the driver of the vehicle to driverId. The vehicle row
+// is locked and its driver must still be the one the vehicle was read with,
+// so two concurrent changes of the same vehicle can not both succeed.
+func moveVehicle(tx *gorm.DB, vehicle *entity.Vehicle, driverId *uint, actor entity.Actor) error {
+	locked := new(entity.Vehicle)
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(locked, vehicle.ID).Error
+	if err != nil {
+		return err
+	}
+	if !sameDriver(locked.DriverID, vehicle.DriverID) {
+		return ErrVehicleDriverChanged
+	}
+	return assignVehicle(tx, vehicle, driverId, time.Now(), actor)
+}
+
+func sameDriver(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// assignVehicle ends the active assignment of the vehicle and, when driverId
+// is not nil, starts a new one. It must run inside a transaction so the
+// history, the audit log and the vehicle current driver and status never
+// disagree.
+func assignVehicle(tx *gorm.DB, vehicle *entity.Vehicle, driverId *uint, at time.Time, actor entity.Actor) error {
+	before := vehicle.AuditFields()
+	from := vehicle.DriverID
+	err := tx.Model(&entity.Assignment{}).
+		Where("vehicle_id = ? AND ended_at IS NULL", vehicle.ID).
+		Update("ended_at", at).Error
+	if err != nil {
+		return err
+	}
+
+	if driverId != nil {
+		err = tx.Create(&entity.Assignment{VehicleID: vehicle.ID, DriverID: *driverId, StartedAt: at}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// a vehicle is in use while assigned and available again once unassigned
+	status, reason := vehicle.Status, entity.StatusReasonUnassigned
+	if driverId != nil {
+		status, reason = entity.VehicleStatusInUse, entity.StatusReasonAssigned
+	} else if status == entity.VehicleStatusInUse {
+		status = entity.VehicleStatusAvailable
+	}
+	err = recordStatusChange(tx, entity.NewStatusChange(actor, entity.AuditEntityVehicle, vehicle.ID, vehicle.Status, status, reason))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(vehicle).Updates(map[string]interface{}{
+		"driver_id": driverId,
+		"status":    status,
+		"version":   gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	vehicle.DriverID = driverId
+	vehicle.Status = status
+	vehicle.Version++
+
+	operation := entity.AuditOperationTransfer
+	switch {
+	case from == nil:
+		operation = entity.AuditOperationAssign
+	case driverId == nil:
+		operation = entity.AuditOperationUnassign
+	}
+	entries := []*entity.AuditEntry{
+		auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, operation, before, vehicle.AuditFields()),
+	}
+	if from != nil {
+		entries = append(entries, auditVehicleOf(actor, *from, operation, &vehicle.ID, nil))
+	}
+	if driverId != nil {
+		entries = append(entries, auditVehicleOf(actor, *driverId, operation, nil, &vehicle.ID))
+	}
+	return recordAudit(tx, entries...)
+}
+
+// auditVehicleOf returns the entry of a vehicle leaving or joining the
+// vehicles of a driver, recorded in the history of the driver.
+func auditVehicleOf(actor entity.Actor, driverId uint, operation string, before *uint, after *uint) *entity.AuditEntry {
+	change := entity.AuditChange{Field: "vehicleId"}
+	if before != nil {
+		change.Before = *before
+	}
+	if after != nil {
+		change.After = *after
+	}
+	return entity.NewAuditEntry(actor, entity.AuditEntityDriver, driverId, operation, []entity.AuditChange{change})
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/assignment.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAssignmentRepository is a mock of AssignmentRepository interface.
+type MockAssignmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentRepositoryMockRecorder
+}
+
+// MockAssignmentRepositoryMockRecorder is the mock recorder for MockAssignmentRepository.
+type MockAssignmentRepositoryMockRecorder struct {
+	mock *MockAssignmentRepository
+}
+
+// NewMockAssignmentRepository creates a new mock instance.
+func NewMockAssignmentRepository(ctrl *gomock.Controller) *MockAssignmentRepository {
+	mock := &MockAssignmentRepository{ctrl: ctrl}
+	mock.recorder = &MockAssignmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentRepository) EXPECT() *MockAssignmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockAssignmentRepository) Assign(vehicle *entity.Vehicle, driverId uint, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", vehicle, driverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockAssignmentRepositoryMockRecorder) Assign(vehicle, driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockAssignmentRepository)(nil).Assign), vehicle, driverId, actor)
+}
+
+// GetByDriver mocks base method.
+func (m *MockAssignmentRepository) GetByDriver(driverId uint) ([]*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDriver", driverId)
+	ret0, _ := ret[0].([]*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDriver indicates an expected call of GetByDriver.
+func (mr *MockAssignmentRepositoryMockRecorder) GetByDriver(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDriver", reflect.TypeOf((*MockAssignmentRepository)(nil).GetByDriver), driverId)
+}
+
+// GetByPlateAt mocks base method.
+func (m *MockAssignmentRepository) GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPlateAt", plate, at)
+	ret0, _ := ret[0].(*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPlateAt indicates an expected call of GetByPlateAt.
+func (mr *MockAssignmentRepositoryMockRecorder) GetByPlateAt(plate, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPlateAt", reflect.TypeOf((*MockAssignmentRepository)(nil).GetByPlateAt), plate, at)
+}
+
+// GetByVehicle mocks base method.
+func (m *MockAssignmentRepository) GetByVehicle(vehicleId uint) ([]*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByVehicle", vehicleId)
+	ret0, _ := ret[0].([]*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByVehicle indicates an expected call of GetByVehicle.
+func (mr *MockAssignmentRepositoryMockRecorder) GetByVehicle(vehicleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByVehicle", reflect.TypeOf((*MockAssignmentRepository)(nil).GetByVehicle), vehicleId)
+}
+
+// Unassign mocks base method.
+func (m *MockAssignmentRepository) Unassign(vehicle *entity.Vehicle, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", vehicle, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockAssignmentRepositoryMockRecorder) Unassign(vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockAssignmentRepository)(nil).Unassign), vehicle, actor)
+}
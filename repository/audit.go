@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuditRepository interface {
+	GetByEntity(entityType string, entityId uint) ([]*entity.AuditEntry, error)
+}
+
+type auditRepository struct {
+	log *zap.SugaredLogger
+	db  *gorm.DB
+}
+
+func NewAuditRepository(log *zap.SugaredLogger, db *gorm.DB) *auditRepository {
+	return &auditRepository{log: log, db: db}
+}
+
+// GetByEntity returns the changes of a driver or vehicle, the latest first.
+func (ar auditRepository) GetByEntity(entityType string, entityId uint) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	err := ar.db.Where("entity_type = ? AND entity_id = ?", entityType, entityId).
+		Order("created_at desc, id desc").
+		Find(&entries).Error
+	if err != nil {
+		ar.log.Errorw("error getting audit entries", "entityType", entityType, "entityId", entityId, "error", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// recordAudit stores the entries of a change. It must run in the transaction
+// of the change, so a change is never stored without its entries. Entries
+// without changes, e.g. of an update that kept every field, are skipped.
+func recordAudit(tx *gorm.DB, entries ...*entity.AuditEntry) error {
+	var changed []*entity.AuditEntry
+	for _, entry := range entries {
+		if len(entry.Changes) > 0 {
+			changed = append(changed, entry)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(changed, createBatchSize).Error
+}
+
+// auditDiff returns the entry of a change from before to after.
+func auditDiff(actor entity.Actor, entityType string, id uint, operation string, before map[string]interface{}, after map[string]interface{}) *entity.AuditEntry {
+	return entity.NewAuditEntry(actor, entityType, id, operation, entity.DiffAudit(before, after))
+}
+
+// findLocked reads the record with id into value, locking it until the end
+// of the transaction. It returns false when there is no such record.
+func findLocked(tx *gorm.DB, value interface{}, id uint) (bool, error) {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(value, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
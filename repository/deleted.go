@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// findDeleted reads the soft deleted record with id into value. It returns
+// false when there is no such record or it is not deleted.
+func findDeleted(db *gorm.DB, value interface{}, id int) (bool, error) {
+	err := db.Unscoped().Where("deleted_at IS NOT NULL").First(value, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// lockDeleted reads the soft deleted record with id into value, locking it
+// until the end of the transaction. It returns ErrRecordNotDeleted when the
+// record was restored, or never deleted, in the meantime.
+func lockDeleted(tx *gorm.DB, value interface{}, id uint) error {
+	err := tx.Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NOT NULL").
+		First(value, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRecordNotDeleted
+	}
+	return err
+}
+
+// restoreDeleted clears the deletion of the locked record value, setting
+// columns and incrementing its version, and reads value back.
+func restoreDeleted(tx *gorm.DB, value interface{}, id uint, columns map[string]interface{}) error {
+	updates := map[string]interface{}{
+		"deleted_at": nil,
+		"version":    gorm.Expr("version + 1"),
+	}
+	for column, v := range columns {
+		updates[column] = v
+	}
+	err := tx.Unscoped().Model(value).Where("id = ?", id).Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	return tx.First(value, id).Error
+}
@@ -2,19 +2,34 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DriverRepository interface {
-	GetAll() ([]*entity.Driver, error)
+	GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error)
+	Export(opts *entity.QueryOptions, batch func([]*entity.Driver) error) error
 	GetById(driverId int, includeVehicle bool) (*entity.Driver, error)
-	Create(driver *entity.Driver) error
-	AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle) error
-	Update(driver *entity.Driver) error
-	Delete(driverId int) error
+	GetDeletedById(driverId int) (*entity.Driver, error)
+	GetByEmail(email string) (*entity.Driver, error)
+	GetByCPF(cpf string) (*entity.Driver, error)
+	GetByLicense(license string) (*entity.Driver, error)
+	GetByExternalId(source string, externalId string) (*entity.Driver, error)
+	GetByLicenseExpiration(until time.Time) ([]*entity.Driver, error)
+	GetLicensesToNotify(until time.Time, batch func([]*entity.Driver) error) error
+	MarkLicenseNotified(driver *entity.Driver) error
+	Create(driver *entity.Driver, actor entity.Actor) error
+	CreateBatch(drivers []*entity.Driver, actor entity.Actor) error
+	AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle, actor entity.Actor) error
+	Update(driver *entity.Driver, actor entity.Actor) error
+	Replace(driver *entity.Driver, actor entity.Actor) (bool, error)
+	Delete(driverId int, version uint, policy string, actor entity.Actor) error
+	Restore(driver *entity.Driver, actor entity.Actor) error
+	Purge(driverId int, actor entity.Actor) error
 }
 
 type driverRepository struct {
@@ -26,13 +41,41 @@ func NewDriverRepository(log *zap.SugaredLogger, db *gorm.DB) *driverRepository
 	return &driverRepository{log: log, db: db}
 }
 
-func (dr driverRepository) GetAll() ([]*entity.Driver, error) {
+func (dr driverRepository) GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error) {
 	var drivers []*entity.Driver
-	err := dr.db.Find(&drivers).Error
+	var total int64
+
+	query := applyFilters(dr.db.Model(&entity.Driver{}), opts, entity.DriverQueryFields)
+	err := query.Count(&total).Error
 	if err != nil {
-		return nil, err
+		dr.log.Errorw("error counting drivers", "opts", opts, "error", err)
+		return nil, 0, err
 	}
-	return drivers, nil
+
+	err = applyPagination(query, opts, entity.DriverQueryFields).Find(&drivers).Error
+	if err != nil {
+		dr.log.Errorw("error getting drivers", "opts", opts, "error", err)
+		return nil, 0, err
+	}
+	return drivers, total, nil
+}
+
+// Export calls batch with the drivers matching the filters of opts, ordered
+// by id, a batch at a time so they are never all loaded into memory.
+func (dr driverRepository) Export(opts *entity.QueryOptions, batch func([]*entity.Driver) error) error {
+	query := applyFilters(dr.db.Model(&entity.Driver{}), opts, entity.DriverQueryFields)
+	if opts.After > 0 {
+		query = query.Where("id > ?", opts.After)
+	}
+	var drivers []*entity.Driver
+	err := query.FindInBatches(&drivers, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		return batch(drivers)
+	}).Error
+	if err != nil {
+		dr.log.Errorw("error exporting drivers", "opts", opts, "error", err)
+		return err
+	}
+	return nil
 }
 
 func (dr driverRepository) GetById(driverId int, includeVehicle bool) (*entity.Driver, error) {
@@ -52,12 +95,150 @@ func (dr driverRepository) GetById(driverId int, includeVehicle bool) (*entity.D
 	return driver, nil
 }
 
-func (dr driverRepository) Create(driver *entity.Driver) error {
-	return dr.db.Create(driver).Error
+// GetDeletedById returns the driver with id only when it is soft deleted.
+func (dr driverRepository) GetDeletedById(driverId int) (*entity.Driver, error) {
+	driver := new(entity.Driver)
+	found, err := findDeleted(dr.db, driver, driverId)
+	if err != nil {
+		dr.log.Errorw("error getting deleted driver by id", "driverId", driverId, "error", err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return driver, nil
+}
+
+func (dr driverRepository) GetByEmail(email string) (*entity.Driver, error) {
+	return dr.getBy("email", email)
+}
+
+func (dr driverRepository) GetByCPF(cpf string) (*entity.Driver, error) {
+	return dr.getBy("cpf", cpf)
+}
+
+func (dr driverRepository) GetByLicense(license string) (*entity.Driver, error) {
+	return dr.getBy("license", license)
+}
+
+func (dr driverRepository) GetByExternalId(source string, externalId string) (*entity.Driver, error) {
+	driver := new(entity.Driver)
+	err := dr.db.Where("external_source = ? AND external_id = ?", source, externalId).First(driver).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		dr.log.Errorw("error getting driver by external id", "source", source, "externalId", externalId, "error", err)
+		return nil, err
+	}
+	return driver, nil
+}
+
+func (dr driverRepository) GetByLicenseExpiration(until time.Time) ([]*entity.Driver, error) {
+	var drivers []*entity.Driver
+	err := dr.db.Where("license_expires_at <= ?", until).Order("license_expires_at").Find(&drivers).Error
+	if err != nil {
+		dr.log.Errorw("error getting drivers by license expiration", "until", until, "error", err)
+		return nil, err
+	}
+	return drivers, nil
 }
 
-func (dr driverRepository) AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle) error {
-	err := dr.db.Model(driver).Association("Vehicles").Append(vehicle)
+// GetLicensesToNotify calls batch with the drivers whose license expires
+// until the given time and was not notified yet, a batch at a time. The
+// drivers terminated are left out, as they no longer drive.
+func (dr driverRepository) GetLicensesToNotify(until time.Time, batch func([]*entity.Driver) error) error {
+	var drivers []*entity.Driver
+	err := dr.db.Where("license_expires_at <= ? AND status <> ?", until, entity.DriverStatusTerminated).
+		Where("license_notified_expiry IS NULL OR license_notified_expiry <> license_expires_at").
+		FindInBatches(&drivers, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			return batch(drivers)
+		}).Error
+	if err != nil {
+		dr.log.Errorw("error getting licenses to notify", "until", until, "error", err)
+		return err
+	}
+	return nil
+}
+
+// MarkLicenseNotified records that the current license of the driver was
+// notified. It is bookkeeping of the scanner, so neither the version nor the
+// audit log of the driver change.
+func (dr driverRepository) MarkLicenseNotified(driver *entity.Driver) error {
+	err := dr.db.Model(driver).UpdateColumn("license_notified_expiry", driver.LicenseExpiresAt).Error
+	if err != nil {
+		dr.log.Errorw("error marking license as notified", "driverId", driver.ID, "error", err)
+		return err
+	}
+	driver.LicenseNotifiedExpiry = driver.LicenseExpiresAt
+	return nil
+}
+
+func (dr driverRepository) getBy(column string, value string) (*entity.Driver, error) {
+	driver := new(entity.Driver)
+	err := dr.db.Where(map[string]interface{}{column: value}).First(driver).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		dr.log.Errorw("error getting driver", column, value, "error", err)
+		return nil, err
+	}
+	return driver, nil
+}
+
+func (dr driverRepository) Create(driver *entity.Driver, actor entity.Actor) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(driver).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationCreate, nil, driver.AuditFields()))
+	})
+}
+
+// CreateBatch creates the drivers in a single transaction, so either all of
+// them or none are created.
+func (dr driverRepository) CreateBatch(drivers []*entity.Driver, actor entity.Actor) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.CreateInBatches(drivers, createBatchSize).Error
+		if err != nil {
+			return err
+		}
+		entries := make([]*entity.AuditEntry, len(drivers))
+		for i, driver := range drivers {
+			entries[i] = auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationCreate, nil, driver.AuditFields())
+		}
+		return recordAudit(tx, entries...)
+	})
+	if err != nil {
+		dr.log.Errorw("error creating drivers", "count", len(drivers), "error", err)
+		return err
+	}
+	return nil
+}
+
+// AddVehicle creates the vehicle assigned to the driver, so it is in use.
+func (dr driverRepository) AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle, actor entity.Actor) error {
+	vehicle.Status = entity.VehicleStatusInUse
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(driver).Association("Vehicles").Append(vehicle)
+		if err != nil {
+			return err
+		}
+		err = tx.Create(&entity.Assignment{
+			VehicleID: vehicle.ID,
+			DriverID:  driver.ID,
+			StartedAt: time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx,
+			auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationCreate, nil, vehicle.AuditFields()),
+			auditVehicleOf(actor, driver.ID, entity.AuditOperationAssign, nil, &vehicle.ID),
+		)
+	})
 	if err != nil {
 		dr.log.Errorw("error adding vehicle to driver",
 			"driverId", driver.ID, "vehicle", vehicle, "error", err)
@@ -66,19 +247,161 @@ func (dr driverRepository) AddVehicle(driver *entity.Driver, vehicle *entity.Veh
 	return nil
 }
 
-func (dr driverRepository) Update(driver *entity.Driver) error {
-	err := dr.db.Save(driver).Error
+func (dr driverRepository) Update(driver *entity.Driver, actor entity.Actor) error {
+	version := driver.Version
+	driver.Version++
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Driver)
+		found, err := findLocked(tx, before, driver.ID)
+		if err != nil {
+			return err
+		}
+		err = saveVersioned(tx, driver, version)
+		if err != nil || !found {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationUpdate, before.AuditFields(), driver.AuditFields()))
+	})
 	if err != nil {
+		driver.Version = version
 		dr.log.Errorw("error updating driver", "driver", driver, "error", err)
 		return err
 	}
 	return nil
 }
 
-func (dr driverRepository) Delete(driverId int) error {
-	err := dr.db.Delete(&entity.Driver{}, driverId).Error
+// Replace creates the driver when it has no ID, or updates every field of the
+// driver with its ID otherwise. It returns whether the driver was created.
+func (dr driverRepository) Replace(driver *entity.Driver, actor entity.Actor) (bool, error) {
+	version := driver.Version
+	driver.Version++
+	created := false
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Driver)
+		found, err := findLocked(tx, before, driver.ID)
+		if err != nil {
+			return err
+		}
+		created, err = replaceVersioned(tx, driver, driver.ID, version)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationCreate, nil, driver.AuditFields()))
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationUpdate, before.AuditFields(), driver.AuditFields()))
+	})
+	if err != nil {
+		driver.Version = version
+		dr.log.Errorw("error replacing driver", "driver", driver, "error", err)
+		return false, err
+	}
+	return created, nil
+}
+
+// Delete deletes the driver, applying policy to its vehicles in the same
+// transaction: with VehiclePolicyRestrict it fails while the driver has
+// vehicles, with VehiclePolicyUnassign they are kept without a driver and
+// with VehiclePolicyCascade they are deleted too.
+func (dr driverRepository) Delete(driverId int, version uint, policy string, actor entity.Actor) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Driver)
+		found, err := findLocked(tx, before, uint(driverId))
+		if err != nil {
+			return err
+		}
+		err = deleteVersioned(tx, &entity.Driver{}, driverId, version)
+		if err != nil || !found {
+			return err
+		}
+
+		var vehicles []*entity.Vehicle
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("driver_id = ?", driverId).Order("id").Find(&vehicles).Error
+		if err != nil {
+			return err
+		}
+		if len(vehicles) > 0 && policy != entity.VehiclePolicyUnassign && policy != entity.VehiclePolicyCascade {
+			return ErrDriverHasVehicles
+		}
+		now := time.Now()
+		for _, vehicle := range vehicles {
+			if policy == entity.VehiclePolicyCascade {
+				err = deleteVehicle(tx, vehicle, 0, actor)
+			} else {
+				err = assignVehicle(tx, vehicle, nil, now, actor)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, before.ID, entity.AuditOperationDelete, before.AuditFields(), nil))
+	})
+	if err != nil {
+		dr.log.Errorw("error deleting driver", "driverId", driverId, "policy", policy, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Restore undeletes the driver.
+func (dr driverRepository) Restore(driver *entity.Driver, actor entity.Actor) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		err := lockDeleted(tx, driver, driver.ID)
+		if err != nil {
+			return err
+		}
+		err = restoreDeleted(tx, driver, driver.ID, nil)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationRestore, nil, driver.AuditFields()))
+	})
+	if err != nil {
+		dr.log.Errorw("error restoring driver", "driverId", driver.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Purge removes the soft deleted driver for good, along with its assignment
+// history. Its vehicles are kept without a driver.
+func (dr driverRepository) Purge(driverId int, actor entity.Actor) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Driver)
+		err := lockDeleted(tx, before, uint(driverId))
+		if err != nil {
+			return err
+		}
+
+		var vehicles []*entity.Vehicle
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("driver_id = ?", driverId).Find(&vehicles).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, vehicle := range vehicles {
+			err = assignVehicle(tx, vehicle, nil, now, actor)
+			if err != nil {
+				return err
+			}
+		}
+		// deleted vehicles still reference the driver they had when deleted
+		err = tx.Unscoped().Model(&entity.Vehicle{}).Where("driver_id = ?", driverId).Update("driver_id", nil).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("driver_id = ?", driverId).Delete(&entity.Assignment{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Delete(&entity.Driver{}, driverId).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, before.ID, entity.AuditOperationPurge, before.AuditFields(), nil))
+	})
 	if err != nil {
-		dr.log.Errorw("error deleting driver", "driverId", driverId, "error", err)
+		dr.log.Errorw("error purging driver", "driverId", driverId, "error", err)
 		return err
 	}
 	return nil
@@ -6,6 +6,7 @@ package repository
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/lucas-moura1/gobrax-challenge/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -35,60 +36,134 @@ func (m *MockDriverRepository) EXPECT() *MockDriverRepositoryMockRecorder {
 }
 
 // AddVehicle mocks base method.
-func (m *MockDriverRepository) AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle) error {
+func (m *MockDriverRepository) AddVehicle(driver *entity.Driver, vehicle *entity.Vehicle, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddVehicle", driver, vehicle)
+	ret := m.ctrl.Call(m, "AddVehicle", driver, vehicle, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddVehicle indicates an expected call of AddVehicle.
-func (mr *MockDriverRepositoryMockRecorder) AddVehicle(driver, vehicle interface{}) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) AddVehicle(driver, vehicle, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVehicle", reflect.TypeOf((*MockDriverRepository)(nil).AddVehicle), driver, vehicle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVehicle", reflect.TypeOf((*MockDriverRepository)(nil).AddVehicle), driver, vehicle, actor)
 }
 
 // Create mocks base method.
-func (m *MockDriverRepository) Create(driver *entity.Driver) error {
+func (m *MockDriverRepository) Create(driver *entity.Driver, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", driver)
+	ret := m.ctrl.Call(m, "Create", driver, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockDriverRepositoryMockRecorder) Create(driver interface{}) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) Create(driver, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDriverRepository)(nil).Create), driver)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDriverRepository)(nil).Create), driver, actor)
+}
+
+// CreateBatch mocks base method.
+func (m *MockDriverRepository) CreateBatch(drivers []*entity.Driver, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", drivers, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockDriverRepositoryMockRecorder) CreateBatch(drivers, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockDriverRepository)(nil).CreateBatch), drivers, actor)
 }
 
 // Delete mocks base method.
-func (m *MockDriverRepository) Delete(driverId int) error {
+func (m *MockDriverRepository) Delete(driverId int, version uint, policy string, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", driverId)
+	ret := m.ctrl.Call(m, "Delete", driverId, version, policy, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockDriverRepositoryMockRecorder) Delete(driverId interface{}) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) Delete(driverId, version, policy, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverRepository)(nil).Delete), driverId, version, policy, actor)
+}
+
+// Export mocks base method.
+func (m *MockDriverRepository) Export(opts *entity.QueryOptions, batch func([]*entity.Driver) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", opts, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockDriverRepositoryMockRecorder) Export(opts, batch interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverRepository)(nil).Delete), driverId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockDriverRepository)(nil).Export), opts, batch)
 }
 
 // GetAll mocks base method.
-func (m *MockDriverRepository) GetAll() ([]*entity.Driver, error) {
+func (m *MockDriverRepository) GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", opts)
 	ret0, _ := ret[0].([]*entity.Driver)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDriverRepositoryMockRecorder) GetAll(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDriverRepository)(nil).GetAll), opts)
+}
+
+// GetByCPF mocks base method.
+func (m *MockDriverRepository) GetByCPF(cpf string) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCPF", cpf)
+	ret0, _ := ret[0].(*entity.Driver)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockDriverRepositoryMockRecorder) GetAll() *gomock.Call {
+// GetByCPF indicates an expected call of GetByCPF.
+func (mr *MockDriverRepositoryMockRecorder) GetByCPF(cpf interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDriverRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCPF", reflect.TypeOf((*MockDriverRepository)(nil).GetByCPF), cpf)
+}
+
+// GetByEmail mocks base method.
+func (m *MockDriverRepository) GetByEmail(email string) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockDriverRepositoryMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockDriverRepository)(nil).GetByEmail), email)
+}
+
+// GetByExternalId mocks base method.
+func (m *MockDriverRepository) GetByExternalId(source, externalId string) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalId", source, externalId)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalId indicates an expected call of GetByExternalId.
+func (mr *MockDriverRepositoryMockRecorder) GetByExternalId(source, externalId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalId", reflect.TypeOf((*MockDriverRepository)(nil).GetByExternalId), source, externalId)
 }
 
 // GetById mocks base method.
@@ -106,16 +181,132 @@ func (mr *MockDriverRepositoryMockRecorder) GetById(driverId, includeVehicle int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDriverRepository)(nil).GetById), driverId, includeVehicle)
 }
 
+// GetByLicense mocks base method.
+func (m *MockDriverRepository) GetByLicense(license string) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLicense", license)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLicense indicates an expected call of GetByLicense.
+func (mr *MockDriverRepositoryMockRecorder) GetByLicense(license interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLicense", reflect.TypeOf((*MockDriverRepository)(nil).GetByLicense), license)
+}
+
+// GetByLicenseExpiration mocks base method.
+func (m *MockDriverRepository) GetByLicenseExpiration(until time.Time) ([]*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLicenseExpiration", until)
+	ret0, _ := ret[0].([]*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLicenseExpiration indicates an expected call of GetByLicenseExpiration.
+func (mr *MockDriverRepositoryMockRecorder) GetByLicenseExpiration(until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLicenseExpiration", reflect.TypeOf((*MockDriverRepository)(nil).GetByLicenseExpiration), until)
+}
+
+// GetDeletedById mocks base method.
+func (m *MockDriverRepository) GetDeletedById(driverId int) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedById", driverId)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedById indicates an expected call of GetDeletedById.
+func (mr *MockDriverRepositoryMockRecorder) GetDeletedById(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedById", reflect.TypeOf((*MockDriverRepository)(nil).GetDeletedById), driverId)
+}
+
+// GetLicensesToNotify mocks base method.
+func (m *MockDriverRepository) GetLicensesToNotify(until time.Time, batch func([]*entity.Driver) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicensesToNotify", until, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetLicensesToNotify indicates an expected call of GetLicensesToNotify.
+func (mr *MockDriverRepositoryMockRecorder) GetLicensesToNotify(until, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicensesToNotify", reflect.TypeOf((*MockDriverRepository)(nil).GetLicensesToNotify), until, batch)
+}
+
+// MarkLicenseNotified mocks base method.
+func (m *MockDriverRepository) MarkLicenseNotified(driver *entity.Driver) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLicenseNotified", driver)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkLicenseNotified indicates an expected call of MarkLicenseNotified.
+func (mr *MockDriverRepositoryMockRecorder) MarkLicenseNotified(driver interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLicenseNotified", reflect.TypeOf((*MockDriverRepository)(nil).MarkLicenseNotified), driver)
+}
+
+// Purge mocks base method.
+func (m *MockDriverRepository) Purge(driverId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", driverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockDriverRepositoryMockRecorder) Purge(driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDriverRepository)(nil).Purge), driverId, actor)
+}
+
+// Replace mocks base method.
+func (m *MockDriverRepository) Replace(driver *entity.Driver, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", driver, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockDriverRepositoryMockRecorder) Replace(driver, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockDriverRepository)(nil).Replace), driver, actor)
+}
+
+// Restore mocks base method.
+func (m *MockDriverRepository) Restore(driver *entity.Driver, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", driver, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockDriverRepositoryMockRecorder) Restore(driver, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockDriverRepository)(nil).Restore), driver, actor)
+}
+
 // Update mocks base method.
-func (m *MockDriverRepository) Update(driver *entity.Driver) error {
+func (m *MockDriverRepository) Update(driver *entity.Driver, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", driver)
+	ret := m.ctrl.Call(m, "Update", driver, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockDriverRepositoryMockRecorder) Update(driver interface{}) *gomock.Call {
+func (mr *MockDriverRepositoryMockRecorder) Update(driver, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDriverRepository)(nil).Update), driver)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDriverRepository)(nil).Update), driver, actor)
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"gorm.io/gorm"
+)
+
+const mysqlDuplicateEntry uint16 = 1062
+
+// ErrVehicleDriverChanged is returned when the driver of a vehicle changed
+// between the validation and the update, e.g. by a concurrent transfer.
+var ErrVehicleDriverChanged = domainerr.New(domainerr.Conflict, i18n.VehicleDriverChanged)
+
+// ErrVersionMismatch is returned when a record was changed by someone else
+// since it was read, so saving it would overwrite the other change.
+var ErrVersionMismatch = domainerr.New(domainerr.Precondition, i18n.VersionMismatch)
+
+// ErrRecordDeleted is returned when a record is replaced with the id of a
+// record that was soft deleted.
+var ErrRecordDeleted = domainerr.New(domainerr.Conflict, i18n.RecordDeleted)
+
+// ErrDriverHasVehicles is returned when deleting a driver that still has
+// vehicles with the restrict policy.
+var ErrDriverHasVehicles = domainerr.New(domainerr.Conflict, i18n.DriverHasVehicles)
+
+// ErrRecordNotDeleted is returned when restoring or purging a record that is
+// not soft deleted.
+var ErrRecordNotDeleted = domainerr.New(domainerr.Conflict, i18n.RecordNotDeleted)
+
+type uniqueIndex struct {
+	entity string
+	field  string
+}
+
+// uniqueIndexes maps the unique indexes created by gorm to the field they protect.
+var uniqueIndexes = map[string]uniqueIndex{
+	"idx_drivers_email":     {entity: "driver", field: "email"},
+	"idx_drivers_cpf":       {entity: "driver", field: "cpf"},
+	"idx_drivers_license":   {entity: "driver", field: "license"},
+	"idx_vehicles_plate":    {entity: "vehicle", field: "plate"},
+	"idx_drivers_external":  {entity: "driver", field: "externalId"},
+	"idx_vehicles_external": {entity: "vehicle", field: "externalId"},
+}
+
+// translateError converts a MySQL duplicate key error into an
+// entity.ErrorConflict and a lost connection into an unavailable error.
+func translateError(err error) error {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) {
+		return domainerr.Wrap(domainerr.Unavailable, i18n.ServiceUnavailable, err)
+	}
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlDuplicateEntry {
+		return err
+	}
+	for name, index := range uniqueIndexes {
+		if strings.Contains(mysqlErr.Message, name+"'") {
+			return &entity.ErrorConflict{Entity: index.entity, Field: index.field}
+		}
+	}
+	return err
+}
+
+// RegisterErrorTranslation makes every statement of db return the errors of
+// translateError, so the repositories only see domain errors.
+func RegisterErrorTranslation(db *gorm.DB) error {
+	translate := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			tx.Error = translateError(tx.Error)
+		}
+	}
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("repository:translate_error", translate); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("repository:translate_error", translate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("repository:translate_error", translate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("repository:translate_error", translate); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("repository:translate_error", translate); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("repository:translate_error", translate)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	GetByKey(key string) (*entity.IdempotencyKey, error)
+	Reserve(key *entity.IdempotencyKey) (bool, error)
+	Complete(key *entity.IdempotencyKey) error
+	Delete(key string) error
+}
+
+type idempotencyRepository struct {
+	log *zap.SugaredLogger
+	db  *gorm.DB
+}
+
+func NewIdempotencyRepository(log *zap.SugaredLogger, db *gorm.DB) *idempotencyRepository {
+	return &idempotencyRepository{log: log, db: db}
+}
+
+func (ir idempotencyRepository) GetByKey(key string) (*entity.IdempotencyKey, error) {
+	idempotencyKey := new(entity.IdempotencyKey)
+	err := ir.db.Where("`key` = ?", key).First(idempotencyKey).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		ir.log.Errorw("error getting idempotency key", "key", key, "error", err)
+		return nil, err
+	}
+	return idempotencyKey, nil
+}
+
+// Reserve inserts the key unless it already exists, reporting whether it
+// was inserted. The primary key makes concurrent reservations of the same
+// key safe, only one of them succeeds.
+func (ir idempotencyRepository) Reserve(key *entity.IdempotencyKey) (bool, error) {
+	result := ir.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		ir.log.Errorw("error reserving idempotency key", "key", key.Key, "error", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (ir idempotencyRepository) Complete(key *entity.IdempotencyKey) error {
+	err := ir.db.Model(&entity.IdempotencyKey{}).Where("`key` = ?", key.Key).Updates(map[string]interface{}{
+		"status_code":  key.StatusCode,
+		"content_type": key.ContentType,
+		"body":         key.Body,
+	}).Error
+	if err != nil {
+		ir.log.Errorw("error completing idempotency key", "key", key.Key, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ir idempotencyRepository) Delete(key string) error {
+	err := ir.db.Where("`key` = ?", key).Delete(&entity.IdempotencyKey{}).Error
+	if err != nil {
+		ir.log.Errorw("error deleting idempotency key", "key", key, "error", err)
+		return err
+	}
+	return nil
+}
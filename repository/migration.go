@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// uniqueColumn is a column with a unique index of its own. Its values are
+// compared through value, the SQL expression of the value once normalised,
+// or the column itself when value is empty.
+type uniqueColumn struct {
+	table  string
+	column string
+	value  string
+}
+
+var uniqueColumns = []uniqueColumn{
+	{table: "drivers", column: "email"},
+	{table: "drivers", column: "cpf"},
+	{table: "drivers", column: "license"},
+	// the plates saved before plates were normalised are only normalised
+	// after the migration, e.g. "ABC-1234" and "ABC1234" become the same plate
+	{table: "vehicles", column: "plate", value: "UPPER(REPLACE(REPLACE(REPLACE(plate, '-', ''), ' ', ''), '.', ''))"},
+}
+
+// duplicate is a value shared by several rows of a unique column.
+type duplicate struct {
+	Value string
+	Ids   string
+}
+
+// Migrate creates and updates the tables of the entities. A unique index can
+// not be created while duplicated values exist and AutoMigrate stops at its
+// first error, so the duplicates are reported first instead of starting
+// without the index and without the migrations after it.
+func Migrate(log *zap.SugaredLogger, db *gorm.DB) error {
+	err := checkDuplicates(log, db)
+	if err != nil {
+		return err
+	}
+	err = db.AutoMigrate(&entity.Driver{}, &entity.Vehicle{}, &entity.Assignment{}, &entity.IdempotencyKey{}, &entity.AuditEntry{}, &entity.StatusChange{})
+	if err != nil {
+		log.Errorw("error migrating the database", "error", err)
+		return err
+	}
+	return nil
+}
+
+// checkDuplicates logs the rows sharing a value of a unique column and
+// returns an error naming the columns with duplicates. Soft deleted rows are
+// included, as the unique indexes include them too.
+func checkDuplicates(log *zap.SugaredLogger, db *gorm.DB) error {
+	var found []string
+	for _, unique := range uniqueColumns {
+		if !db.Migrator().HasColumn(unique.table, unique.column) {
+			continue
+		}
+		value := unique.value
+		if value == "" {
+			value = unique.column
+		}
+		var duplicates []duplicate
+		err := db.Raw(fmt.Sprintf(
+			"SELECT %[1]s AS value, GROUP_CONCAT(id ORDER BY id) AS ids FROM %[2]s WHERE %[3]s IS NOT NULL GROUP BY %[1]s HAVING COUNT(*) > 1",
+			value, unique.table, unique.column)).Scan(&duplicates).Error
+		if err != nil {
+			log.Errorw("error looking up duplicates", "table", unique.table, "column", unique.column, "error", err)
+			return err
+		}
+		for _, dup := range duplicates {
+			log.Errorw("duplicated value of a unique column", "table", unique.table, "column", unique.column, "value", dup.Value, "ids", dup.Ids)
+		}
+		if len(duplicates) > 0 {
+			found = append(found, fmt.Sprintf("%s.%s (%d values)", unique.table, unique.column, len(duplicates)))
+		}
+	}
+	if len(found) > 0 {
+		return fmt.Errorf("duplicated values in %s, merge or remove the rows logged above before starting again", strings.Join(found, ", "))
+	}
+	return nil
+}
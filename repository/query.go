@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exportBatchSize is the number of records read by each query of an export.
+const exportBatchSize = 500
+
+func applyFilters(query *gorm.DB, opts *entity.QueryOptions, fields map[string]string) *gorm.DB {
+	if opts.Deleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	for _, filter := range opts.Filters {
+		column := fields[filter.Field]
+		switch filter.Operator {
+		case entity.FilterOperatorGreaterThan:
+			query = query.Where(fmt.Sprintf("%s >= ?", column), filter.Value)
+		case entity.FilterOperatorLessThan:
+			query = query.Where(fmt.Sprintf("%s <= ?", column), filter.Value)
+		case entity.FilterOperatorNull:
+			if filter.Value == "true" {
+				query = query.Where(fmt.Sprintf("%s IS NULL", column))
+			} else {
+				query = query.Where(fmt.Sprintf("%s IS NOT NULL", column))
+			}
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", column), filter.Value)
+		}
+	}
+	// a new session lets the filtered query be reused for count and find
+	return query.Session(&gorm.Session{})
+}
+
+func applyPagination(query *gorm.DB, opts *entity.QueryOptions, fields map[string]string) *gorm.DB {
+	if opts.After > 0 {
+		query = query.Where("id > ?", opts.After)
+	}
+	for _, sort := range opts.Sort {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: fields[sort.Field]},
+			Desc:   sort.Desc,
+		})
+	}
+	// id keeps the order stable between pages when sorting by non unique columns
+	query = query.Order("id")
+	return query.Offset(opts.Offset()).Limit(opts.PageSize)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type StatusRepository interface {
+	GetByEntity(entityType string, entityId uint) ([]*entity.StatusChange, error)
+	ChangeDriverStatus(driver *entity.Driver, status string, reason string, actor entity.Actor) error
+	ChangeVehicleStatus(vehicle *entity.Vehicle, status string, reason string, actor entity.Actor) error
+}
+
+type statusRepository struct {
+	log *zap.SugaredLogger
+	db  *gorm.DB
+}
+
+func NewStatusRepository(log *zap.SugaredLogger, db *gorm.DB) *statusRepository {
+	return &statusRepository{log: log, db: db}
+}
+
+// GetByEntity returns the status changes of a driver or vehicle, the latest first.
+func (sr statusRepository) GetByEntity(entityType string, entityId uint) ([]*entity.StatusChange, error) {
+	var changes []*entity.StatusChange
+	err := sr.db.Where("entity_type = ? AND entity_id = ?", entityType, entityId).
+		Order("created_at desc, id desc").
+		Find(&changes).Error
+	if err != nil {
+		sr.log.Errorw("error getting status changes", "entityType", entityType, "entityId", entityId, "error", err)
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ChangeDriverStatus moves the driver to status, recording the change. The
+// driver is only changed while its version is still the one read.
+func (sr statusRepository) ChangeDriverStatus(driver *entity.Driver, status string, reason string, actor entity.Actor) error {
+	before := *driver
+	driver.Status = status
+	driver.Version++
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		err := saveVersioned(tx, driver, before.Version)
+		if err != nil {
+			return err
+		}
+		err = recordStatusChange(tx, entity.NewStatusChange(actor, entity.AuditEntityDriver, driver.ID, before.Status, status, reason))
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityDriver, driver.ID, entity.AuditOperationStatus, before.AuditFields(), driver.AuditFields()))
+	})
+	if err != nil {
+		driver.Status = before.Status
+		driver.Version = before.Version
+		sr.log.Errorw("error changing driver status", "driverId", driver.ID, "status", status, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ChangeVehicleStatus moves the vehicle to status, recording the change. The
+// vehicle is only changed while its version is still the one read.
+func (sr statusRepository) ChangeVehicleStatus(vehicle *entity.Vehicle, status string, reason string, actor entity.Actor) error {
+	before := *vehicle
+	vehicle.Status = status
+	vehicle.Version++
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		err := saveVersioned(tx, vehicle, before.Version)
+		if err != nil {
+			return err
+		}
+		err = recordStatusChange(tx, entity.NewStatusChange(actor, entity.AuditEntityVehicle, vehicle.ID, before.Status, status, reason))
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationStatus, before.AuditFields(), vehicle.AuditFields()))
+	})
+	if err != nil {
+		vehicle.Status = before.Status
+		vehicle.Version = before.Version
+		sr.log.Errorw("error changing vehicle status", "vehicleId", vehicle.ID, "status", status, "error", err)
+		return err
+	}
+	return nil
+}
+
+// recordStatusChange stores a status change. It must run in the transaction
+// of the change, so a status never changes without its history.
+func recordStatusChange(tx *gorm.DB, change *entity.StatusChange) error {
+	if change.FromStatus == change.ToStatus {
+		return nil
+	}
+	return tx.Create(change).Error
+}
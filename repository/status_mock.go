@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/status.go
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	reflect "reflect"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatusRepository is a mock of StatusRepository interface.
+type MockStatusRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusRepositoryMockRecorder
+}
+
+// MockStatusRepositoryMockRecorder is the mock recorder for MockStatusRepository.
+type MockStatusRepositoryMockRecorder struct {
+	mock *MockStatusRepository
+}
+
+// NewMockStatusRepository creates a new mock instance.
+func NewMockStatusRepository(ctrl *gomock.Controller) *MockStatusRepository {
+	mock := &MockStatusRepository{ctrl: ctrl}
+	mock.recorder = &MockStatusRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusRepository) EXPECT() *MockStatusRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ChangeDriverStatus mocks base method.
+func (m *MockStatusRepository) ChangeDriverStatus(driver *entity.Driver, status, reason string, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeDriverStatus", driver, status, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeDriverStatus indicates an expected call of ChangeDriverStatus.
+func (mr *MockStatusRepositoryMockRecorder) ChangeDriverStatus(driver, status, reason, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeDriverStatus", reflect.TypeOf((*MockStatusRepository)(nil).ChangeDriverStatus), driver, status, reason, actor)
+}
+
+// ChangeVehicleStatus mocks base method.
+func (m *MockStatusRepository) ChangeVehicleStatus(vehicle *entity.Vehicle, status, reason string, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeVehicleStatus", vehicle, status, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeVehicleStatus indicates an expected call of ChangeVehicleStatus.
+func (mr *MockStatusRepositoryMockRecorder) ChangeVehicleStatus(vehicle, status, reason, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeVehicleStatus", reflect.TypeOf((*MockStatusRepository)(nil).ChangeVehicleStatus), vehicle, status, reason, actor)
+}
+
+// GetByEntity mocks base method.
+func (m *MockStatusRepository) GetByEntity(entityType string, entityId uint) ([]*entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEntity", entityType, entityId)
+	ret0, _ := ret[0].([]*entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEntity indicates an expected call of GetByEntity.
+func (mr *MockStatusRepositoryMockRecorder) GetByEntity(entityType, entityId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEntity", reflect.TypeOf((*MockStatusRepository)(nil).GetByEntity), entityType, entityId)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Repositories are the repositories handed to the operation of a unit of
+// work, bound to its transaction.
+type Repositories struct {
+	Drivers     DriverRepository
+	Vehicles    VehicleRepository
+	Assignments AssignmentRepository
+	Statuses    StatusRepository
+}
+
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committing it when fn returns nil and
+	// rolling it back when fn returns an error or panics. The error of fn is
+	// returned as is.
+	Do(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	log *zap.SugaredLogger
+	db  *gorm.DB
+}
+
+func NewUnitOfWork(log *zap.SugaredLogger, db *gorm.DB) *unitOfWork {
+	return &unitOfWork{log: log, db: db}
+}
+
+// Do runs fn with repositories bound to a new transaction. The transactions
+// the repositories open themselves become savepoints of it, so an operation
+// that reads and writes through several repositories is committed or rolled
+// back as a whole.
+func (uow unitOfWork) Do(fn func(repos Repositories) error) error {
+	return uow.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Drivers:     NewDriverRepository(uow.log, tx),
+			Vehicles:    NewVehicleRepository(uow.log, tx),
+			Assignments: NewAssignmentRepository(uow.log, tx),
+			Statuses:    NewStatusRepository(uow.log, tx),
+		})
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
 	"go.uber.org/zap"
@@ -9,10 +10,20 @@ import (
 )
 
 type VehicleRepository interface {
-	GetAll() ([]*entity.Vehicle, error)
+	GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error)
+	Export(opts *entity.QueryOptions, batch func([]*entity.Vehicle) error) error
 	GetById(vehicleId int) (*entity.Vehicle, error)
-	Update(vehicle *entity.Vehicle) error
-	Delete(vehicleId int) error
+	GetDeletedById(vehicleId int) (*entity.Vehicle, error)
+	GetByPlate(plate string) (*entity.Vehicle, error)
+	GetByExternalId(source string, externalId string) (*entity.Vehicle, error)
+	GetByDriver(driverId uint) ([]*entity.Vehicle, error)
+	Create(vehicle *entity.Vehicle, actor entity.Actor) error
+	CreateBatch(vehicles []*entity.Vehicle, actor entity.Actor) error
+	Update(vehicle *entity.Vehicle, actor entity.Actor) error
+	Replace(vehicle *entity.Vehicle, actor entity.Actor) (bool, error)
+	Delete(vehicleId int, version uint, actor entity.Actor) error
+	Restore(vehicle *entity.Vehicle, actor entity.Actor) error
+	Purge(vehicleId int, actor entity.Actor) error
 }
 
 type vehicleRepository struct {
@@ -24,13 +35,41 @@ func NewVehicleRepository(log *zap.SugaredLogger, db *gorm.DB) *vehicleRepositor
 	return &vehicleRepository{log: log, db: db}
 }
 
-func (vr vehicleRepository) GetAll() ([]*entity.Vehicle, error) {
+func (vr vehicleRepository) GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error) {
 	var vehicles []*entity.Vehicle
-	err := vr.db.Find(&vehicles).Error
+	var total int64
+
+	query := applyFilters(vr.db.Model(&entity.Vehicle{}), opts, entity.VehicleQueryFields)
+	err := query.Count(&total).Error
 	if err != nil {
-		return nil, err
+		vr.log.Errorw("error counting vehicles", "opts", opts, "error", err)
+		return nil, 0, err
 	}
-	return vehicles, nil
+
+	err = applyPagination(query, opts, entity.VehicleQueryFields).Find(&vehicles).Error
+	if err != nil {
+		vr.log.Errorw("error getting vehicles", "opts", opts, "error", err)
+		return nil, 0, err
+	}
+	return vehicles, total, nil
+}
+
+// Export calls batch with the vehicles matching the filters of opts, ordered
+// by id, a batch at a time so they are never all loaded into memory.
+func (vr vehicleRepository) Export(opts *entity.QueryOptions, batch func([]*entity.Vehicle) error) error {
+	query := applyFilters(vr.db.Model(&entity.Vehicle{}), opts, entity.VehicleQueryFields)
+	if opts.After > 0 {
+		query = query.Where("id > ?", opts.After)
+	}
+	var vehicles []*entity.Vehicle
+	err := query.FindInBatches(&vehicles, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		return batch(vehicles)
+	}).Error
+	if err != nil {
+		vr.log.Errorw("error exporting vehicles", "opts", opts, "error", err)
+		return err
+	}
+	return nil
 }
 
 func (vr vehicleRepository) GetById(vehicleId int) (*entity.Vehicle, error) {
@@ -46,20 +85,292 @@ func (vr vehicleRepository) GetById(vehicleId int) (*entity.Vehicle, error) {
 	return vehicle, nil
 }
 
-func (vr vehicleRepository) Update(vehicle *entity.Vehicle) error {
-	err := vr.db.Save(vehicle).Error
+// GetDeletedById returns the vehicle with id only when it is soft deleted.
+func (vr vehicleRepository) GetDeletedById(vehicleId int) (*entity.Vehicle, error) {
+	vehicle := new(entity.Vehicle)
+	found, err := findDeleted(vr.db, vehicle, vehicleId)
+	if err != nil {
+		vr.log.Errorw("error getting deleted vehicle by id", "vehicleId", vehicleId, "error", err)
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return vehicle, nil
+}
+
+func (vr vehicleRepository) GetByPlate(plate string) (*entity.Vehicle, error) {
+	vehicle := new(entity.Vehicle)
+	err := vr.db.Where("plate = ?", entity.NormalizePlate(plate)).First(vehicle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vr.log.Errorw("error getting vehicle by plate", "plate", plate, "error", err)
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+func (vr vehicleRepository) GetByExternalId(source string, externalId string) (*entity.Vehicle, error) {
+	vehicle := new(entity.Vehicle)
+	err := vr.db.Where("external_source = ? AND external_id = ?", source, externalId).First(vehicle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vr.log.Errorw("error getting vehicle by external id", "source", source, "externalId", externalId, "error", err)
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+func (vr vehicleRepository) GetByDriver(driverId uint) ([]*entity.Vehicle, error) {
+	var vehicles []*entity.Vehicle
+	err := vr.db.Where("driver_id = ?", driverId).Find(&vehicles).Error
+	if err != nil {
+		vr.log.Errorw("error getting vehicles by driver", "driverId", driverId, "error", err)
+		return nil, err
+	}
+	return vehicles, nil
+}
+
+func (vr vehicleRepository) Create(vehicle *entity.Vehicle, actor entity.Actor) error {
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(vehicle).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationCreate, nil, vehicle.AuditFields()))
+	})
+	if err != nil {
+		vr.log.Errorw("error creating vehicle", "vehicle", vehicle, "error", err)
+		return err
+	}
+	return nil
+}
+
+// CreateBatch creates the vehicles in a single transaction, so either all of
+// them or none are created.
+func (vr vehicleRepository) CreateBatch(vehicles []*entity.Vehicle, actor entity.Actor) error {
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.CreateInBatches(vehicles, createBatchSize).Error
+		if err != nil {
+			return err
+		}
+		entries := make([]*entity.AuditEntry, len(vehicles))
+		for i, vehicle := range vehicles {
+			entries[i] = auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationCreate, nil, vehicle.AuditFields())
+		}
+		return recordAudit(tx, entries...)
+	})
+	if err != nil {
+		vr.log.Errorw("error creating vehicles", "count", len(vehicles), "error", err)
+		return err
+	}
+	return nil
+}
+
+func (vr vehicleRepository) Update(vehicle *entity.Vehicle, actor entity.Actor) error {
+	version := vehicle.Version
+	vehicle.Version++
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Vehicle)
+		found, err := findLocked(tx, before, vehicle.ID)
+		if err != nil {
+			return err
+		}
+		err = saveVersioned(tx, vehicle, version)
+		if err != nil || !found {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationUpdate, before.AuditFields(), vehicle.AuditFields()))
+	})
 	if err != nil {
+		vehicle.Version = version
 		vr.log.Errorw("error updating vehicle", "vehicle", vehicle, "error", err)
 		return err
 	}
 	return nil
 }
 
-func (vr vehicleRepository) Delete(vehicleId int) error {
-	err := vr.db.Delete(&entity.Vehicle{}, vehicleId).Error
+// Replace creates the vehicle when it has no ID, or updates every field of
+// the vehicle with its ID otherwise. It returns whether the vehicle was created.
+func (vr vehicleRepository) Replace(vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
+	version := vehicle.Version
+	vehicle.Version++
+	created := false
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Vehicle)
+		found, err := findLocked(tx, before, vehicle.ID)
+		if err != nil {
+			return err
+		}
+		created, err = replaceVersioned(tx, vehicle, vehicle.ID, version)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationCreate, nil, vehicle.AuditFields()))
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationUpdate, before.AuditFields(), vehicle.AuditFields()))
+	})
+	if err != nil {
+		vehicle.Version = version
+		vr.log.Errorw("error replacing vehicle", "vehicle", vehicle, "error", err)
+		return false, err
+	}
+	return created, nil
+}
+
+func (vr vehicleRepository) Delete(vehicleId int, version uint, actor entity.Actor) error {
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		vehicle := new(entity.Vehicle)
+		found, err := findLocked(tx, vehicle, uint(vehicleId))
+		if err != nil {
+			return err
+		}
+		if !found {
+			return deleteVersioned(tx, &entity.Vehicle{}, vehicleId, version)
+		}
+		return deleteVehicle(tx, vehicle, version, actor)
+	})
 	if err != nil {
 		vr.log.Errorw("error deleting vehicle", "vehicleId", vehicleId, "error", err)
 		return err
 	}
 	return nil
 }
+
+// deleteVehicle deletes the locked vehicle and ends its assignment. It must
+// run inside a transaction.
+func deleteVehicle(tx *gorm.DB, vehicle *entity.Vehicle, version uint, actor entity.Actor) error {
+	err := deleteVersioned(tx, &entity.Vehicle{}, int(vehicle.ID), version)
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&entity.Assignment{}).
+		Where("vehicle_id = ? AND ended_at IS NULL", vehicle.ID).
+		Update("ended_at", time.Now()).Error
+	if err != nil {
+		return err
+	}
+	entries := []*entity.AuditEntry{
+		auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationDelete, vehicle.AuditFields(), nil),
+	}
+	if vehicle.DriverID != nil {
+		entries = append(entries, auditVehicleOf(actor, *vehicle.DriverID, entity.AuditOperationDelete, &vehicle.ID, nil))
+	}
+	return recordAudit(tx, entries...)
+}
+
+// Restore undeletes the vehicle without a driver, as its assignment ended
+// when it was deleted. A vehicle deleted while in use comes back available.
+func (vr vehicleRepository) Restore(vehicle *entity.Vehicle, actor entity.Actor) error {
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		err := lockDeleted(tx, vehicle, vehicle.ID)
+		if err != nil {
+			return err
+		}
+		columns := map[string]interface{}{"driver_id": nil}
+		if vehicle.Status == entity.VehicleStatusInUse {
+			columns["status"] = entity.VehicleStatusAvailable
+			err = recordStatusChange(tx, entity.NewStatusChange(actor, entity.AuditEntityVehicle, vehicle.ID,
+				vehicle.Status, entity.VehicleStatusAvailable, entity.StatusReasonUnassigned))
+			if err != nil {
+				return err
+			}
+		}
+		err = restoreDeleted(tx, vehicle, vehicle.ID, columns)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, vehicle.ID, entity.AuditOperationRestore, nil, vehicle.AuditFields()))
+	})
+	if err != nil {
+		vr.log.Errorw("error restoring vehicle", "vehicleId", vehicle.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// Purge removes the soft deleted vehicle for good, along with its
+// assignment history.
+func (vr vehicleRepository) Purge(vehicleId int, actor entity.Actor) error {
+	err := vr.db.Transaction(func(tx *gorm.DB) error {
+		before := new(entity.Vehicle)
+		err := lockDeleted(tx, before, uint(vehicleId))
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("vehicle_id = ?", vehicleId).Delete(&entity.Assignment{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Delete(&entity.Vehicle{}, vehicleId).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, auditDiff(actor, entity.AuditEntityVehicle, before.ID, entity.AuditOperationPurge, before.AuditFields(), nil))
+	})
+	if err != nil {
+		vr.log.Errorw("error purging vehicle", "vehicleId", vehicleId, "error", err)
+		return err
+	}
+	return nil
+}
+
+// BackfillClasses sets class on the vehicles saved before classes were
+// recorded, which have an empty class. Deleted vehicles are included, so
+// they are classified if restored. Like BackfillPlates, it neither changes
+// the version nor the audit log of the vehicles.
+func (vr vehicleRepository) BackfillClasses(class string) error {
+	result := vr.db.Unscoped().Model(&entity.Vehicle{}).
+		Where("class = '' OR class IS NULL").
+		UpdateColumn("class", class)
+	if result.Error != nil {
+		vr.log.Errorw("error backfilling classes", "class", class, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		vr.log.Infow("classes backfilled", "class", class, "vehicles", result.RowsAffected)
+	}
+	return nil
+}
+
+// BackfillPlates stores the plates saved before plates were normalised, e.g.
+// "ABC-1234", in their canonical form with the detected country and format,
+// so lookups by plate find them. Deleted vehicles are included, as the
+// unique index of the plate includes them.
+func (vr vehicleRepository) BackfillPlates() error {
+	var vehicles []*entity.Vehicle
+	var updated int
+	err := vr.db.Unscoped().Where("plate_format = '' OR plate_format IS NULL").
+		FindInBatches(&vehicles, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, vehicle := range vehicles {
+				plate := vehicle.Plate
+				vehicle.NormalizePlate()
+				if vehicle.Plate == plate && vehicle.PlateFormat == "" {
+					continue
+				}
+				err := vr.db.Unscoped().Model(vehicle).UpdateColumns(map[string]interface{}{
+					"plate":         vehicle.Plate,
+					"plate_country": vehicle.PlateCountry,
+					"plate_format":  vehicle.PlateFormat,
+				}).Error
+				if err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		vr.log.Errorw("error backfilling plates", "error", err)
+		return err
+	}
+	if updated > 0 {
+		vr.log.Infow("plates backfilled", "vehicles", updated)
+	}
+	return nil
+}
@@ -34,33 +34,106 @@ func (m *MockVehicleRepository) EXPECT() *MockVehicleRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockVehicleRepository) Create(vehicle *entity.Vehicle, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", vehicle, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVehicleRepositoryMockRecorder) Create(vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVehicleRepository)(nil).Create), vehicle, actor)
+}
+
+// CreateBatch mocks base method.
+func (m *MockVehicleRepository) CreateBatch(vehicles []*entity.Vehicle, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", vehicles, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockVehicleRepositoryMockRecorder) CreateBatch(vehicles, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockVehicleRepository)(nil).CreateBatch), vehicles, actor)
+}
+
 // Delete mocks base method.
-func (m *MockVehicleRepository) Delete(vehicleId int) error {
+func (m *MockVehicleRepository) Delete(vehicleId int, version uint, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", vehicleId)
+	ret := m.ctrl.Call(m, "Delete", vehicleId, version, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockVehicleRepositoryMockRecorder) Delete(vehicleId interface{}) *gomock.Call {
+func (mr *MockVehicleRepositoryMockRecorder) Delete(vehicleId, version, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleRepository)(nil).Delete), vehicleId, version, actor)
+}
+
+// Export mocks base method.
+func (m *MockVehicleRepository) Export(opts *entity.QueryOptions, batch func([]*entity.Vehicle) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", opts, batch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockVehicleRepositoryMockRecorder) Export(opts, batch interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleRepository)(nil).Delete), vehicleId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockVehicleRepository)(nil).Export), opts, batch)
 }
 
 // GetAll mocks base method.
-func (m *MockVehicleRepository) GetAll() ([]*entity.Vehicle, error) {
+func (m *MockVehicleRepository) GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", opts)
+	ret0, _ := ret[0].([]*entity.Vehicle)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockVehicleRepositoryMockRecorder) GetAll(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVehicleRepository)(nil).GetAll), opts)
+}
+
+// GetByDriver mocks base method.
+func (m *MockVehicleRepository) GetByDriver(driverId uint) ([]*entity.Vehicle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetByDriver", driverId)
 	ret0, _ := ret[0].([]*entity.Vehicle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockVehicleRepositoryMockRecorder) GetAll() *gomock.Call {
+// GetByDriver indicates an expected call of GetByDriver.
+func (mr *MockVehicleRepositoryMockRecorder) GetByDriver(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDriver", reflect.TypeOf((*MockVehicleRepository)(nil).GetByDriver), driverId)
+}
+
+// GetByExternalId mocks base method.
+func (m *MockVehicleRepository) GetByExternalId(source, externalId string) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalId", source, externalId)
+	ret0, _ := ret[0].(*entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalId indicates an expected call of GetByExternalId.
+func (mr *MockVehicleRepositoryMockRecorder) GetByExternalId(source, externalId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVehicleRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalId", reflect.TypeOf((*MockVehicleRepository)(nil).GetByExternalId), source, externalId)
 }
 
 // GetById mocks base method.
@@ -78,16 +151,89 @@ func (mr *MockVehicleRepositoryMockRecorder) GetById(vehicleId interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockVehicleRepository)(nil).GetById), vehicleId)
 }
 
+// GetByPlate mocks base method.
+func (m *MockVehicleRepository) GetByPlate(plate string) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPlate", plate)
+	ret0, _ := ret[0].(*entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPlate indicates an expected call of GetByPlate.
+func (mr *MockVehicleRepositoryMockRecorder) GetByPlate(plate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPlate", reflect.TypeOf((*MockVehicleRepository)(nil).GetByPlate), plate)
+}
+
+// GetDeletedById mocks base method.
+func (m *MockVehicleRepository) GetDeletedById(vehicleId int) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedById", vehicleId)
+	ret0, _ := ret[0].(*entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedById indicates an expected call of GetDeletedById.
+func (mr *MockVehicleRepositoryMockRecorder) GetDeletedById(vehicleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedById", reflect.TypeOf((*MockVehicleRepository)(nil).GetDeletedById), vehicleId)
+}
+
+// Purge mocks base method.
+func (m *MockVehicleRepository) Purge(vehicleId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", vehicleId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockVehicleRepositoryMockRecorder) Purge(vehicleId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockVehicleRepository)(nil).Purge), vehicleId, actor)
+}
+
+// Replace mocks base method.
+func (m *MockVehicleRepository) Replace(vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", vehicle, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockVehicleRepositoryMockRecorder) Replace(vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockVehicleRepository)(nil).Replace), vehicle, actor)
+}
+
+// Restore mocks base method.
+func (m *MockVehicleRepository) Restore(vehicle *entity.Vehicle, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", vehicle, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockVehicleRepositoryMockRecorder) Restore(vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockVehicleRepository)(nil).Restore), vehicle, actor)
+}
+
 // Update mocks base method.
-func (m *MockVehicleRepository) Update(vehicle *entity.Vehicle) error {
+func (m *MockVehicleRepository) Update(vehicle *entity.Vehicle, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", vehicle)
+	ret := m.ctrl.Call(m, "Update", vehicle, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockVehicleRepositoryMockRecorder) Update(vehicle interface{}) *gomock.Call {
+func (mr *MockVehicleRepositoryMockRecorder) Update(vehicle, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleRepository)(nil).Update), vehicle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleRepository)(nil).Update), vehicle, actor)
 }
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createBatchSize is the number of records inserted by each statement of a
+// batch create.
+const createBatchSize = 100
+
+// saveVersioned updates every column of value only while the stored version
+// is still version. The caller is responsible for incrementing the version of
+// value before saving.
+func saveVersioned(db *gorm.DB, value interface{}, version uint) error {
+	result := db.Model(value).
+		Where("version = ?", version).
+		Select("*").
+		Omit("CreatedAt", clause.Associations).
+		Updates(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// deleteVersioned deletes the record with id, checking its version when one
+// is informed.
+func deleteVersioned(db *gorm.DB, value interface{}, id int, version uint) error {
+	if version > 0 {
+		db = db.Where("version = ?", version)
+	}
+	result := db.Delete(value, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if version > 0 && result.RowsAffected == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// replaceVersioned creates value when id is zero, or updates every column of
+// the record with id while the stored version is still version. Records are
+// never created with an id chosen by the client, so a missing record with id
+// is gorm.ErrRecordNotFound. It returns whether value was created.
+func replaceVersioned(db *gorm.DB, value interface{}, id uint, version uint) (bool, error) {
+	if id == 0 {
+		err := db.Omit(clause.Associations).Create(value).Error
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var deleted []gorm.DeletedAt
+		err := tx.Unscoped().Model(value).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Pluck("deleted_at", &deleted).Error
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if deleted[0].Valid {
+			return ErrRecordDeleted
+		}
+		return saveVersioned(tx, value, version)
+	})
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+)
+
+var (
+	ErrVehicleAlreadyAssigned = domainerr.New(domainerr.Conflict, i18n.VehicleAlreadyAssigned)
+	ErrVehicleNotAssigned     = domainerr.New(domainerr.Conflict, i18n.VehicleNotAssigned)
+	ErrAssignmentNotFound     = domainerr.New(domainerr.NotFound, i18n.AssignmentNotFound)
+)
+
+type AssignmentUsecase interface {
+	GetByVehicle(vehicleId int) ([]*entity.Assignment, error)
+	GetByDriver(driverId int) ([]*entity.Assignment, error)
+	GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error)
+	Assign(vehicleId int, driverId int, actor entity.Actor) error
+	Unassign(vehicleId int, actor entity.Actor) error
+	Transfer(vehicleId int, driverId int, actor entity.Actor) error
+}
+
+type assignmentUsecase struct {
+	aRepo repository.AssignmentRepository
+	vRepo repository.VehicleRepository
+	dRepo repository.DriverRepository
+	uow   repository.UnitOfWork
+}
+
+func NewAssignmentUsecase(aRepo repository.AssignmentRepository, vRepo repository.VehicleRepository, dRepo repository.DriverRepository, uow repository.UnitOfWork) *assignmentUsecase {
+	return &assignmentUsecase{aRepo: aRepo, vRepo: vRepo, dRepo: dRepo, uow: uow}
+}
+
+// inTransaction runs fn with a copy of the usecase whose repositories are
+// bound to a unit of work, so what fn reads and writes is committed or
+// rolled back together.
+func (au assignmentUsecase) inTransaction(fn func(tx assignmentUsecase) error) error {
+	return au.uow.Do(func(repos repository.Repositories) error {
+		tx := au
+		tx.aRepo, tx.vRepo, tx.dRepo = repos.Assignments, repos.Vehicles, repos.Drivers
+		return fn(tx)
+	})
+}
+
+func (au assignmentUsecase) GetByVehicle(vehicleId int) ([]*entity.Assignment, error) {
+	vehicle, err := getVehicle(au.vRepo, vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	return au.aRepo.GetByVehicle(vehicle.ID)
+}
+
+func (au assignmentUsecase) GetByDriver(driverId int) ([]*entity.Assignment, error) {
+	driver, err := getDriver(au.dRepo, driverId)
+	if err != nil {
+		return nil, err
+	}
+	return au.aRepo.GetByDriver(driver.ID)
+}
+
+func (au assignmentUsecase) GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error) {
+	if entity.NormalizePlate(plate) == "" {
+		return nil, entity.NewErrorInvalidField("plate", entity.CodeRequired, nil, i18n.VehiclePlateInvalid)
+	}
+	assignment, err := au.aRepo.GetByPlateAt(plate, at)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil {
+		return nil, ErrAssignmentNotFound
+	}
+	return assignment, nil
+}
+
+func (au assignmentUsecase) Assign(vehicleId int, driverId int, actor entity.Actor) error {
+	return au.inTransaction(func(tx assignmentUsecase) error {
+		vehicle, err := getVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		driver, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		return assign(tx.aRepo, vehicle, driver, actor)
+	})
+}
+
+func (au assignmentUsecase) Unassign(vehicleId int, actor entity.Actor) error {
+	return au.inTransaction(func(tx assignmentUsecase) error {
+		vehicle, err := getVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		return unassign(tx.aRepo, vehicle, actor)
+	})
+}
+
+func (au assignmentUsecase) Transfer(vehicleId int, driverId int, actor entity.Actor) error {
+	return au.inTransaction(func(tx assignmentUsecase) error {
+		vehicle, err := getVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		driver, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		return transfer(tx.aRepo, vehicle, driver, actor)
+	})
+}
+
+// The vehicle and the driver scoped endpoints both change assignments through
+// assign, unassign and transfer, so they follow the same rules.
+
+// assign assigns the vehicle to the driver. Assigning a vehicle already
+// assigned to the driver does nothing, while a vehicle assigned to another
+// driver must be transferred instead.
+func assign(aRepo repository.AssignmentRepository, vehicle *entity.Vehicle, driver *entity.Driver, actor entity.Actor) error {
+	if vehicle.DriverID != nil {
+		if *vehicle.DriverID == driver.ID {
+			return nil
+		}
+		return ErrVehicleAlreadyAssigned
+	}
+	err := checkAssignment(driver, vehicle)
+	if err != nil {
+		return err
+	}
+	return aRepo.Assign(vehicle, driver.ID, actor)
+}
+
+// unassign ends the assignment of the vehicle.
+func unassign(aRepo repository.AssignmentRepository, vehicle *entity.Vehicle, actor entity.Actor) error {
+	if vehicle.DriverID == nil {
+		return ErrVehicleNotAssigned
+	}
+	return aRepo.Unassign(vehicle, actor)
+}
+
+// transfer moves the vehicle from its driver to another one, closing the
+// current assignment and starting the new one atomically.
+func transfer(aRepo repository.AssignmentRepository, vehicle *entity.Vehicle, driver *entity.Driver, actor entity.Actor) error {
+	if vehicle.DriverID == nil {
+		return ErrVehicleNotAssigned
+	}
+	if *vehicle.DriverID == driver.ID {
+		return entity.NewErrorInvalidField("driverId", entity.CodeInvalid, int(driver.ID), i18n.VehicleAlreadyAssignedToDriver)
+	}
+	err := checkAssignment(driver, vehicle)
+	if err != nil {
+		return err
+	}
+	return aRepo.Assign(vehicle, driver.ID, actor)
+}
+
+// checkAssignment makes sure the driver is allowed to drive the vehicle.
+func checkAssignment(driver *entity.Driver, vehicle *entity.Vehicle) error {
+	err := checkAssignableStatus(driver, vehicle)
+	if err != nil {
+		return err
+	}
+	if driver.LicenseExpired(time.Now()) {
+		return ErrDriverLicenseExpired
+	}
+	return driver.CheckLicense(*vehicle)
+}
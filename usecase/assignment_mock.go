@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/assignment.go
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAssignmentUsecase is a mock of AssignmentUsecase interface.
+type MockAssignmentUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssignmentUsecaseMockRecorder
+}
+
+// MockAssignmentUsecaseMockRecorder is the mock recorder for MockAssignmentUsecase.
+type MockAssignmentUsecaseMockRecorder struct {
+	mock *MockAssignmentUsecase
+}
+
+// NewMockAssignmentUsecase creates a new mock instance.
+func NewMockAssignmentUsecase(ctrl *gomock.Controller) *MockAssignmentUsecase {
+	mock := &MockAssignmentUsecase{ctrl: ctrl}
+	mock.recorder = &MockAssignmentUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAssignmentUsecase) EXPECT() *MockAssignmentUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Assign mocks base method.
+func (m *MockAssignmentUsecase) Assign(vehicleId, driverId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Assign", vehicleId, driverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Assign indicates an expected call of Assign.
+func (mr *MockAssignmentUsecaseMockRecorder) Assign(vehicleId, driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Assign", reflect.TypeOf((*MockAssignmentUsecase)(nil).Assign), vehicleId, driverId, actor)
+}
+
+// GetByDriver mocks base method.
+func (m *MockAssignmentUsecase) GetByDriver(driverId int) ([]*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDriver", driverId)
+	ret0, _ := ret[0].([]*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDriver indicates an expected call of GetByDriver.
+func (mr *MockAssignmentUsecaseMockRecorder) GetByDriver(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDriver", reflect.TypeOf((*MockAssignmentUsecase)(nil).GetByDriver), driverId)
+}
+
+// GetByPlateAt mocks base method.
+func (m *MockAssignmentUsecase) GetByPlateAt(plate string, at time.Time) (*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPlateAt", plate, at)
+	ret0, _ := ret[0].(*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPlateAt indicates an expected call of GetByPlateAt.
+func (mr *MockAssignmentUsecaseMockRecorder) GetByPlateAt(plate, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPlateAt", reflect.TypeOf((*MockAssignmentUsecase)(nil).GetByPlateAt), plate, at)
+}
+
+// GetByVehicle mocks base method.
+func (m *MockAssignmentUsecase) GetByVehicle(vehicleId int) ([]*entity.Assignment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByVehicle", vehicleId)
+	ret0, _ := ret[0].([]*entity.Assignment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByVehicle indicates an expected call of GetByVehicle.
+func (mr *MockAssignmentUsecaseMockRecorder) GetByVehicle(vehicleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByVehicle", reflect.TypeOf((*MockAssignmentUsecase)(nil).GetByVehicle), vehicleId)
+}
+
+// Transfer mocks base method.
+func (m *MockAssignmentUsecase) Transfer(vehicleId, driverId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", vehicleId, driverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAssignmentUsecaseMockRecorder) Transfer(vehicleId, driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAssignmentUsecase)(nil).Transfer), vehicleId, driverId, actor)
+}
+
+// Unassign mocks base method.
+func (m *MockAssignmentUsecase) Unassign(vehicleId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unassign", vehicleId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unassign indicates an expected call of Unassign.
+func (mr *MockAssignmentUsecaseMockRecorder) Unassign(vehicleId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unassign", reflect.TypeOf((*MockAssignmentUsecase)(nil).Unassign), vehicleId, actor)
+}
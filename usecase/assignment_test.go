@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_assignmentUsecase_GetByPlateAt(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		plate   string
+		setup   func(mockAssignmentRepo *repository.MockAssignmentRepository)
+		want    error
+		wantErr bool
+	}{
+		{
+			name:  "Should return the assignment active at the moment",
+			plate: "ABC1D23",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockAssignmentRepo.EXPECT().GetByPlateAt("ABC1D23", at).Return(&entity.Assignment{DriverID: 1, VehicleID: 2, StartedAt: at}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Should return error when plate is empty",
+			plate:   " ",
+			setup:   func(mockAssignmentRepo *repository.MockAssignmentRepository) {},
+			want:    entity.NewErrorInvalidField("plate", entity.CodeRequired, nil, i18n.VehiclePlateInvalid),
+			wantErr: true,
+		},
+		{
+			name:  "Should return assignment not found error",
+			plate: "ABC1D23",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockAssignmentRepo.EXPECT().GetByPlateAt("ABC1D23", at).Return(nil, nil)
+			},
+			want:    ErrAssignmentNotFound,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			tt.setup(mockAssignmentRepo)
+
+			au := NewAssignmentUsecase(mockAssignmentRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+
+			got, err := au.GetByPlateAt(tt.plate, at)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, uint(1), got.DriverID)
+		})
+	}
+}
+
+func Test_assignmentUsecase_Assign(t *testing.T) {
+	driverId := uint(1)
+	otherDriverId := uint(3)
+	expiredAt := time.Now().AddDate(0, 0, -1)
+	tests := []struct {
+		name    string
+		setup   func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository)
+		want    error
+		wantErr bool
+	}{
+		{
+			name: "Should assign vehicle to driver",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockAssignmentRepo.EXPECT().Assign(vehicle, uint(1), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should do nothing when vehicle is already assigned to the driver",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &driverId}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error when vehicle is assigned to another driver",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &otherDriverId}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want:    ErrVehicleAlreadyAssigned,
+			wantErr: true,
+		},
+		{
+			name: "Should return vehicle not found error",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(nil, nil)
+			},
+			want:    ErrVehicleNotFound,
+			wantErr: true,
+		},
+		{
+			name: "Should return driver not found error",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(nil, nil)
+			},
+			want:    ErrDriverNotFound,
+			wantErr: true,
+		},
+		{
+			name: "Should return error when driver license is expired",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B", LicenseExpiresAt: &expiredAt}, nil)
+			},
+			want:    ErrDriverLicenseExpired,
+			wantErr: true,
+		},
+		{
+			name: "Should return error when driver is not active",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusSuspended, LicenseType: "B"}, nil)
+			},
+			want:    domainerr.New(domainerr.Unprocessable, i18n.DriverNotActive, entity.DriverStatusSuspended),
+			wantErr: true,
+		},
+		{
+			name: "Should return error when vehicle is in maintenance",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInMaintenance, Class: "car"}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want:    domainerr.New(domainerr.Unprocessable, i18n.VehicleNotAvailable, entity.VehicleStatusInMaintenance),
+			wantErr: true,
+		},
+		{
+			name: "Should return error when driver license does not allow the vehicle class",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "bus", Plate: "ABC1234"}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want: &entity.ErrorIncompatibleLicense{
+				LicenseType:  "B",
+				VehicleClass: "bus",
+				Plate:        "ABC1234",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockAssignmentRepo.EXPECT().Assign(gomock.Any(), uint(1), testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			want:    fmt.Errorf("some error occurred"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo)
+
+			au := NewAssignmentUsecase(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+
+			err := au.Assign(2, 1, testActor)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_assignmentUsecase_Unassign(t *testing.T) {
+	driverId := uint(1)
+	tests := []struct {
+		name    string
+		setup   func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		want    error
+		wantErr bool
+	}{
+		{
+			name: "Should unassign vehicle",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, DriverID: &driverId}
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockAssignmentRepo.EXPECT().Unassign(vehicle, testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error when vehicle is not assigned",
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}}, nil)
+			},
+			want:    ErrVehicleNotAssigned,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockAssignmentRepo, mockVehicleRepo)
+
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			au := NewAssignmentUsecase(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+
+			err := au.Unassign(2, testActor)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_assignmentUsecase_Transfer(t *testing.T) {
+	driverId := uint(1)
+	tests := []struct {
+		name     string
+		driverId int
+		setup    func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository)
+		want     error
+		wantErr  bool
+	}{
+		{
+			name:     "Should transfer vehicle to another driver",
+			driverId: 3,
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, Class: "car", DriverID: &driverId}
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockDriverRepo.EXPECT().GetById(3, false).Return(&entity.Driver{Model: gorm.Model{ID: 3}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockAssignmentRepo.EXPECT().Assign(vehicle, uint(3), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Should return error when vehicle is not assigned",
+			driverId: 3,
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable}, nil)
+				mockDriverRepo.EXPECT().GetById(3, false).Return(&entity.Driver{Model: gorm.Model{ID: 3}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want:    ErrVehicleNotAssigned,
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when transferring to the current driver",
+			driverId: 1,
+			setup: func(mockAssignmentRepo *repository.MockAssignmentRepository, mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &driverId}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want:    entity.NewErrorInvalidField("driverId", entity.CodeInvalid, 1, i18n.VehicleAlreadyAssignedToDriver),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo)
+
+			au := NewAssignmentUsecase(mockAssignmentRepo, mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+
+			err := au.Transfer(2, tt.driverId, testActor)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
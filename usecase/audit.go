@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+)
+
+type AuditUsecase interface {
+	GetByDriver(driverId int) ([]*entity.AuditEntry, error)
+	GetByVehicle(vehicleId int) ([]*entity.AuditEntry, error)
+}
+
+type auditUsecase struct {
+	auRepo repository.AuditRepository
+	dRepo  repository.DriverRepository
+	vRepo  repository.VehicleRepository
+}
+
+func NewAuditUsecase(auRepo repository.AuditRepository, dRepo repository.DriverRepository, vRepo repository.VehicleRepository) *auditUsecase {
+	return &auditUsecase{auRepo: auRepo, dRepo: dRepo, vRepo: vRepo}
+}
+
+// GetByDriver returns the changes of the driver, the latest first. The
+// history of a deleted driver is still returned.
+func (au auditUsecase) GetByDriver(driverId int) ([]*entity.AuditEntry, error) {
+	if driverId <= 0 {
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
+	}
+	entries, err := au.auRepo.GetByEntity(entity.AuditEntityDriver, uint(driverId))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		_, err = getDriver(au.dRepo, driverId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// GetByVehicle returns the changes of the vehicle, the latest first. The
+// history of a deleted vehicle is still returned.
+func (au auditUsecase) GetByVehicle(vehicleId int) ([]*entity.AuditEntry, error) {
+	if vehicleId <= 0 {
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
+	}
+	entries, err := au.auRepo.GetByEntity(entity.AuditEntityVehicle, uint(vehicleId))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		_, err = getVehicle(au.vRepo, vehicleId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
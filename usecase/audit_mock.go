@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/audit.go
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	reflect "reflect"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditUsecase is a mock of AuditUsecase interface.
+type MockAuditUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditUsecaseMockRecorder
+}
+
+// MockAuditUsecaseMockRecorder is the mock recorder for MockAuditUsecase.
+type MockAuditUsecaseMockRecorder struct {
+	mock *MockAuditUsecase
+}
+
+// NewMockAuditUsecase creates a new mock instance.
+func NewMockAuditUsecase(ctrl *gomock.Controller) *MockAuditUsecase {
+	mock := &MockAuditUsecase{ctrl: ctrl}
+	mock.recorder = &MockAuditUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditUsecase) EXPECT() *MockAuditUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetByDriver mocks base method.
+func (m *MockAuditUsecase) GetByDriver(driverId int) ([]*entity.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDriver", driverId)
+	ret0, _ := ret[0].([]*entity.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDriver indicates an expected call of GetByDriver.
+func (mr *MockAuditUsecaseMockRecorder) GetByDriver(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDriver", reflect.TypeOf((*MockAuditUsecase)(nil).GetByDriver), driverId)
+}
+
+// GetByVehicle mocks base method.
+func (m *MockAuditUsecase) GetByVehicle(vehicleId int) ([]*entity.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByVehicle", vehicleId)
+	ret0, _ := ret[0].([]*entity.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByVehicle indicates an expected call of GetByVehicle.
+func (mr *MockAuditUsecaseMockRecorder) GetByVehicle(vehicleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByVehicle", reflect.TypeOf((*MockAuditUsecase)(nil).GetByVehicle), vehicleId)
+}
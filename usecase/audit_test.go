@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_auditUsecase_GetByDriver(t *testing.T) {
+	entries := []*entity.AuditEntry{
+		{ID: 2, EntityType: entity.AuditEntityDriver, EntityID: 1, Operation: entity.AuditOperationUpdate, Actor: "tester"},
+		{ID: 1, EntityType: entity.AuditEntityDriver, EntityID: 1, Operation: entity.AuditOperationCreate, Actor: "tester"},
+	}
+	tests := []struct {
+		name     string
+		driverId int
+		setup    func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository)
+		want     []*entity.AuditEntry
+		wantErr  error
+	}{
+		{
+			name:     "Should return the history of the driver",
+			driverId: 1,
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityDriver, uint(1)).Return(entries, nil)
+			},
+			want: entries,
+		},
+		{
+			name:     "Should return an empty history of a driver created before the audit log",
+			driverId: 1,
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityDriver, uint(1)).Return(nil, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+			},
+			want: nil,
+		},
+		{
+			name:     "Should return driver not found error",
+			driverId: 1,
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityDriver, uint(1)).Return(nil, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(nil, nil)
+			},
+			wantErr: ErrDriverNotFound,
+		},
+		{
+			name:     "Should return error for invalid id",
+			driverId: 0,
+			setup:    func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository) {},
+			wantErr:  entity.NewErrorInvalidField("id", entity.CodeInvalid, 0, i18n.DriverIdInvalid),
+		},
+		{
+			name:     "Should return error",
+			driverId: 1,
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityDriver, uint(1)).Return(nil, fmt.Errorf("some error occurred"))
+			},
+			wantErr: fmt.Errorf("some error occurred"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAuditRepo := repository.NewMockAuditRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockAuditRepo, mockDriverRepo)
+
+			au := NewAuditUsecase(mockAuditRepo, mockDriverRepo, repository.NewMockVehicleRepository(ctrl))
+
+			got, err := au.GetByDriver(tt.driverId)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_auditUsecase_GetByVehicle(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(mockAuditRepo *repository.MockAuditRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		want    []*entity.AuditEntry
+		wantErr error
+	}{
+		{
+			name: "Should return the history of a deleted vehicle",
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityVehicle, uint(2)).Return([]*entity.AuditEntry{
+					{ID: 3, EntityType: entity.AuditEntityVehicle, EntityID: 2, Operation: entity.AuditOperationDelete},
+				}, nil)
+			},
+			want: []*entity.AuditEntry{
+				{ID: 3, EntityType: entity.AuditEntityVehicle, EntityID: 2, Operation: entity.AuditOperationDelete},
+			},
+		},
+		{
+			name: "Should return vehicle not found error",
+			setup: func(mockAuditRepo *repository.MockAuditRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockAuditRepo.EXPECT().GetByEntity(entity.AuditEntityVehicle, uint(2)).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(nil, nil)
+			},
+			wantErr: ErrVehicleNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockAuditRepo := repository.NewMockAuditRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockAuditRepo, mockVehicleRepo)
+
+			au := NewAuditUsecase(mockAuditRepo, repository.NewMockDriverRepository(ctrl), mockVehicleRepo)
+
+			got, err := au.GetByVehicle(2)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
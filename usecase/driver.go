@@ -1,46 +1,101 @@
 package usecase
 
 import (
-	"errors"
+	"time"
 
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/repository"
 	"go.uber.org/zap"
 )
 
-var ErrDriverNotFound = errors.New("driver not found")
+var (
+	ErrDriverNotFound       = domainerr.New(domainerr.NotFound, i18n.DriverNotFound)
+	ErrDriverLicenseExpired = domainerr.New(domainerr.Unprocessable, i18n.DriverLicenseExpired)
+	ErrVehicleNotOwned      = domainerr.New(domainerr.NotFound, i18n.VehicleNotOwned)
+	ErrVehicleDriverChanged = repository.ErrVehicleDriverChanged
+	ErrVersionMismatch      = repository.ErrVersionMismatch
+	ErrRecordDeleted        = repository.ErrRecordDeleted
+	ErrRecordNotDeleted     = repository.ErrRecordNotDeleted
+	ErrDriverHasVehicles    = repository.ErrDriverHasVehicles
+	ErrPurgeNotAllowed      = domainerr.New(domainerr.Forbidden, i18n.PurgeNotAllowed)
+)
 
 type DriverUsecase interface {
-	GetAll() ([]*entity.Driver, error)
+	GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error)
+	Export(opts *entity.QueryOptions, write func([]*entity.Driver) error) error
 	GetById(driverId int, includeVehicle bool) (*entity.Driver, error)
-	Create(driver *entity.Driver) error
-	AddVehicle(driverId int, vehicle *entity.Vehicle) error
-	Update(driverId int, driver *entity.Driver) error
-	Delete(driverId int) error
+	GetByExternalId(source string, externalId string) (*entity.Driver, error)
+	GetExpiring(within time.Duration) ([]*entity.Driver, error)
+	Create(driver *entity.Driver, actor entity.Actor) error
+	ImportDrivers(rows []entity.DriverImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error)
+	AddVehicle(driverId int, vehicle *entity.Vehicle, actor entity.Actor) error
+	AttachVehicle(driverId int, vehicleId int, version uint, actor entity.Actor) error
+	DetachVehicle(driverId int, vehicleId int, version uint, actor entity.Actor) error
+	TransferVehicle(driverId int, vehicleId int, toDriverId int, actor entity.Actor) error
+	Update(driverId int, patch *entity.Patch, actor entity.Actor) error
+	Replace(driverId int, driver *entity.Driver, actor entity.Actor) (bool, error)
+	ReplaceByExternalId(source string, externalId string, driver *entity.Driver, actor entity.Actor) (bool, error)
+	Delete(driverId int, version uint, policy string, actor entity.Actor) error
+	Restore(driverId int, actor entity.Actor) (*entity.Driver, error)
+	Purge(driverId int, actor entity.Actor) error
 }
 
 type driverUsecase struct {
 	log   *zap.SugaredLogger
 	dRepo repository.DriverRepository
+	vRepo repository.VehicleRepository
+	uow   repository.UnitOfWork
+	// aRepo is only bound inside inTransaction, as assignments are always
+	// changed in a unit of work
+	aRepo repository.AssignmentRepository
+}
+
+func NewDriverUsecase(log *zap.SugaredLogger, dRepo repository.DriverRepository, vRepo repository.VehicleRepository, uow repository.UnitOfWork) *driverUsecase {
+	return &driverUsecase{log: log, dRepo: dRepo, vRepo: vRepo, uow: uow}
 }
 
-func NewDriverUsecase(log *zap.SugaredLogger, dRepo repository.DriverRepository) *driverUsecase {
-	return &driverUsecase{log: log, dRepo: dRepo}
+// inTransaction runs fn with a copy of the usecase whose repositories are
+// bound to a unit of work, so what fn reads and writes is committed or
+// rolled back together.
+func (du driverUsecase) inTransaction(fn func(tx driverUsecase) error) error {
+	return du.uow.Do(func(repos repository.Repositories) error {
+		tx := du
+		tx.dRepo, tx.vRepo, tx.aRepo = repos.Drivers, repos.Vehicles, repos.Assignments
+		return fn(tx)
+	})
 }
 
-func (du driverUsecase) GetAll() ([]*entity.Driver, error) {
-	drivers, err := du.dRepo.GetAll()
+func (du driverUsecase) GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error) {
+	if opts == nil {
+		opts = entity.NewQueryOptions()
+	}
+	err := opts.Validate(entity.DriverQueryFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return drivers, nil
+
+	drivers, total, err := du.dRepo.GetAll(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return drivers, total, nil
+}
+
+// Export calls write with the drivers matching the filters of opts, a batch
+// at a time, ordered by id.
+func (du driverUsecase) Export(opts *entity.QueryOptions, write func([]*entity.Driver) error) error {
+	opts, err := exportOptions(opts, entity.DriverQueryFields)
+	if err != nil {
+		return err
+	}
+	return du.dRepo.Export(opts, write)
 }
 
 func (du driverUsecase) GetById(driverId int, includeVehicle bool) (*entity.Driver, error) {
 	if driverId <= 0 {
-		return nil, &entity.ErrorInvalidField{
-			Message: []string{"driver id is invalid"},
-		}
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
 	}
 	driver, err := du.dRepo.GetById(driverId, includeVehicle)
 	if err != nil {
@@ -49,41 +104,255 @@ func (du driverUsecase) GetById(driverId int, includeVehicle bool) (*entity.Driv
 	return driver, nil
 }
 
-func (du driverUsecase) Create(driver *entity.Driver) error {
+// GetByExternalId returns the driver with the id of the system it is mastered in.
+func (du driverUsecase) GetByExternalId(source string, externalId string) (*entity.Driver, error) {
+	err := entity.ValidateExternalId(source, externalId)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := du.dRepo.GetByExternalId(source, externalId)
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// GetExpiring returns the drivers whose license expires within the given
+// duration from now, including the ones already expired.
+func (du driverUsecase) GetExpiring(within time.Duration) ([]*entity.Driver, error) {
+	if within < 0 {
+		return nil, entity.NewErrorInvalidField("within", entity.CodeOutOfRange, within.String(), i18n.WithinInvalid)
+	}
+	drivers, err := du.dRepo.GetByLicenseExpiration(time.Now().Add(within))
+	if err != nil {
+		return nil, err
+	}
+	return drivers, nil
+}
+
+func (du driverUsecase) Create(driver *entity.Driver, actor entity.Actor) error {
 	if driver == nil {
-		return &entity.ErrorInvalidField{
-			Message: []string{"driver is invalid"},
-		}
+		return entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.DriverRequired)
 	}
+	driver.NormalizeDocuments()
 	err := driver.Validate()
 	if err != nil {
 		return err
 	}
 
-	err = du.dRepo.Create(driver)
+	err = du.checkUniqueFields(driver, entity.Driver{})
+	if err != nil {
+		return err
+	}
+
+	err = du.dRepo.Create(driver, actor)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (du driverUsecase) AddVehicle(driverId int, vehicle *entity.Vehicle) error {
-	if driverId <= 0 {
-		return &entity.ErrorInvalidField{
-			Message: []string{"driver id is invalid"},
+// ImportDrivers validates the drivers read from a file and creates the valid
+// ones. An all or nothing import creates none of them when any row is
+// invalid, and a dry run only validates them.
+func (du driverUsecase) ImportDrivers(rows []entity.DriverImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error) {
+	opts, err := importOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := entity.NewImportReport(*opts, len(rows))
+	keys := importKeys{}
+	var valid []entity.DriverImportRow
+	for _, row := range rows {
+		err := du.checkImportRow(row, keys)
+		if err != nil {
+			if !isRowError(err) {
+				return nil, err
+			}
+			report.Reject(row.Line, err)
+			continue
 		}
+		valid = append(valid, row)
 	}
-	if vehicle == nil {
-		return &entity.ErrorInvalidField{
-			Message: []string{"vehicle is invalid"},
+	report.Valid = len(valid)
+	if opts.DryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	if opts.Mode == entity.ImportModeAllOrNothing {
+		if report.Failed() > 0 {
+			return report, nil
 		}
+		drivers := make([]*entity.Driver, len(valid))
+		for i, row := range valid {
+			drivers[i] = row.Driver
+		}
+		err = du.dRepo.CreateBatch(drivers, actor)
+		if err != nil {
+			return nil, err
+		}
+		report.Imported = len(drivers)
+		return report, nil
+	}
+
+	for _, row := range valid {
+		err = du.dRepo.Create(row.Driver, actor)
+		if err != nil {
+			if !isRowError(err) {
+				return nil, err
+			}
+			report.Reject(row.Line, err)
+			continue
+		}
+		report.Imported++
 	}
+	return report, nil
+}
 
+// checkImportRow validates the driver of row like Create does, and makes
+// sure its unique fields were not used by a previous row of the file.
+func (du driverUsecase) checkImportRow(row entity.DriverImportRow, keys importKeys) error {
+	if row.Driver == nil {
+		return row.Err
+	}
+	row.Driver.NormalizeDocuments()
+	err := rowError(row.Err, row.Driver.Validate())
+	if err != nil {
+		return err
+	}
+
+	err = keys.check(row.Line,
+		importKey{field: "email", value: row.Driver.Email},
+		importKey{field: "cpf", value: row.Driver.CPF},
+		importKey{field: "license", value: row.Driver.License},
+		importKey{field: "externalId", value: externalRef(row.Driver.ExternalSource, row.Driver.ExternalID)},
+	)
+	if err != nil {
+		return err
+	}
+	return du.checkUniqueFields(row.Driver, entity.Driver{})
+}
+
+func (du driverUsecase) AddVehicle(driverId int, vehicle *entity.Vehicle, actor entity.Actor) error {
+	if driverId <= 0 {
+		return entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
+	}
+	if vehicle == nil {
+		return entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.VehicleRequired)
+	}
+
+	vehicle.Status = entity.VehicleStatusAvailable
+	vehicle.NormalizePlate()
 	err := vehicle.Validate()
 	if err != nil {
 		return err
 	}
 
+	return du.inTransaction(func(tx driverUsecase) error {
+		driver, err := tx.dRepo.GetById(driverId, false)
+		if err != nil {
+			return err
+		}
+		if driver == nil {
+			return ErrDriverNotFound
+		}
+		err = checkAssignment(driver, vehicle)
+		if err != nil {
+			return err
+		}
+
+		err = checkPlate(tx.vRepo, vehicle)
+		if err != nil {
+			return err
+		}
+
+		err = checkExternalId(tx.vRepo, vehicle)
+		if err != nil {
+			return err
+		}
+
+		return tx.dRepo.AddVehicle(driver, vehicle, actor)
+	})
+}
+
+// AttachVehicle assigns an existing vehicle to the driver, with the same
+// rules as assigning it through the vehicle. When version is not zero the
+// vehicle is only attached if it was not changed since that version.
+func (du driverUsecase) AttachVehicle(driverId int, vehicleId int, version uint, actor entity.Actor) error {
+	return du.inTransaction(func(tx driverUsecase) error {
+		driver, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		vehicle, err := getVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		if version != 0 && version != vehicle.Version {
+			return ErrVersionMismatch
+		}
+		return assign(tx.aRepo, vehicle, driver, actor)
+	})
+}
+
+// DetachVehicle unassigns a vehicle of the driver. When version is not zero
+// the vehicle is only detached if it was not changed since that version.
+func (du driverUsecase) DetachVehicle(driverId int, vehicleId int, version uint, actor entity.Actor) error {
+	return du.inTransaction(func(tx driverUsecase) error {
+		driver, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		vehicle, err := tx.getOwnedVehicle(driver, vehicleId)
+		if err != nil {
+			return err
+		}
+		if version != 0 && version != vehicle.Version {
+			return ErrVersionMismatch
+		}
+
+		return unassign(tx.aRepo, vehicle, actor)
+	})
+}
+
+// TransferVehicle moves a vehicle of the driver to another driver, with the
+// same rules as transferring it through the vehicle.
+func (du driverUsecase) TransferVehicle(driverId int, vehicleId int, toDriverId int, actor entity.Actor) error {
+	if driverId == toDriverId {
+		return entity.NewErrorInvalidField("driverId", entity.CodeInvalid, toDriverId, i18n.VehicleAlreadyAssignedToDriver)
+	}
+	return du.inTransaction(func(tx driverUsecase) error {
+		driver, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		vehicle, err := tx.getOwnedVehicle(driver, vehicleId)
+		if err != nil {
+			return err
+		}
+		toDriver, err := getDriver(tx.dRepo, toDriverId)
+		if err != nil {
+			return err
+		}
+		return transfer(tx.aRepo, vehicle, toDriver, actor)
+	})
+}
+
+// Update applies patch to the driver. When the version of the patch is not
+// zero the driver is only changed if it was not changed since that version.
+func (du driverUsecase) Update(driverId int, patch *entity.Patch, actor entity.Actor) error {
+	if driverId <= 0 {
+		return entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
+	}
+	return du.inTransaction(func(tx driverUsecase) error {
+		return tx.update(driverId, patch, actor)
+	})
+}
+
+// update is Update inside a unit of work, so the vehicles checked against a
+// new license can not change before the driver is saved.
+func (du driverUsecase) update(driverId int, patch *entity.Patch, actor entity.Actor) error {
 	driver, err := du.dRepo.GetById(driverId, false)
 	if err != nil {
 		return err
@@ -91,66 +360,305 @@ func (du driverUsecase) AddVehicle(driverId int, vehicle *entity.Vehicle) error
 	if driver == nil {
 		return ErrDriverNotFound
 	}
-	err = du.dRepo.AddVehicle(driver, vehicle)
+	if patch.Version != 0 && patch.Version != driver.Version {
+		return ErrVersionMismatch
+	}
+	current := *driver
+
+	var doc driverDocument
+	err = applyPatch(newDriverDocument(driver), &doc, patch)
+	if err != nil {
+		return err
+	}
+	err = doc.apply(driver)
+	if err != nil {
+		return err
+	}
+
+	driver.NormalizeDocuments()
+	err = driver.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = du.checkUniqueFields(driver, current)
+	if err != nil {
+		return err
+	}
+
+	if driver.LicenseType != current.LicenseType {
+		err = du.checkVehicles(driver)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = du.dRepo.Update(driver, actor)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (du driverUsecase) Update(driverId int, updateDriver *entity.Driver) error {
+// Replace overwrites every field of the driver with driverId. Drivers are
+// never created with an id chosen by the client, so an unknown id is
+// ErrDriverNotFound and the returned bool, whether the driver was created, is
+// always false. When the version of driver is not zero the driver is only
+// replaced if it was not changed since that version.
+func (du driverUsecase) Replace(driverId int, driver *entity.Driver, actor entity.Actor) (bool, error) {
 	if driverId <= 0 {
-		return &entity.ErrorInvalidField{
-			Message: []string{"driver id is invalid"},
-		}
+		return false, entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
 	}
-	driver, err := du.dRepo.GetById(driverId, false)
-	if err != nil {
+	if driver == nil {
+		return false, entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.DriverRequired)
+	}
+	var created bool
+	err := du.inTransaction(func(tx driverUsecase) error {
+		current, err := tx.dRepo.GetById(driverId, false)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return missingDriver(tx.dRepo, driverId)
+		}
+		driver.ID = uint(driverId)
+		created, err = tx.replace(driver, current, actor)
 		return err
+	})
+	return created, err
+}
+
+// ReplaceByExternalId is Replace for the driver with the external id, which
+// is created with that external id when it does not exist. It is the only
+// replace that creates drivers.
+func (du driverUsecase) ReplaceByExternalId(source string, externalId string, driver *entity.Driver, actor entity.Actor) (bool, error) {
+	err := entity.ValidateExternalId(source, externalId)
+	if err != nil {
+		return false, err
 	}
 	if driver == nil {
-		return ErrDriverNotFound
+		return false, entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.DriverRequired)
 	}
-	if updateDriver.Name != "" {
-		driver.Name = updateDriver.Name
+	var created bool
+	err = du.inTransaction(func(tx driverUsecase) error {
+		current, err := tx.dRepo.GetByExternalId(source, externalId)
+		if err != nil {
+			return err
+		}
+		driver.ID = 0
+		if current != nil {
+			driver.ID = current.ID
+		}
+		driver.ExternalSource = &source
+		driver.ExternalID = &externalId
+		created, err = tx.replace(driver, current, actor)
+		return err
+	})
+	return created, err
+}
+
+// replace overwrites current with driver, or creates driver when current is nil.
+func (du driverUsecase) replace(driver *entity.Driver, current *entity.Driver, actor entity.Actor) (bool, error) {
+	if current == nil {
+		current = new(entity.Driver)
 	}
-	if updateDriver.LastName != "" {
-		driver.LastName = updateDriver.LastName
+	if driver.Version != 0 && driver.Version != current.Version {
+		return false, ErrVersionMismatch
 	}
-	if updateDriver.Email != "" {
-		driver.Email = updateDriver.Email
+	driver.Version = current.Version
+	driver.Status = current.Status
+	driver.LicenseNotifiedExpiry = current.LicenseNotifiedExpiry
+
+	driver.NormalizeDocuments()
+	err := driver.Validate()
+	if err != nil {
+		return false, err
 	}
-	if updateDriver.Phone != "" {
-		driver.Phone = updateDriver.Phone
+
+	err = du.checkUniqueFields(driver, *current)
+	if err != nil {
+		return false, err
 	}
-	if updateDriver.License != "" {
-		driver.License = updateDriver.License
+
+	if current.ID != 0 && driver.LicenseType != current.LicenseType {
+		err = du.checkVehicles(driver)
+		if err != nil {
+			return false, err
+		}
 	}
-	if updateDriver.LicenseType != "" {
-		driver.LicenseType = updateDriver.LicenseType
+
+	created, err := du.dRepo.Replace(driver, actor)
+	if err != nil {
+		return false, err
 	}
+	return created, nil
+}
 
-	err = driver.Validate()
+// Delete removes the driver, applying policy to its vehicles: restrict
+// refuses while it has vehicles, unassign keeps them without a driver and
+// cascade removes them too. When version is not zero the driver is only
+// removed if it was not changed since that version.
+func (du driverUsecase) Delete(driverId int, version uint, policy string, actor entity.Actor) error {
+	err := entity.ValidateVehiclePolicy(policy)
 	if err != nil {
 		return err
 	}
+	return du.inTransaction(func(tx driverUsecase) error {
+		_, err := getDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		return tx.dRepo.Delete(driverId, version, policy, actor)
+	})
+}
 
-	err = du.dRepo.Update(driver)
+// Restore undeletes the driver. The vehicles it had when deleted are not
+// restored, as they were unassigned or deleted with it.
+func (du driverUsecase) Restore(driverId int, actor entity.Actor) (*entity.Driver, error) {
+	var driver *entity.Driver
+	err := du.inTransaction(func(tx driverUsecase) error {
+		var err error
+		driver, err = getDeletedDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		return tx.dRepo.Restore(driver, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Purge removes a deleted driver for good. Only admins can purge, and only
+// drivers already deleted, so a purge always follows a delete that can be
+// reviewed. The vehicles of the driver are kept, without a driver.
+func (du driverUsecase) Purge(driverId int, actor entity.Actor) error {
+	if !actor.IsAdmin() {
+		return ErrPurgeNotAllowed
+	}
+	return du.inTransaction(func(tx driverUsecase) error {
+		_, err := getDeletedDriver(tx.dRepo, driverId)
+		if err != nil {
+			return err
+		}
+		return tx.dRepo.Purge(driverId, actor)
+	})
+}
+
+// getOwnedVehicle returns the vehicle only when it is assigned to the driver.
+func (du driverUsecase) getOwnedVehicle(driver *entity.Driver, vehicleId int) (*entity.Vehicle, error) {
+	vehicle, err := getVehicle(du.vRepo, vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	if vehicle.DriverID == nil || *vehicle.DriverID != driver.ID {
+		return nil, ErrVehicleNotOwned
+	}
+	return vehicle, nil
+}
+
+// checkUniqueFields makes sure no other driver uses the same email, CPF,
+// license or external id. Only the fields that differ from current are
+// looked up.
+func (du driverUsecase) checkUniqueFields(driver *entity.Driver, current entity.Driver) error {
+	checks := []struct {
+		field   string
+		value   string
+		current string
+		get     func(string) (*entity.Driver, error)
+	}{
+		{field: "email", value: driver.Email, current: current.Email, get: du.dRepo.GetByEmail},
+		{field: "cpf", value: driver.CPF, current: current.CPF, get: du.dRepo.GetByCPF},
+		{field: "license", value: driver.License, current: current.License, get: du.dRepo.GetByLicense},
+		{field: "externalId", value: externalRef(driver.ExternalSource, driver.ExternalID),
+			current: externalRef(current.ExternalSource, current.ExternalID), get: du.getByExternalRef},
+	}
+	for _, check := range checks {
+		if check.value == check.current || check.value == "" {
+			continue
+		}
+		existing, err := check.get(check.value)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != driver.ID {
+			return &entity.ErrorConflict{Entity: "driver", Field: check.field}
+		}
+	}
+	return nil
+}
+
+func (du driverUsecase) getByExternalRef(ref string) (*entity.Driver, error) {
+	source, externalId, _ := entity.ParseExternalRef(ref)
+	return du.dRepo.GetByExternalId(source, externalId)
+}
+
+// checkVehicles makes sure the driver license still allows driving every
+// vehicle assigned to the driver.
+func (du driverUsecase) checkVehicles(driver *entity.Driver) error {
+	vehicles, err := du.vRepo.GetByDriver(driver.ID)
 	if err != nil {
 		return err
 	}
+	for _, vehicle := range vehicles {
+		// vehicles saved before classes were recorded are checked once they
+		// get a class
+		if vehicle.Class == "" {
+			continue
+		}
+		err = driver.CheckLicense(*vehicle)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (du driverUsecase) Delete(driverId int) error {
+// getDriver returns the driver or ErrDriverNotFound when it does not exist.
+func getDriver(dRepo repository.DriverRepository, driverId int) (*entity.Driver, error) {
 	if driverId <= 0 {
-		return &entity.ErrorInvalidField{
-			Message: []string{"driver id is invalid"},
-		}
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
+	}
+	driver, err := dRepo.GetById(driverId, false)
+	if err != nil {
+		return nil, err
 	}
-	err := du.dRepo.Delete(driverId)
+	if driver == nil {
+		return nil, ErrDriverNotFound
+	}
+	return driver, nil
+}
+
+// missingDriver returns the error of a driver id that was not found: the
+// driver may have been deleted, or never existed.
+func missingDriver(dRepo repository.DriverRepository, driverId int) error {
+	driver, err := dRepo.GetDeletedById(driverId)
 	if err != nil {
 		return err
 	}
-	return nil
+	if driver != nil {
+		return ErrRecordDeleted
+	}
+	return ErrDriverNotFound
+}
+
+// getDeletedDriver returns the deleted driver, ErrRecordNotDeleted when it
+// is not deleted or ErrDriverNotFound when it does not exist.
+func getDeletedDriver(dRepo repository.DriverRepository, driverId int) (*entity.Driver, error) {
+	if driverId <= 0 {
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, driverId, i18n.DriverIdInvalid)
+	}
+	driver, err := dRepo.GetDeletedById(driverId)
+	if err != nil {
+		return nil, err
+	}
+	if driver != nil {
+		return driver, nil
+	}
+	_, err = getDriver(dRepo, driverId)
+	if err != nil {
+		return nil, err
+	}
+	return nil, ErrRecordNotDeleted
 }
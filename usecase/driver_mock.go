@@ -6,6 +6,7 @@ package usecase
 
 import (
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/lucas-moura1/gobrax-challenge/entity"
 	gomock "go.uber.org/mock/gomock"
@@ -35,60 +36,118 @@ func (m *MockDriverUsecase) EXPECT() *MockDriverUsecaseMockRecorder {
 }
 
 // AddVehicle mocks base method.
-func (m *MockDriverUsecase) AddVehicle(driverId int, vehicle *entity.Vehicle) error {
+func (m *MockDriverUsecase) AddVehicle(driverId int, vehicle *entity.Vehicle, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddVehicle", driverId, vehicle)
+	ret := m.ctrl.Call(m, "AddVehicle", driverId, vehicle, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddVehicle indicates an expected call of AddVehicle.
-func (mr *MockDriverUsecaseMockRecorder) AddVehicle(driverId, vehicle interface{}) *gomock.Call {
+func (mr *MockDriverUsecaseMockRecorder) AddVehicle(driverId, vehicle, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVehicle", reflect.TypeOf((*MockDriverUsecase)(nil).AddVehicle), driverId, vehicle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVehicle", reflect.TypeOf((*MockDriverUsecase)(nil).AddVehicle), driverId, vehicle, actor)
+}
+
+// AttachVehicle mocks base method.
+func (m *MockDriverUsecase) AttachVehicle(driverId, vehicleId int, version uint, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachVehicle", driverId, vehicleId, version, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachVehicle indicates an expected call of AttachVehicle.
+func (mr *MockDriverUsecaseMockRecorder) AttachVehicle(driverId, vehicleId, version, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachVehicle", reflect.TypeOf((*MockDriverUsecase)(nil).AttachVehicle), driverId, vehicleId, version, actor)
 }
 
 // Create mocks base method.
-func (m *MockDriverUsecase) Create(driver *entity.Driver) error {
+func (m *MockDriverUsecase) Create(driver *entity.Driver, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", driver)
+	ret := m.ctrl.Call(m, "Create", driver, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockDriverUsecaseMockRecorder) Create(driver interface{}) *gomock.Call {
+func (mr *MockDriverUsecaseMockRecorder) Create(driver, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDriverUsecase)(nil).Create), driver)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDriverUsecase)(nil).Create), driver, actor)
 }
 
 // Delete mocks base method.
-func (m *MockDriverUsecase) Delete(driverId int) error {
+func (m *MockDriverUsecase) Delete(driverId int, version uint, policy string, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", driverId)
+	ret := m.ctrl.Call(m, "Delete", driverId, version, policy, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockDriverUsecaseMockRecorder) Delete(driverId interface{}) *gomock.Call {
+func (mr *MockDriverUsecaseMockRecorder) Delete(driverId, version, policy, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverUsecase)(nil).Delete), driverId, version, policy, actor)
+}
+
+// DetachVehicle mocks base method.
+func (m *MockDriverUsecase) DetachVehicle(driverId, vehicleId int, version uint, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachVehicle", driverId, vehicleId, version, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachVehicle indicates an expected call of DetachVehicle.
+func (mr *MockDriverUsecaseMockRecorder) DetachVehicle(driverId, vehicleId, version, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachVehicle", reflect.TypeOf((*MockDriverUsecase)(nil).DetachVehicle), driverId, vehicleId, version, actor)
+}
+
+// Export mocks base method.
+func (m *MockDriverUsecase) Export(opts *entity.QueryOptions, write func([]*entity.Driver) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", opts, write)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockDriverUsecaseMockRecorder) Export(opts, write interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDriverUsecase)(nil).Delete), driverId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockDriverUsecase)(nil).Export), opts, write)
 }
 
 // GetAll mocks base method.
-func (m *MockDriverUsecase) GetAll() ([]*entity.Driver, error) {
+func (m *MockDriverUsecase) GetAll(opts *entity.QueryOptions) ([]*entity.Driver, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", opts)
 	ret0, _ := ret[0].([]*entity.Driver)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDriverUsecaseMockRecorder) GetAll(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDriverUsecase)(nil).GetAll), opts)
+}
+
+// GetByExternalId mocks base method.
+func (m *MockDriverUsecase) GetByExternalId(source, externalId string) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalId", source, externalId)
+	ret0, _ := ret[0].(*entity.Driver)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockDriverUsecaseMockRecorder) GetAll() *gomock.Call {
+// GetByExternalId indicates an expected call of GetByExternalId.
+func (mr *MockDriverUsecaseMockRecorder) GetByExternalId(source, externalId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDriverUsecase)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalId", reflect.TypeOf((*MockDriverUsecase)(nil).GetByExternalId), source, externalId)
 }
 
 // GetById mocks base method.
@@ -106,16 +165,119 @@ func (mr *MockDriverUsecaseMockRecorder) GetById(driverId, includeVehicle interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockDriverUsecase)(nil).GetById), driverId, includeVehicle)
 }
 
+// GetExpiring mocks base method.
+func (m *MockDriverUsecase) GetExpiring(within time.Duration) ([]*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiring", within)
+	ret0, _ := ret[0].([]*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiring indicates an expected call of GetExpiring.
+func (mr *MockDriverUsecaseMockRecorder) GetExpiring(within interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiring", reflect.TypeOf((*MockDriverUsecase)(nil).GetExpiring), within)
+}
+
+// ImportDrivers mocks base method.
+func (m *MockDriverUsecase) ImportDrivers(rows []entity.DriverImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportDrivers", rows, opts, actor)
+	ret0, _ := ret[0].(*entity.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportDrivers indicates an expected call of ImportDrivers.
+func (mr *MockDriverUsecaseMockRecorder) ImportDrivers(rows, opts, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDrivers", reflect.TypeOf((*MockDriverUsecase)(nil).ImportDrivers), rows, opts, actor)
+}
+
+// Purge mocks base method.
+func (m *MockDriverUsecase) Purge(driverId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", driverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockDriverUsecaseMockRecorder) Purge(driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDriverUsecase)(nil).Purge), driverId, actor)
+}
+
+// Replace mocks base method.
+func (m *MockDriverUsecase) Replace(driverId int, driver *entity.Driver, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", driverId, driver, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockDriverUsecaseMockRecorder) Replace(driverId, driver, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockDriverUsecase)(nil).Replace), driverId, driver, actor)
+}
+
+// ReplaceByExternalId mocks base method.
+func (m *MockDriverUsecase) ReplaceByExternalId(source, externalId string, driver *entity.Driver, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceByExternalId", source, externalId, driver, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceByExternalId indicates an expected call of ReplaceByExternalId.
+func (mr *MockDriverUsecaseMockRecorder) ReplaceByExternalId(source, externalId, driver, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceByExternalId", reflect.TypeOf((*MockDriverUsecase)(nil).ReplaceByExternalId), source, externalId, driver, actor)
+}
+
+// Restore mocks base method.
+func (m *MockDriverUsecase) Restore(driverId int, actor entity.Actor) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", driverId, actor)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockDriverUsecaseMockRecorder) Restore(driverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockDriverUsecase)(nil).Restore), driverId, actor)
+}
+
+// TransferVehicle mocks base method.
+func (m *MockDriverUsecase) TransferVehicle(driverId, vehicleId, toDriverId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferVehicle", driverId, vehicleId, toDriverId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferVehicle indicates an expected call of TransferVehicle.
+func (mr *MockDriverUsecaseMockRecorder) TransferVehicle(driverId, vehicleId, toDriverId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferVehicle", reflect.TypeOf((*MockDriverUsecase)(nil).TransferVehicle), driverId, vehicleId, toDriverId, actor)
+}
+
 // Update mocks base method.
-func (m *MockDriverUsecase) Update(driverId int, driver *entity.Driver) error {
+func (m *MockDriverUsecase) Update(driverId int, patch *entity.Patch, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", driverId, driver)
+	ret := m.ctrl.Call(m, "Update", driverId, patch, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockDriverUsecaseMockRecorder) Update(driverId, driver interface{}) *gomock.Call {
+func (mr *MockDriverUsecaseMockRecorder) Update(driverId, patch, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDriverUsecase)(nil).Update), driverId, driver)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDriverUsecase)(nil).Update), driverId, patch, actor)
 }
@@ -3,34 +3,40 @@ package usecase
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/repository"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func Test_driveUsecase_GetAll(t *testing.T) {
 	tests := []struct {
-		name    string
-		setup   func(mockDriveRepo *repository.MockDriverRepository)
-		want    []*entity.Driver
-		wantErr bool
+		name      string
+		opts      *entity.QueryOptions
+		setup     func(mockDriveRepo *repository.MockDriverRepository)
+		want      []*entity.Driver
+		wantTotal int64
+		wantErr   bool
 	}{
 		{
 			name: "Should return all drives",
 			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetAll().Return([]*entity.Driver{
+				mockDriveRepo.EXPECT().GetAll(gomock.Any()).Return([]*entity.Driver{
 					{
 						Name:        "Lucas",
 						LastName:    "Moura",
 						Email:       "lucas@test.com",
 						Phone:       "123456789",
-						License:     "123456",
+						CPF:         "52998224725",
+						License:     "12347261891",
 						LicenseType: "A",
 					},
-				}, nil)
+				}, int64(1), nil)
 			},
 			want: []*entity.Driver{
 				{
@@ -38,16 +44,47 @@ func Test_driveUsecase_GetAll(t *testing.T) {
 					LastName:    "Moura",
 					Email:       "lucas@test.com",
 					Phone:       "123456789",
-					License:     "123456",
+					CPF:         "52998224725",
+					License:     "12347261891",
 					LicenseType: "A",
 				},
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "Should pass query options to repository",
+			opts: &entity.QueryOptions{
+				Page:     2,
+				PageSize: 10,
+				Sort:     []entity.Sort{{Field: "createdAt", Desc: true}},
+			},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     2,
+					PageSize: 10,
+					Sort:     []entity.Sort{{Field: "createdAt", Desc: true}},
+				}).Return(nil, int64(11), nil)
+			},
+			want:      nil,
+			wantTotal: 11,
+			wantErr:   false,
+		},
+		{
+			name: "Should return error for invalid query options",
+			opts: &entity.QueryOptions{
+				Page:     0,
+				PageSize: 1000,
+				Filters:  []entity.Filter{{Field: "unknown", Operator: entity.FilterOperatorEqual, Value: "x"}},
+			},
+			setup:   func(mockDriveRepo *repository.MockDriverRepository) {},
+			want:    nil,
+			wantErr: true,
 		},
 		{
 			name: "Should return error",
 			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetAll().Return(nil, fmt.Errorf("some error occurred"))
+				mockDriveRepo.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), fmt.Errorf("some error occurred"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -60,13 +97,14 @@ func Test_driveUsecase_GetAll(t *testing.T) {
 
 			tt.setup(mockDriveRepo)
 
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
-			got, err := vu.GetAll()
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, total, err := vu.GetAll(tt.opts)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTotal, total)
 		})
 	}
 }
@@ -90,7 +128,8 @@ func Test_driveUsecase_GetById(t *testing.T) {
 					LastName:    "Moura",
 					Email:       "lucas@test.com",
 					Phone:       "123456789",
-					License:     "123456",
+					CPF:         "52998224725",
+					License:     "12347261891",
 					LicenseType: "A",
 				}, nil)
 			},
@@ -99,7 +138,8 @@ func Test_driveUsecase_GetById(t *testing.T) {
 				LastName:    "Moura",
 				Email:       "lucas@test.com",
 				Phone:       "123456789",
-				License:     "123456",
+				CPF:         "52998224725",
+				License:     "12347261891",
 				LicenseType: "A",
 			},
 			wantErr: false,
@@ -114,13 +154,15 @@ func Test_driveUsecase_GetById(t *testing.T) {
 					LastName:    "Doe",
 					Email:       "john@test.com",
 					Phone:       "987654321",
-					License:     "654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
 					LicenseType: "B",
 					Vehicles: []entity.Vehicle{
 						{
 							Brand:        "Toyota",
 							VehicleModel: "Corolla",
 							Year:         2021,
+							Class:        "car",
 							Plate:        "XYZ-9876",
 						},
 					},
@@ -131,13 +173,15 @@ func Test_driveUsecase_GetById(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "987654321",
-				License:     "654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: "B",
 				Vehicles: []entity.Vehicle{
 					{
 						Brand:        "Toyota",
 						VehicleModel: "Corolla",
 						Year:         2021,
+						Class:        "car",
 						Plate:        "XYZ-9876",
 					},
 				},
@@ -171,7 +215,7 @@ func Test_driveUsecase_GetById(t *testing.T) {
 
 			tt.setup(mockDriveRepo)
 
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
 			got, err := vu.GetById(tt.driverId, tt.includeVehicle)
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -183,6 +227,62 @@ func Test_driveUsecase_GetById(t *testing.T) {
 	}
 }
 
+func Test_driveUsecase_GetExpiring(t *testing.T) {
+	tests := []struct {
+		name    string
+		within  time.Duration
+		setup   func(mockDriveRepo *repository.MockDriverRepository)
+		want    []*entity.Driver
+		wantErr bool
+	}{
+		{
+			name:   "Should return drivers with expiring license",
+			within: 30 * 24 * time.Hour,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByLicenseExpiration(gomock.Any()).DoAndReturn(func(until time.Time) ([]*entity.Driver, error) {
+					assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), until, time.Minute)
+					return []*entity.Driver{{Name: "Lucas"}}, nil
+				})
+			},
+			want:    []*entity.Driver{{Name: "Lucas"}},
+			wantErr: false,
+		},
+		{
+			name:    "Should return error for negative duration",
+			within:  -time.Hour,
+			setup:   func(mockDriveRepo *repository.MockDriverRepository) {},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:   "Should return error",
+			within: time.Hour,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByLicenseExpiration(gomock.Any()).Return(nil, fmt.Errorf("some error occurred"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+
+			tt.setup(mockDriveRepo)
+
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, err := vu.GetExpiring(tt.within)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Test_driveUsecase_Create(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -197,21 +297,104 @@ func Test_driveUsecase_Create(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "21987654321",
-				License:     "654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
+				LicenseType: "B",
+			},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12345678026").Return(nil, nil)
+				mockDriveRepo.EXPECT().Create(&entity.Driver{
+					Name:        "John",
+					LastName:    "Doe",
+					Email:       "john@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
+					LicenseType: "B",
+				}, testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should create driver with masked documents stored as digits",
+			driver: &entity.Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "21987654321",
+				CPF:         "529.982.247-25",
+				License:     "123.456.780-26",
 				LicenseType: "B",
 			},
 			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12345678026").Return(nil, nil)
 				mockDriveRepo.EXPECT().Create(&entity.Driver{
 					Name:        "John",
 					LastName:    "Doe",
 					Email:       "john@test.com",
 					Phone:       "21987654321",
-					License:     "654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
 					LicenseType: "B",
-				}).Return(nil)
+				}, testActor).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should return conflict error when cpf already exists",
+			driver: &entity.Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "21987654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
+				LicenseType: "B",
+			},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return conflict error when email already exists",
+			driver: &entity.Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "21987654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
+				LicenseType: "B",
+			},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return conflict error when license already exists",
+			driver: &entity.Driver{
+				Name:        "John",
+				LastName:    "Doe",
+				Email:       "john@test.com",
+				Phone:       "21987654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
+				LicenseType: "B",
+			},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12345678026").Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name:    "Should return error for invalid driver",
 			driver:  nil,
@@ -225,6 +408,7 @@ func Test_driveUsecase_Create(t *testing.T) {
 				LastName:    "",
 				Email:       "",
 				Phone:       "",
+				CPF:         "52998224725",
 				License:     "",
 				LicenseType: "",
 			},
@@ -238,18 +422,23 @@ func Test_driveUsecase_Create(t *testing.T) {
 				LastName:    "Doe",
 				Email:       "john@test.com",
 				Phone:       "21987654321",
-				License:     "654321",
+				CPF:         "52998224725",
+				License:     "12345678026",
 				LicenseType: "B",
 			},
 			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12345678026").Return(nil, nil)
 				mockDriveRepo.EXPECT().Create(&entity.Driver{
 					Name:        "John",
 					LastName:    "Doe",
 					Email:       "john@test.com",
 					Phone:       "21987654321",
-					License:     "654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
 					LicenseType: "B",
-				}).Return(fmt.Errorf("some error occurred"))
+				}, testActor).Return(fmt.Errorf("some error occurred"))
 			},
 			wantErr: true,
 		},
@@ -262,8 +451,8 @@ func Test_driveUsecase_Create(t *testing.T) {
 
 			tt.setup(mockDriveRepo)
 
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
-			err := vu.Create(tt.driver)
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			err := vu.Create(tt.driver, testActor)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -279,29 +468,33 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 		Brand:        "Toyota",
 		VehicleModel: "Corolla",
 		Year:         2021,
+		Class:        "car",
 		Plate:        "XYZ-9876",
 	}
 	tests := []struct {
 		name     string
 		driverId int
 		vehicle  *entity.Vehicle
-		setup    func(mockDriveRepo *repository.MockDriverRepository)
+		setup    func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository)
 		wantErr  bool
 	}{
 		{
 			name:     "Should add vehicle successfully",
 			driverId: 1,
 			vehicle:  mockVehicle,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
 				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Status:      entity.DriverStatusActive,
 					Name:        "Lucas",
 					LastName:    "Moura",
 					Email:       "lucas@test.com",
 					Phone:       "123456789",
-					License:     "123456",
-					LicenseType: "A",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "B",
 				}, nil)
-				mockDriveRepo.EXPECT().AddVehicle(gomock.Any(), mockVehicle).Return(nil)
+				mockVehicleRepo.EXPECT().GetByPlate("XYZ9876").Return(nil, nil)
+				mockDriveRepo.EXPECT().AddVehicle(gomock.Any(), mockVehicle, testActor).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -309,15 +502,17 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 			name:     "Should return error for invalid driver ID",
 			driverId: -1,
 			vehicle:  nil,
-			setup:    func(mockDriveRepo *repository.MockDriverRepository) {},
-			wantErr:  true,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+			},
+			wantErr: true,
 		},
 		{
 			name:     "Should return error for invalid vehicle",
 			driverId: 2,
 			vehicle:  nil,
-			setup:    func(mockDriveRepo *repository.MockDriverRepository) {},
-			wantErr:  true,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+			},
+			wantErr: true,
 		},
 		{
 			name:     "Should return error when vehicle fields are invalid",
@@ -326,16 +521,18 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 				Brand:        "T",
 				VehicleModel: "C",
 				Year:         1885,
+				Class:        "car",
 				Plate:        "XYZ-987",
 			},
-			setup:   func(mockDriveRepo *repository.MockDriverRepository) {},
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+			},
 			wantErr: true,
 		},
 		{
 			name:     "Should return error for driver not found",
 			driverId: 3,
 			vehicle:  mockVehicle,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
 				mockDriveRepo.EXPECT().GetById(3, false).Return(nil, nil)
 			},
 			wantErr: true,
@@ -344,7 +541,7 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 			name:     "Should return error to get driver by id",
 			driverId: 3,
 			vehicle:  mockVehicle,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
 				mockDriveRepo.EXPECT().GetById(3, false).Return(nil, fmt.Errorf("some error occurred"))
 			},
 			wantErr: true,
@@ -353,16 +550,68 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 			name:     "Should return error for repository add vehicle failure",
 			driverId: 4,
 			vehicle:  mockVehicle,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
 				mockDriveRepo.EXPECT().GetById(4, false).Return(&entity.Driver{
+					Status:      entity.DriverStatusActive,
 					Name:        "John",
 					LastName:    "Doe",
 					Email:       "john@test.com",
 					Phone:       "987654321",
-					License:     "654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
 					LicenseType: "B",
 				}, nil)
-				mockDriveRepo.EXPECT().AddVehicle(gomock.Any(), mockVehicle).Return(fmt.Errorf("some error occurred"))
+				mockVehicleRepo.EXPECT().GetByPlate("XYZ9876").Return(nil, nil)
+				mockDriveRepo.EXPECT().AddVehicle(gomock.Any(), mockVehicle, testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when driver license is expired",
+			driverId: 6,
+			vehicle:  mockVehicle,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				expiredAt := time.Now().AddDate(0, 0, -1)
+				mockDriveRepo.EXPECT().GetById(6, false).Return(&entity.Driver{
+					Status:           entity.DriverStatusActive,
+					Name:             "John",
+					LastName:         "Doe",
+					LicenseType:      "B",
+					LicenseExpiresAt: &expiredAt,
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when driver license does not allow the vehicle class",
+			driverId: 7,
+			vehicle:  mockVehicle,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(7, false).Return(&entity.Driver{
+					Status:      entity.DriverStatusActive,
+					Name:        "John",
+					LastName:    "Doe",
+					LicenseType: "A",
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return conflict error when plate already exists",
+			driverId: 5,
+			vehicle:  mockVehicle,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(5, false).Return(&entity.Driver{
+					Status:      entity.DriverStatusActive,
+					Name:        "John",
+					LastName:    "Doe",
+					Email:       "john@test.com",
+					Phone:       "987654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
+					LicenseType: "B",
+				}, nil)
+				mockVehicleRepo.EXPECT().GetByPlate("XYZ9876").Return(&entity.Vehicle{Model: gorm.Model{ID: 9}}, nil)
 			},
 			wantErr: true,
 		},
@@ -372,11 +621,12 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
 
-			tt.setup(mockDriveRepo)
+			tt.setup(mockDriveRepo, mockVehicleRepo)
 
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
-			err := vu.AddVehicle(tt.driverId, tt.vehicle)
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			err := vu.AddVehicle(tt.driverId, tt.vehicle, testActor)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -387,98 +637,124 @@ func Test_driveUsecase_AddVehicle(t *testing.T) {
 	}
 }
 
-func Test_driveUsecase_Update(t *testing.T) {
+func Test_driveUsecase_AddVehicle_unitOfWork(t *testing.T) {
 	tests := []struct {
-		name         string
-		driverId     int
-		updateDriver *entity.Driver
-		setup        func(mockDriveRepo *repository.MockDriverRepository)
-		wantErr      bool
+		name    string
+		setup   func(mockUnitOfWork *repository.MockUnitOfWork, txDriverRepo *repository.MockDriverRepository, txVehicleRepo *repository.MockVehicleRepository)
+		wantErr error
 	}{
 		{
-			name:     "Should update driver successfully",
-			driverId: 1,
-			updateDriver: &entity.Driver{
-				Name:        "Lucas",
-				LastName:    "Moura",
-				Email:       "lucas@test.com",
-				Phone:       "21987654321",
-				License:     "21323232",
-				LicenseType: "B",
+			name: "Should read and write through the repositories of the transaction",
+			setup: func(mockUnitOfWork *repository.MockUnitOfWork, txDriverRepo *repository.MockDriverRepository, txVehicleRepo *repository.MockVehicleRepository) {
+				mockUnitOfWork.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repository.Repositories) error) error {
+					return fn(repository.Repositories{Drivers: txDriverRepo, Vehicles: txVehicleRepo})
+				})
+				txDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				txVehicleRepo.EXPECT().GetByPlate("XYZ9876").Return(nil, nil)
+				txDriverRepo.EXPECT().AddVehicle(gomock.Any(), gomock.Any(), testActor).Return(nil)
 			},
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
-					Name:        "L",
-					LastName:    "M",
-					Email:       "lucas.test@test.com",
-					Phone:       "123456789",
-					License:     "123456",
-					LicenseType: "A",
-				}, nil)
-				mockDriveRepo.EXPECT().Update(gomock.Any()).Return(nil)
+		},
+		{
+			name: "Should hand the error of the operation to the transaction so it is rolled back",
+			setup: func(mockUnitOfWork *repository.MockUnitOfWork, txDriverRepo *repository.MockDriverRepository, txVehicleRepo *repository.MockVehicleRepository) {
+				mockUnitOfWork.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repository.Repositories) error) error {
+					err := fn(repository.Repositories{Drivers: txDriverRepo, Vehicles: txVehicleRepo})
+					assert.Equal(t, ErrDriverNotFound, err)
+					return err
+				})
+				txDriverRepo.EXPECT().GetById(1, false).Return(nil, nil)
+			},
+			wantErr: ErrDriverNotFound,
+		},
+		{
+			name: "Should return error when the transaction fails",
+			setup: func(mockUnitOfWork *repository.MockUnitOfWork, txDriverRepo *repository.MockDriverRepository, txVehicleRepo *repository.MockVehicleRepository) {
+				mockUnitOfWork.EXPECT().Do(gomock.Any()).Return(fmt.Errorf("commit failed"))
+			},
+			wantErr: fmt.Errorf("commit failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockUnitOfWork := repository.NewMockUnitOfWork(ctrl)
+			txDriverRepo := repository.NewMockDriverRepository(ctrl)
+			txVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockUnitOfWork, txDriverRepo, txVehicleRepo)
+
+			// the repositories outside the transaction must not be used
+			du := NewDriverUsecase(zap.NewNop().Sugar(), repository.NewMockDriverRepository(ctrl), repository.NewMockVehicleRepository(ctrl), mockUnitOfWork)
+			err := du.AddVehicle(1, &entity.Vehicle{Brand: "Toyota", VehicleModel: "Corolla", Year: 2021, Class: "car", Plate: "XYZ-9876"}, testActor)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_driveUsecase_AttachVehicle(t *testing.T) {
+	driverId := uint(1)
+	otherDriverId := uint(3)
+	tests := []struct {
+		name    string
+		version uint
+		setup   func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository)
+		want    error
+		wantErr bool
+	}{
+		{
+			name: "Should attach vehicle to driver",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockAssignmentRepo.EXPECT().Assign(vehicle, uint(1), testActor).Return(nil)
 			},
 			wantErr: false,
 		},
 		{
-			name:         "Should return error for invalid driver ID",
-			driverId:     -1,
-			updateDriver: nil,
-			setup:        func(mockDriveRepo *repository.MockDriverRepository) {},
-			wantErr:      true,
+			name: "Should do nothing when vehicle is already attached to the driver",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &driverId}, nil)
+			},
+			wantErr: false,
 		},
 		{
-			name:         "Should return error to get driver by id",
-			driverId:     2,
-			updateDriver: new(entity.Driver),
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetById(2, false).Return(nil, fmt.Errorf("some error occurred"))
+			name: "Should return error when vehicle is attached to another driver",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &otherDriverId}, nil)
 			},
+			want:    ErrVehicleAlreadyAssigned,
 			wantErr: true,
 		},
 		{
-			name:         "Should return error for driver not found",
-			driverId:     2,
-			updateDriver: &entity.Driver{},
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetById(2, false).Return(nil, nil)
+			name: "Should return vehicle not found error",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(nil, nil)
 			},
+			want:    ErrVehicleNotFound,
 			wantErr: true,
 		},
 		{
-			name:     "Should return error for invalid driver fields",
-			driverId: 3,
-			updateDriver: &entity.Driver{
-				Name: "J",
-			},
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetById(3, false).Return(&entity.Driver{
-					Name:        "John",
-					LastName:    "Doe",
-					Email:       "john@test.com",
-					Phone:       "987654321",
-					License:     "654321",
-					LicenseType: "B",
-				}, nil)
+			name:    "Should return error when vehicle version does not match",
+			version: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car", Version: 2}, nil)
 			},
+			want:    ErrVersionMismatch,
 			wantErr: true,
 		},
 		{
-			name:     "Should return error for repository update failure",
-			driverId: 4,
-			updateDriver: &entity.Driver{
-				Name: "John",
-			},
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().GetById(4, false).Return(&entity.Driver{
-					Name:        "Johnn",
-					LastName:    "Doe",
-					Email:       "john@test.com",
-					Phone:       "21987654321",
-					License:     "654321",
-					LicenseType: "B",
-				}, nil)
-				mockDriveRepo.EXPECT().Update(gomock.Any()).Return(fmt.Errorf("some error occurred"))
+			name: "Should return error when vehicle changed concurrently",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable, Class: "car"}, nil)
+				mockAssignmentRepo.EXPECT().Assign(gomock.Any(), uint(1), testActor).Return(repository.ErrVehicleDriverChanged)
 			},
+			want:    ErrVehicleDriverChanged,
 			wantErr: true,
 		},
 	}
@@ -487,48 +763,66 @@ func Test_driveUsecase_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			tt.setup(mockDriveRepo, mockVehicleRepo, mockAssignmentRepo)
 
-			tt.setup(mockDriveRepo)
-
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
-			err := vu.Update(tt.driverId, tt.updateDriver)
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+			err := du.AttachVehicle(1, 2, tt.version, testActor)
 			if tt.wantErr {
-				assert.Error(t, err)
+				assert.Equal(t, tt.want, err)
 				return
 			}
-
 			assert.Nil(t, err)
 		})
 	}
 }
 
-func Test_driveUsecase_Delete(t *testing.T) {
+func Test_driveUsecase_DetachVehicle(t *testing.T) {
+	driverId := uint(1)
+	otherDriverId := uint(3)
 	tests := []struct {
-		name     string
-		driverId int
-		setup    func(mockDriveRepo *repository.MockDriverRepository)
-		wantErr  bool
+		name    string
+		version uint
+		setup   func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository)
+		want    error
+		wantErr bool
 	}{
 		{
-			name:     "Should delete driver successfully",
-			driverId: 1,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().Delete(1).Return(nil)
+			name: "Should detach vehicle from driver",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, DriverID: &driverId}
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockAssignmentRepo.EXPECT().Unassign(vehicle, testActor).Return(nil)
 			},
 			wantErr: false,
 		},
 		{
-			name:     "Should return error for invalid driver ID",
-			driverId: -1,
-			setup:    func(mockDriveRepo *repository.MockDriverRepository) {},
-			wantErr:  true,
+			name: "Should return error when vehicle belongs to another driver",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, DriverID: &otherDriverId}, nil)
+			},
+			want:    ErrVehicleNotOwned,
+			wantErr: true,
 		},
 		{
-			name:     "Should return error for repository delete failure",
-			driverId: 2,
-			setup: func(mockDriveRepo *repository.MockDriverRepository) {
-				mockDriveRepo.EXPECT().Delete(2).Return(fmt.Errorf("some error occurred"))
+			name:    "Should return error when vehicle version does not match",
+			version: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, DriverID: &driverId, Version: 2}, nil)
+			},
+			want:    ErrVersionMismatch,
+			wantErr: true,
+		},
+		{
+			name: "Should return driver not found error",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(nil, nil)
 			},
+			want:    ErrDriverNotFound,
 			wantErr: true,
 		},
 	}
@@ -537,17 +831,913 @@ func Test_driveUsecase_Delete(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			tt.setup(mockDriveRepo, mockVehicleRepo, mockAssignmentRepo)
 
-			tt.setup(mockDriveRepo)
-
-			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo)
-			err := vu.Delete(tt.driverId)
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+			err := du.DetachVehicle(1, 2, tt.version, testActor)
 			if tt.wantErr {
-				assert.Error(t, err)
+				assert.Equal(t, tt.want, err)
 				return
 			}
-
 			assert.Nil(t, err)
 		})
 	}
 }
+
+func Test_driveUsecase_TransferVehicle(t *testing.T) {
+	driverId := uint(1)
+	tests := []struct {
+		name       string
+		toDriverId int
+		setup      func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository)
+		want       error
+		wantErr    bool
+	}{
+		{
+			name:       "Should transfer vehicle to another driver",
+			toDriverId: 3,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, Class: "heavy_truck", DriverID: &driverId}
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "C"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockDriveRepo.EXPECT().GetById(3, false).Return(&entity.Driver{Model: gorm.Model{ID: 3}, Status: entity.DriverStatusActive, LicenseType: "CE"}, nil)
+				mockAssignmentRepo.EXPECT().Assign(vehicle, uint(3), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:       "Should return error when transferring to the same driver",
+			toDriverId: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+			},
+			want:    entity.NewErrorInvalidField("driverId", entity.CodeInvalid, 1, i18n.VehicleAlreadyAssignedToDriver),
+			wantErr: true,
+		},
+		{
+			name:       "Should return error when new driver license does not allow the vehicle class",
+			toDriverId: 3,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "C"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, Class: "heavy_truck", Plate: "ABC1234", DriverID: &driverId}, nil)
+				mockDriveRepo.EXPECT().GetById(3, false).Return(&entity.Driver{Model: gorm.Model{ID: 3}, Status: entity.DriverStatusActive, LicenseType: "B"}, nil)
+			},
+			want: &entity.ErrorIncompatibleLicense{
+				LicenseType:  "B",
+				VehicleClass: "heavy_truck",
+				Plate:        "ABC1234",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "Should return driver not found error for the new driver",
+			toDriverId: 3,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository, mockAssignmentRepo *repository.MockAssignmentRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, LicenseType: "C"}, nil)
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &driverId}, nil)
+				mockDriveRepo.EXPECT().GetById(3, false).Return(nil, nil)
+			},
+			want:    ErrDriverNotFound,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockAssignmentRepo := repository.NewMockAssignmentRepository(ctrl)
+			tt.setup(mockDriveRepo, mockVehicleRepo, mockAssignmentRepo)
+
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo, Assignments: mockAssignmentRepo}))
+			err := du.TransferVehicle(1, 2, tt.toDriverId, testActor)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_driveUsecase_Update(t *testing.T) {
+	tests := []struct {
+		name     string
+		driverId int
+		patch    *entity.Patch
+		setup    func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		wantErr  bool
+	}{
+		{
+			name:     "Should update driver successfully",
+			driverId: 1,
+			patch: mergePatch(`{"name":"Lucas","lastName":"Moura","email":"lucas@test.com","phone":"21987654321",` +
+				`"cpf":"52998224725","license":"12346469974","licenseType":"B"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Name:        "L",
+					LastName:    "M",
+					Email:       "lucas.test@test.com",
+					Phone:       "123456789",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "A",
+				}, nil)
+				mockDriveRepo.EXPECT().GetByEmail("lucas@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12346469974").Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(0)).Return([]*entity.Vehicle{{Class: entity.VehicleClassCar}}, nil)
+				mockDriveRepo.EXPECT().Update(gomock.Any(), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Should return error when driver version does not match",
+			driverId: 1,
+			patch:    &entity.Patch{Type: entity.PatchTypeMerge, Document: []byte(`{"name":"Lucas"}`), Version: 1},
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Name: "L", Version: 2}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when new license type does not allow driver vehicles",
+			driverId: 1,
+			patch:    mergePatch(`{"licenseType":"A"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Model:       gorm.Model{ID: 1},
+					Name:        "Lucas",
+					LastName:    "Moura",
+					Email:       "lucas@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "CE",
+				}, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return([]*entity.Vehicle{
+					{Plate: "ABC1234", Class: entity.VehicleClassHeavyTruck},
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should update license type when driver vehicle has no class",
+			driverId: 1,
+			patch:    mergePatch(`{"licenseType":"A"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Model:       gorm.Model{ID: 1},
+					Name:        "Lucas",
+					LastName:    "Moura",
+					Email:       "lucas@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "CE",
+				}, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return([]*entity.Vehicle{{Plate: "ABC1234"}}, nil)
+				mockDriveRepo.EXPECT().Update(gomock.Any(), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Should return conflict error when email belongs to another driver",
+			driverId: 1,
+			patch:    mergePatch(`{"email":"john@test.com"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Model:       gorm.Model{ID: 1},
+					Name:        "Lucas",
+					LastName:    "Moura",
+					Email:       "lucas@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "A",
+				}, nil)
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return conflict error when external id belongs to another driver",
+			driverId: 1,
+			patch:    mergePatch(`{"externalSource":"hr","externalId":"E-2"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Model:       gorm.Model{ID: 1},
+					Name:        "Lucas",
+					LastName:    "Moura",
+					Email:       "lucas@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12347261891",
+					LicenseType: "A",
+				}, nil)
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-2").Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for invalid driver ID",
+			driverId: -1,
+			patch:    nil,
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error to get driver by id",
+			driverId: 2,
+			patch:    mergePatch(`{}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(2, false).Return(nil, fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for driver not found",
+			driverId: 2,
+			patch:    mergePatch(`{}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(2, false).Return(nil, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for invalid driver fields",
+			driverId: 3,
+			patch:    mergePatch(`{"name":"J"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(3, false).Return(&entity.Driver{
+					Name:        "John",
+					LastName:    "Doe",
+					Email:       "john@test.com",
+					Phone:       "987654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
+					LicenseType: "B",
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for repository update failure",
+			driverId: 4,
+			patch:    mergePatch(`{"name":"John"}`),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(4, false).Return(&entity.Driver{
+					Name:        "Johnn",
+					LastName:    "Doe",
+					Email:       "john@test.com",
+					Phone:       "21987654321",
+					CPF:         "52998224725",
+					License:     "12345678026",
+					LicenseType: "B",
+				}, nil)
+				mockDriveRepo.EXPECT().Update(gomock.Any(), testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+
+			tt.setup(mockDriveRepo, mockVehicleRepo)
+
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			err := vu.Update(tt.driverId, tt.patch, testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_driveUsecase_Replace(t *testing.T) {
+	newDriver := func() *entity.Driver {
+		return &entity.Driver{
+			Name:        "Lucas",
+			LastName:    "Moura",
+			Email:       "lucas@test.com",
+			Phone:       "21987654321",
+			CPF:         "529.982.247-25",
+			License:     "12346469974",
+			LicenseType: "B",
+		}
+	}
+	tests := []struct {
+		name        string
+		driverId    int
+		driver      *entity.Driver
+		setup       func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		want        error
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name:     "Should return driver not found error when the driver does not exist",
+			driverId: 10,
+			driver:   newDriver(),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(10, false).Return(nil, nil)
+				mockDriveRepo.EXPECT().GetDeletedById(10).Return(nil, nil)
+			},
+			want:    ErrDriverNotFound,
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when the id belongs to a deleted driver",
+			driverId: 10,
+			driver:   newDriver(),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(10, false).Return(nil, nil)
+				mockDriveRepo.EXPECT().GetDeletedById(10).Return(&entity.Driver{Model: gorm.Model{ID: 10}}, nil)
+			},
+			want:    ErrRecordDeleted,
+			wantErr: true,
+		},
+		{
+			name:     "Should replace every field of an existing driver",
+			driverId: 1,
+			driver:   newDriver(),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{
+					Model:            gorm.Model{ID: 1},
+					Name:             "L",
+					Email:            "lucas@test.com",
+					CPF:              "52998224725",
+					License:          "12346469974",
+					LicenseType:      "CE",
+					LicenseExpiresAt: &time.Time{},
+					Version:          3,
+				}, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return([]*entity.Vehicle{{Class: entity.VehicleClassCar}}, nil)
+				mockDriveRepo.EXPECT().Replace(gomock.Any(), testActor).DoAndReturn(func(driver *entity.Driver, _ entity.Actor) (bool, error) {
+					assert.Equal(t, uint(3), driver.Version)
+					assert.Nil(t, driver.LicenseExpiresAt)
+					return false, nil
+				})
+			},
+			wantCreated: false,
+			wantErr:     false,
+		},
+		{
+			name:     "Should return error when driver version does not match",
+			driverId: 1,
+			driver:   &entity.Driver{Name: "Lucas", Version: 1},
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Name: "L", Version: 2}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for invalid driver fields",
+			driverId: 1,
+			driver:   &entity.Driver{Name: "Lucas"},
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(newDriver(), nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for invalid driver ID",
+			driverId: 0,
+			driver:   newDriver(),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for repository replace failure",
+			driverId: 1,
+			driver:   newDriver(),
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				current := newDriver()
+				current.ID = 1
+				current.CPF = "52998224725"
+				mockDriveRepo.EXPECT().GetById(1, false).Return(current, nil)
+				mockDriveRepo.EXPECT().Replace(gomock.Any(), testActor).Return(false, ErrRecordDeleted)
+			},
+			want:    ErrRecordDeleted,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+
+			tt.setup(mockDriveRepo, mockVehicleRepo)
+
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			created, err := du.Replace(tt.driverId, tt.driver, testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.want != nil {
+					assert.Equal(t, tt.want, err)
+				}
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
+func Test_driveUsecase_GetByExternalId(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		externalId string
+		setup      func(mockDriveRepo *repository.MockDriverRepository)
+		want       *entity.Driver
+		wantErr    bool
+	}{
+		{
+			name:       "Should return the driver with the external id",
+			source:     "hr",
+			externalId: "E-1",
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-1").Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+			},
+			want: &entity.Driver{Model: gorm.Model{ID: 1}},
+		},
+		{
+			name:       "Should return nil when no driver has the external id",
+			source:     "hr",
+			externalId: "E-2",
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-2").Return(nil, nil)
+			},
+			want: nil,
+		},
+		{
+			name:       "Should return error for an invalid source",
+			source:     "h r",
+			externalId: "E-1",
+			setup:      func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockDriveRepo)
+
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, err := du.GetByExternalId(tt.source, tt.externalId)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_driveUsecase_ReplaceByExternalId(t *testing.T) {
+	newDriver := func() *entity.Driver {
+		return &entity.Driver{
+			Name:        "Lucas",
+			LastName:    "Moura",
+			Email:       "lucas@test.com",
+			Phone:       "21987654321",
+			CPF:         "52998224725",
+			License:     "12346469974",
+			LicenseType: "B",
+		}
+	}
+	source, externalId := "hr", "E-1"
+	tests := []struct {
+		name        string
+		source      string
+		externalId  string
+		setup       func(mockDriveRepo *repository.MockDriverRepository)
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name:       "Should create the driver with the external id when it does not exist",
+			source:     "hr",
+			externalId: "E-1",
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-1").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByEmail("lucas@test.com").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByCPF("52998224725").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByLicense("12346469974").Return(nil, nil)
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-1").Return(nil, nil)
+				mockDriveRepo.EXPECT().Replace(gomock.Any(), testActor).DoAndReturn(func(driver *entity.Driver, _ entity.Actor) (bool, error) {
+					assert.Equal(t, uint(0), driver.ID)
+					assert.Equal(t, "hr", *driver.ExternalSource)
+					assert.Equal(t, "E-1", *driver.ExternalID)
+					return true, nil
+				})
+			},
+			wantCreated: true,
+		},
+		{
+			name:       "Should replace the driver with the external id",
+			source:     "hr",
+			externalId: "E-1",
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				current := newDriver()
+				current.ID = 7
+				current.ExternalSource = &source
+				current.ExternalID = &externalId
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-1").Return(current, nil)
+				mockDriveRepo.EXPECT().Replace(gomock.Any(), testActor).DoAndReturn(func(driver *entity.Driver, _ entity.Actor) (bool, error) {
+					assert.Equal(t, uint(7), driver.ID)
+					return false, nil
+				})
+			},
+			wantCreated: false,
+		},
+		{
+			name:       "Should return error for an invalid external id",
+			source:     "hr",
+			externalId: " ",
+			setup:      func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr:    true,
+		},
+		{
+			name:       "Should return error to get driver by external id",
+			source:     "hr",
+			externalId: "E-1",
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByExternalId("hr", "E-1").Return(nil, fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockDriveRepo)
+
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			created, err := du.ReplaceByExternalId(tt.source, tt.externalId, newDriver(), testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
+func Test_driveUsecase_Delete(t *testing.T) {
+	tests := []struct {
+		name     string
+		driverId int
+		policy   string
+		setup    func(mockDriveRepo *repository.MockDriverRepository)
+		wantErr  bool
+	}{
+		{
+			name:     "Should delete driver successfully",
+			driverId: 1,
+			policy:   entity.VehiclePolicyRestrict,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockDriveRepo.EXPECT().Delete(1, uint(0), entity.VehiclePolicyRestrict, testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Should delete driver with its vehicles",
+			driverId: 1,
+			policy:   entity.VehiclePolicyCascade,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockDriveRepo.EXPECT().Delete(1, uint(0), entity.VehiclePolicyCascade, testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Should return error for invalid driver ID",
+			driverId: -1,
+			policy:   entity.VehiclePolicyRestrict,
+			setup:    func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr:  true,
+		},
+		{
+			name:     "Should return error for unknown vehicle policy",
+			driverId: 1,
+			policy:   "orphan",
+			setup:    func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr:  true,
+		},
+		{
+			name:     "Should return not found when the driver does not exist",
+			driverId: 3,
+			policy:   entity.VehiclePolicyRestrict,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetById(3, false).Return(nil, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error when the driver has vehicles",
+			driverId: 2,
+			policy:   entity.VehiclePolicyRestrict,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetById(2, false).Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+				mockDriveRepo.EXPECT().Delete(2, uint(0), entity.VehiclePolicyRestrict, testActor).Return(ErrDriverHasVehicles)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "Should return error for repository delete failure",
+			driverId: 2,
+			policy:   entity.VehiclePolicyUnassign,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetById(2, false).Return(&entity.Driver{Model: gorm.Model{ID: 2}}, nil)
+				mockDriveRepo.EXPECT().Delete(2, uint(0), entity.VehiclePolicyUnassign, testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+
+			tt.setup(mockDriveRepo)
+
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			vu := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			err := vu.Delete(tt.driverId, 0, tt.policy, testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_driveUsecase_ImportDrivers(t *testing.T) {
+	newRows := func() []entity.DriverImportRow {
+		return []entity.DriverImportRow{
+			{Line: 2, Driver: &entity.Driver{Name: "John", LastName: "Doe", Email: "john@test.com", Phone: "21987654321",
+				CPF: "529.982.247-25", License: "12345678026", LicenseType: "B"}},
+			{Line: 3, Driver: &entity.Driver{Name: "Mary", LastName: "Jane", Email: "mary@test.com", Phone: "21987654322",
+				CPF: "11144477735", License: "12347261891", LicenseType: "C"}},
+		}
+	}
+	noConflicts := func(mockDriveRepo *repository.MockDriverRepository) {
+		mockDriveRepo.EXPECT().GetByEmail(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockDriveRepo.EXPECT().GetByCPF(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockDriveRepo.EXPECT().GetByLicense(gomock.Any()).Return(nil, nil).AnyTimes()
+	}
+
+	tests := []struct {
+		name    string
+		rows    func() []entity.DriverImportRow
+		opts    *entity.ImportOptions
+		setup   func(mockDriveRepo *repository.MockDriverRepository)
+		want    *entity.ImportReport
+		wantErr bool
+	}{
+		{
+			name: "Should create every driver in a single batch",
+			rows: newRows,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				noConflicts(mockDriveRepo)
+				mockDriveRepo.EXPECT().CreateBatch(gomock.Len(2), testActor).DoAndReturn(func(drivers []*entity.Driver, _ entity.Actor) error {
+					assert.Equal(t, "52998224725", drivers[0].CPF)
+					return nil
+				})
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, Total: 2, Valid: 2, Imported: 2},
+		},
+		{
+			name: "Should create no driver when a row is invalid",
+			rows: func() []entity.DriverImportRow {
+				rows := newRows()
+				rows[1].Driver.LicenseType = "Z"
+				return rows
+			},
+			setup: noConflicts,
+			want: &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, Total: 2, Valid: 1, Errors: []entity.ImportRowError{
+				{Line: 3, Err: entity.NewErrorInvalidField("licenseType", entity.CodeInvalid, "Z", i18n.DriverLicenseTypeInvalid)},
+			}},
+		},
+		{
+			name:  "Should only validate the drivers on a dry run",
+			rows:  newRows,
+			opts:  &entity.ImportOptions{Mode: entity.ImportModeAllOrNothing, DryRun: true},
+			setup: noConflicts,
+			want:  &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, DryRun: true, Total: 2, Valid: 2},
+		},
+		{
+			name: "Should create the valid drivers on a best effort import",
+			rows: func() []entity.DriverImportRow {
+				rows := newRows()
+				rows[1].Driver.Email = "john@test.com"
+				return rows
+			},
+			opts: &entity.ImportOptions{Mode: entity.ImportModeBestEffort},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				noConflicts(mockDriveRepo)
+				mockDriveRepo.EXPECT().Create(gomock.Any(), testActor).Return(nil)
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeBestEffort, Total: 2, Valid: 1, Imported: 1, Errors: []entity.ImportRowError{
+				{Line: 3, Err: entity.NewErrorInvalidField("email", entity.CodeDuplicate, "john@test.com", i18n.ImportDuplicate, "email", 2)},
+			}},
+		},
+		{
+			name: "Should report a driver that already exists",
+			rows: newRows,
+			opts: &entity.ImportOptions{Mode: entity.ImportModeBestEffort},
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetByEmail("john@test.com").Return(&entity.Driver{Model: gorm.Model{ID: 7}}, nil)
+				noConflicts(mockDriveRepo)
+				mockDriveRepo.EXPECT().Create(gomock.Any(), testActor).Return(nil)
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeBestEffort, Total: 2, Valid: 1, Imported: 1, Errors: []entity.ImportRowError{
+				{Line: 2, Err: &entity.ErrorConflict{Entity: "driver", Field: "email"}},
+			}},
+		},
+		{
+			name: "Should report the dates that could not be read with the other violations",
+			rows: func() []entity.DriverImportRow {
+				rows := newRows()
+				rows[0].Driver.Name = ""
+				rows[0].Err = entity.NewErrorInvalidField("licenseExpiresAt", entity.CodeInvalidFormat, "15/01/2030", i18n.MustBeDate, "licenseExpiresAt")
+				return rows[:1]
+			},
+			setup: noConflicts,
+			want: &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, Total: 1, Errors: []entity.ImportRowError{
+				{Line: 2, Err: &entity.ErrorInvalidField{Errors: []entity.FieldError{
+					entity.NewErrorInvalidField("licenseExpiresAt", entity.CodeInvalidFormat, "15/01/2030", i18n.MustBeDate, "licenseExpiresAt").Errors[0],
+					entity.NewErrorInvalidField("name", entity.CodeRequired, nil, i18n.DriverNameInvalid).Errors[0],
+				}}},
+			}},
+		},
+		{
+			name:    "Should return error for an unknown mode",
+			rows:    newRows,
+			opts:    &entity.ImportOptions{Mode: "some"},
+			setup:   func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr: true,
+		},
+		{
+			name: "Should return error when the batch fails",
+			rows: newRows,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				noConflicts(mockDriveRepo)
+				mockDriveRepo.EXPECT().CreateBatch(gomock.Any(), testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockDriveRepo)
+
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, repository.NewMockVehicleRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, err := du.ImportDrivers(tt.rows(), tt.opts, testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_driveUsecase_Restore(t *testing.T) {
+	deleted := &entity.Driver{Model: gorm.Model{ID: 1}, Name: "John", Version: 2}
+	tests := []struct {
+		name     string
+		driverId int
+		setup    func(mockDriveRepo *repository.MockDriverRepository)
+		want     *entity.Driver
+		wantErr  error
+	}{
+		{
+			name:     "Should restore the deleted driver",
+			driverId: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(deleted, nil)
+				mockDriveRepo.EXPECT().Restore(deleted, testActor).Return(nil)
+			},
+			want: deleted,
+		},
+		{
+			name:     "Should return conflict when the driver is not deleted",
+			driverId: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+			},
+			wantErr: ErrRecordNotDeleted,
+		},
+		{
+			name:     "Should return not found when the driver never existed",
+			driverId: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockDriveRepo.EXPECT().GetById(1, false).Return(nil, nil)
+			},
+			wantErr: ErrDriverNotFound,
+		},
+		{
+			name:     "Should return the error of a concurrent restore",
+			driverId: 1,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(deleted, nil)
+				mockDriveRepo.EXPECT().Restore(deleted, testActor).Return(ErrRecordNotDeleted)
+			},
+			wantErr: ErrRecordNotDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockDriveRepo)
+
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			got, err := du.Restore(tt.driverId, testActor)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_driveUsecase_Purge(t *testing.T) {
+	admin := entity.NewActor("root", entity.RoleAdmin)
+	tests := []struct {
+		name    string
+		actor   entity.Actor
+		setup   func(mockDriveRepo *repository.MockDriverRepository)
+		wantErr error
+	}{
+		{
+			name:  "Should purge the deleted driver",
+			actor: admin,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+				mockDriveRepo.EXPECT().Purge(1, admin).Return(nil)
+			},
+		},
+		{
+			name:    "Should refuse to purge when the actor is not an admin",
+			actor:   testActor,
+			setup:   func(mockDriveRepo *repository.MockDriverRepository) {},
+			wantErr: ErrPurgeNotAllowed,
+		},
+		{
+			name:  "Should refuse to purge a driver that is not deleted",
+			actor: admin,
+			setup: func(mockDriveRepo *repository.MockDriverRepository) {
+				mockDriveRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockDriveRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}}, nil)
+			},
+			wantErr: ErrRecordNotDeleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			tt.setup(mockDriveRepo)
+
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			du := NewDriverUsecase(zap.NewNop().Sugar(), mockDriveRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriveRepo, Vehicles: mockVehicleRepo}))
+			err := du.Purge(1, tt.actor)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// exportOptions validates the filters of an export. Exports are read in
+// batches following the ids, so they can not be sorted by other fields.
+func exportOptions(opts *entity.QueryOptions, fields map[string]string) (*entity.QueryOptions, error) {
+	if opts == nil {
+		opts = entity.NewQueryOptions()
+	}
+	err := opts.Validate(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.Sort) > 0 {
+		return nil, entity.NewErrorInvalidField("sort", entity.CodeNotAllowed, nil, i18n.ExportSortNotAllowed)
+	}
+	return opts, nil
+}
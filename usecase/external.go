@@ -0,0 +1,10 @@
+package usecase
+
+// externalRef formats an external source and id as source:id, or returns an
+// empty string for a record without an external id.
+func externalRef(source *string, id *string) string {
+	if source == nil || id == nil {
+		return ""
+	}
+	return *source + ":" + *id
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+)
+
+var (
+	ErrIdempotencyKeyReused     = domainerr.New(domainerr.Unprocessable, i18n.IdempotencyKeyReused)
+	ErrIdempotencyKeyInProgress = domainerr.New(domainerr.Conflict, i18n.IdempotencyKeyInProgress)
+)
+
+const maxIdempotencyKeySize = 255
+
+type IdempotencyUsecase interface {
+	Begin(key string, fingerprint string) (*entity.IdempotencyKey, error)
+	Complete(key *entity.IdempotencyKey) error
+	Abort(key string) error
+}
+
+type idempotencyUsecase struct {
+	iRepo repository.IdempotencyRepository
+	ttl   time.Duration
+}
+
+// NewIdempotencyUsecase creates the usecase, keys older than ttl are
+// forgotten and may be reused for a new request.
+func NewIdempotencyUsecase(iRepo repository.IdempotencyRepository, ttl time.Duration) *idempotencyUsecase {
+	return &idempotencyUsecase{iRepo: iRepo, ttl: ttl}
+}
+
+// Begin reserves the key for a request with the given fingerprint. It returns
+// nil when the request must be executed, or the stored key when the request
+// was already executed and its response must be replayed.
+func (iu idempotencyUsecase) Begin(key string, fingerprint string) (*entity.IdempotencyKey, error) {
+	if key == "" || len(key) > maxIdempotencyKeySize {
+		return nil, entity.NewErrorInvalidField("Idempotency-Key", entity.CodeInvalidLength, nil, i18n.IdempotencyKeyInvalid)
+	}
+
+	reserved, err := iu.iRepo.Reserve(&entity.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := iu.iRepo.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		// the first request failed and released the key meanwhile
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	if existing.Expired(time.Now(), iu.ttl) {
+		err = iu.iRepo.Delete(key)
+		if err != nil {
+			return nil, err
+		}
+		return iu.Begin(key, fingerprint)
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// Complete stores the response of the request that reserved the key.
+func (iu idempotencyUsecase) Complete(key *entity.IdempotencyKey) error {
+	return iu.iRepo.Complete(key)
+}
+
+// Abort releases the key so the request can be retried, used when the
+// request failed without changing anything.
+func (iu idempotencyUsecase) Abort(key string) error {
+	return iu.iRepo.Delete(key)
+}
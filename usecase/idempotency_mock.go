@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/idempotency.go
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	reflect "reflect"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdempotencyUsecase is a mock of IdempotencyUsecase interface.
+type MockIdempotencyUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyUsecaseMockRecorder
+}
+
+// MockIdempotencyUsecaseMockRecorder is the mock recorder for MockIdempotencyUsecase.
+type MockIdempotencyUsecaseMockRecorder struct {
+	mock *MockIdempotencyUsecase
+}
+
+// NewMockIdempotencyUsecase creates a new mock instance.
+func NewMockIdempotencyUsecase(ctrl *gomock.Controller) *MockIdempotencyUsecase {
+	mock := &MockIdempotencyUsecase{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyUsecase) EXPECT() *MockIdempotencyUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method.
+func (m *MockIdempotencyUsecase) Abort(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Abort indicates an expected call of Abort.
+func (mr *MockIdempotencyUsecaseMockRecorder) Abort(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockIdempotencyUsecase)(nil).Abort), key)
+}
+
+// Begin mocks base method.
+func (m *MockIdempotencyUsecase) Begin(key, fingerprint string) (*entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", key, fingerprint)
+	ret0, _ := ret[0].(*entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIdempotencyUsecaseMockRecorder) Begin(key, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIdempotencyUsecase)(nil).Begin), key, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockIdempotencyUsecase) Complete(key *entity.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIdempotencyUsecaseMockRecorder) Complete(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIdempotencyUsecase)(nil).Complete), key)
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_idempotencyUsecase_Begin(t *testing.T) {
+	completed := &entity.IdempotencyKey{
+		Key:         "abc",
+		Fingerprint: "f1",
+		StatusCode:  201,
+		CreatedAt:   time.Now(),
+	}
+	tests := []struct {
+		name    string
+		key     string
+		setup   func(mockIdempotencyRepo *repository.MockIdempotencyRepository)
+		want    *entity.IdempotencyKey
+		wantErr error
+	}{
+		{
+			name: "Should reserve a new key",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(true, nil)
+			},
+			want: nil,
+		},
+		{
+			name: "Should return stored response for a retry",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(false, nil)
+				mockIdempotencyRepo.EXPECT().GetByKey("abc").Return(completed, nil)
+			},
+			want: completed,
+		},
+		{
+			name: "Should return error when key is reused with another request",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(false, nil)
+				mockIdempotencyRepo.EXPECT().GetByKey("abc").Return(&entity.IdempotencyKey{
+					Key:         "abc",
+					Fingerprint: "f2",
+					StatusCode:  201,
+					CreatedAt:   time.Now(),
+				}, nil)
+			},
+			wantErr: ErrIdempotencyKeyReused,
+		},
+		{
+			name: "Should return error when first request is still running",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(false, nil)
+				mockIdempotencyRepo.EXPECT().GetByKey("abc").Return(&entity.IdempotencyKey{
+					Key:         "abc",
+					Fingerprint: "f1",
+					CreatedAt:   time.Now(),
+				}, nil)
+			},
+			wantErr: ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "Should reserve again an expired key",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				gomock.InOrder(
+					mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(false, nil),
+					mockIdempotencyRepo.EXPECT().GetByKey("abc").Return(&entity.IdempotencyKey{
+						Key:         "abc",
+						Fingerprint: "f2",
+						StatusCode:  201,
+						CreatedAt:   time.Now().Add(-48 * time.Hour),
+					}, nil),
+					mockIdempotencyRepo.EXPECT().Delete("abc").Return(nil),
+					mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(true, nil),
+				)
+			},
+			want: nil,
+		},
+		{
+			name:    "Should return error when key is empty",
+			key:     "",
+			setup:   func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {},
+			wantErr: entity.NewErrorInvalidField("Idempotency-Key", entity.CodeInvalidLength, nil, i18n.IdempotencyKeyInvalid),
+		},
+		{
+			name: "Should return error",
+			key:  "abc",
+			setup: func(mockIdempotencyRepo *repository.MockIdempotencyRepository) {
+				mockIdempotencyRepo.EXPECT().Reserve(gomock.Any()).Return(false, fmt.Errorf("some error occurred"))
+			},
+			wantErr: fmt.Errorf("some error occurred"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockIdempotencyRepo := repository.NewMockIdempotencyRepository(ctrl)
+			tt.setup(mockIdempotencyRepo)
+
+			iu := NewIdempotencyUsecase(mockIdempotencyRepo, 24*time.Hour)
+
+			got, err := iu.Begin(tt.key, "f1")
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
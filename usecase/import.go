@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// importKey is the value of a unique field of an imported row.
+type importKey struct {
+	field string
+	value string
+}
+
+// importKeys remembers the line in which each value of a unique field was
+// first seen in an imported file.
+type importKeys map[importKey]int
+
+// check rejects the row of line when any of its keys was seen in a previous
+// row, or remembers them otherwise. Empty values are not checked.
+func (k importKeys) check(line int, keys ...importKey) error {
+	err := new(entity.ErrorInvalidField)
+	for _, key := range keys {
+		if first, ok := k[key]; ok && key.value != "" {
+			err.Add(key.field, entity.CodeDuplicate, key.value, i18n.ImportDuplicate, key.field, first)
+		}
+	}
+	if len(err.Errors) > 0 {
+		return err
+	}
+	for _, key := range keys {
+		if key.value != "" {
+			k[key] = line
+		}
+	}
+	return nil
+}
+
+// rowError joins the error of reading a row with the error of validating
+// it, dropping the violations of fields that could not be read.
+func rowError(readErr error, err error) error {
+	if readErr == nil {
+		return err
+	}
+	var read, invalid *entity.ErrorInvalidField
+	if !errors.As(readErr, &read) || !errors.As(err, &invalid) {
+		return readErr
+	}
+	joined := &entity.ErrorInvalidField{Errors: append([]entity.FieldError(nil), read.Errors...)}
+	for _, fieldErr := range invalid.Errors {
+		if !hasField(read, fieldErr.Field) {
+			joined.Errors = append(joined.Errors, fieldErr)
+		}
+	}
+	return joined
+}
+
+func hasField(err *entity.ErrorInvalidField, field string) bool {
+	for _, fieldErr := range err.Errors {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// isRowError tells whether err rejects a single row instead of the import.
+func isRowError(err error) bool {
+	switch domainerr.KindOf(err) {
+	case domainerr.Validation, domainerr.Conflict, domainerr.Unprocessable:
+		return true
+	}
+	return false
+}
+
+func importOptions(opts *entity.ImportOptions) (*entity.ImportOptions, error) {
+	if opts == nil {
+		opts = entity.NewImportOptions()
+	}
+	err := opts.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/notifier"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"go.uber.org/zap"
+)
+
+// LicenseScanner periodically looks for drivers whose license is about to
+// expire and sends a notification for each of them, once per license.
+type LicenseScanner struct {
+	log      *zap.SugaredLogger
+	dRepo    repository.DriverRepository
+	notifier notifier.Notifier
+	within   time.Duration
+	interval time.Duration
+}
+
+func NewLicenseScanner(log *zap.SugaredLogger, dRepo repository.DriverRepository, n notifier.Notifier, within, interval time.Duration) *LicenseScanner {
+	return &LicenseScanner{log: log, dRepo: dRepo, notifier: n, within: within, interval: interval}
+}
+
+// Run scans once at start and then on every interval until ctx is done.
+func (ls LicenseScanner) Run(ctx context.Context) {
+	ticker := time.NewTicker(ls.interval)
+	defer ticker.Stop()
+
+	for {
+		err := ls.Scan()
+		if err != nil {
+			ls.log.Errorw("error scanning expiring licenses", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan notifies the licenses entering the window that were not notified
+// yet. A failed notification is not recorded, so it is retried on the next
+// scan.
+func (ls LicenseScanner) Scan() error {
+	return ls.dRepo.GetLicensesToNotify(time.Now().Add(ls.within), func(drivers []*entity.Driver) error {
+		for _, driver := range drivers {
+			err := ls.notifier.LicenseExpiring(driver)
+			if err != nil {
+				ls.log.Errorw("error notifying expiring license", "driverId", driver.ID, "error", err)
+				continue
+			}
+			err = ls.dRepo.MarkLicenseNotified(driver)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/notifier"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestLicenseScanner_Scan(t *testing.T) {
+	drivers := []*entity.Driver{{Name: "Lucas"}, {Name: "John"}}
+	tests := []struct {
+		name    string
+		setup   func(mockDriveRepo *repository.MockDriverRepository, mockNotifier *notifier.MockNotifier)
+		wantErr bool
+	}{
+		{
+			name: "Should notify every driver with expiring license and record it",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockNotifier *notifier.MockNotifier) {
+				mockDriveRepo.EXPECT().GetLicensesToNotify(gomock.Any(), gomock.Any()).DoAndReturn(batchOf(drivers))
+				mockNotifier.EXPECT().LicenseExpiring(drivers[0]).Return(nil)
+				mockDriveRepo.EXPECT().MarkLicenseNotified(drivers[0]).Return(nil)
+				mockNotifier.EXPECT().LicenseExpiring(drivers[1]).Return(nil)
+				mockDriveRepo.EXPECT().MarkLicenseNotified(drivers[1]).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should keep notifying when a notification fails without recording it",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockNotifier *notifier.MockNotifier) {
+				mockDriveRepo.EXPECT().GetLicensesToNotify(gomock.Any(), gomock.Any()).DoAndReturn(batchOf(drivers))
+				mockNotifier.EXPECT().LicenseExpiring(drivers[0]).Return(fmt.Errorf("some error occurred"))
+				mockNotifier.EXPECT().LicenseExpiring(drivers[1]).Return(nil)
+				mockDriveRepo.EXPECT().MarkLicenseNotified(drivers[1]).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Should return error to record the notification",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockNotifier *notifier.MockNotifier) {
+				mockDriveRepo.EXPECT().GetLicensesToNotify(gomock.Any(), gomock.Any()).DoAndReturn(batchOf(drivers))
+				mockNotifier.EXPECT().LicenseExpiring(drivers[0]).Return(nil)
+				mockDriveRepo.EXPECT().MarkLicenseNotified(drivers[0]).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should return error to get drivers",
+			setup: func(mockDriveRepo *repository.MockDriverRepository, mockNotifier *notifier.MockNotifier) {
+				mockDriveRepo.EXPECT().GetLicensesToNotify(gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockDriveRepo := repository.NewMockDriverRepository(ctrl)
+			mockNotifier := notifier.NewMockNotifier(ctrl)
+
+			tt.setup(mockDriveRepo, mockNotifier)
+
+			ls := NewLicenseScanner(zap.NewNop().Sugar(), mockDriveRepo, mockNotifier, 30*24*time.Hour, time.Hour)
+			err := ls.Scan()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// batchOf returns a GetLicensesToNotify implementation handing the drivers
+// over as a single batch.
+func batchOf(drivers []*entity.Driver) func(time.Time, func([]*entity.Driver) error) error {
+	return func(_ time.Time, batch func([]*entity.Driver) error) error {
+		return batch(drivers)
+	}
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+)
+
+// driverDocument holds the fields of a driver a patch can change, with the
+// names and formats of the requests.
+type driverDocument struct {
+	Name             string  `json:"name"`
+	LastName         string  `json:"lastName"`
+	Email            string  `json:"email"`
+	Phone            string  `json:"phone"`
+	CPF              string  `json:"cpf"`
+	License          string  `json:"license"`
+	LicenseType      string  `json:"licenseType"`
+	LicenseIssuedAt  *string `json:"licenseIssuedAt"`
+	LicenseExpiresAt *string `json:"licenseExpiresAt"`
+	FirstLicenseAt   *string `json:"firstLicenseAt"`
+	ExternalSource   *string `json:"externalSource"`
+	ExternalID       *string `json:"externalId"`
+}
+
+func newDriverDocument(driver *entity.Driver) *driverDocument {
+	return &driverDocument{
+		Name:             driver.Name,
+		LastName:         driver.LastName,
+		Email:            driver.Email,
+		Phone:            driver.Phone,
+		CPF:              driver.CPF,
+		License:          driver.License,
+		LicenseType:      driver.LicenseType,
+		LicenseIssuedAt:  formatDate(driver.LicenseIssuedAt),
+		LicenseExpiresAt: formatDate(driver.LicenseExpiresAt),
+		FirstLicenseAt:   formatDate(driver.FirstLicenseAt),
+		ExternalSource:   driver.ExternalSource,
+		ExternalID:       driver.ExternalID,
+	}
+}
+
+// apply copies the fields of the document to driver.
+func (d driverDocument) apply(driver *entity.Driver) error {
+	err := new(entity.ErrorInvalidField)
+	driver.Name = d.Name
+	driver.LastName = d.LastName
+	driver.Email = d.Email
+	driver.Phone = d.Phone
+	driver.CPF = d.CPF
+	driver.License = d.License
+	driver.LicenseType = d.LicenseType
+	driver.LicenseIssuedAt = parseDate(err, "licenseIssuedAt", d.LicenseIssuedAt)
+	driver.LicenseExpiresAt = parseDate(err, "licenseExpiresAt", d.LicenseExpiresAt)
+	driver.FirstLicenseAt = parseDate(err, "firstLicenseAt", d.FirstLicenseAt)
+	driver.ExternalSource = d.ExternalSource
+	driver.ExternalID = d.ExternalID
+	if len(err.Errors) > 0 {
+		return err
+	}
+	return nil
+}
+
+// vehicleDocument holds the fields of a vehicle a patch can change. The
+// driver is changed by the assignment endpoints.
+type vehicleDocument struct {
+	Brand          string  `json:"brand"`
+	VehicleModel   string  `json:"vehicleModel"`
+	Year           int     `json:"year"`
+	Class          string  `json:"class"`
+	Plate          string  `json:"plate"`
+	PlateCountry   string  `json:"plateCountry"`
+	ExternalSource *string `json:"externalSource"`
+	ExternalID     *string `json:"externalId"`
+}
+
+func newVehicleDocument(vehicle *entity.Vehicle) *vehicleDocument {
+	return &vehicleDocument{
+		Brand:          vehicle.Brand,
+		VehicleModel:   vehicle.VehicleModel,
+		Year:           vehicle.Year,
+		Class:          vehicle.Class,
+		Plate:          vehicle.Plate,
+		PlateCountry:   vehicle.PlateCountry,
+		ExternalSource: vehicle.ExternalSource,
+		ExternalID:     vehicle.ExternalID,
+	}
+}
+
+// apply copies the fields of the document to vehicle. A new plate without
+// a new country has its country detected again.
+func (d vehicleDocument) apply(vehicle *entity.Vehicle) {
+	if d.Plate != vehicle.Plate && d.PlateCountry == vehicle.PlateCountry {
+		d.PlateCountry = ""
+	}
+	vehicle.Brand = d.Brand
+	vehicle.VehicleModel = d.VehicleModel
+	vehicle.Year = d.Year
+	vehicle.Class = d.Class
+	vehicle.Plate = d.Plate
+	vehicle.PlateCountry = d.PlateCountry
+	vehicle.ExternalSource = d.ExternalSource
+	vehicle.ExternalID = d.ExternalID
+}
+
+// applyPatch applies patch to current, the document of a record, and
+// decodes the result into patched, a pointer to a zero document. A field
+// removed or set to null by the patch is left with its zero value.
+func applyPatch(current, patched interface{}, patch *entity.Patch) error {
+	original, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var result []byte
+	switch patch.Type {
+	case entity.PatchTypeMerge:
+		result, err = jsonpatch.MergePatch(original, patch.Document)
+	case entity.PatchTypeJSON:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patch.Document)
+		if err == nil {
+			result, err = ops.Apply(original)
+		}
+	default:
+		return domainerr.New(domainerr.Unsupported, i18n.MediaTypeUnsupported,
+			patch.Type, strings.Join([]string{entity.PatchTypeMerge, entity.PatchTypeJSON}, ", "))
+	}
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return domainerr.New(domainerr.Conflict, i18n.PatchTestFailed, err.Error())
+		}
+		return entity.NewErrorInvalidField("body", entity.CodeInvalidFormat, nil, i18n.PatchInvalid, err.Error())
+	}
+
+	err = checkPatchedFields(original, result)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(result, patched)
+	if err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return entity.NewErrorInvalidField(typeErr.Field, entity.CodeInvalid, nil,
+				i18n.MustBeType, typeErr.Field, typeErr.Type.String())
+		}
+		return entity.NewErrorInvalidField("body", entity.CodeInvalidFormat, nil, i18n.PatchInvalid, err.Error())
+	}
+	return nil
+}
+
+// checkPatchedFields rejects the fields added by a patch that the document
+// does not have.
+func checkPatchedFields(original, patched []byte) error {
+	var known, got map[string]json.RawMessage
+	if err := json.Unmarshal(original, &known); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, &got); err != nil {
+		return entity.NewErrorInvalidField("body", entity.CodeInvalidFormat, nil, i18n.PatchInvalid, err.Error())
+	}
+
+	var unknown []string
+	for field := range got {
+		if _, ok := known[field]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	err := new(entity.ErrorInvalidField)
+	for _, field := range unknown {
+		err.Add(field, entity.CodeUnknownField, nil, i18n.FieldUnknown, field)
+	}
+	return err
+}
+
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	value := t.Format(time.DateOnly)
+	return &value
+}
+
+// parseDate parses a date of a document, adding a violation to err when it
+// is not in the format YYYY-MM-DD.
+func parseDate(err *entity.ErrorInvalidField, field string, value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	t, parseErr := time.Parse(time.DateOnly, *value)
+	if parseErr != nil {
+		err.Add(field, entity.CodeInvalidFormat, *value, i18n.MustBeDate, field)
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func mergePatch(document string) *entity.Patch {
+	return &entity.Patch{Type: entity.PatchTypeMerge, Document: []byte(document)}
+}
+
+func jsonPatch(document string) *entity.Patch {
+	return &entity.Patch{Type: entity.PatchTypeJSON, Document: []byte(document)}
+}
+
+func Test_applyPatch(t *testing.T) {
+	expiresAt := "2030-01-15"
+	issuedAt := "2020-01-15"
+	current := driverDocument{
+		Name:             "Lucas",
+		LastName:         "Moura",
+		Email:            "lucas@test.com",
+		LicenseType:      entity.LicenseTypeB,
+		LicenseIssuedAt:  &issuedAt,
+		LicenseExpiresAt: &expiresAt,
+	}
+
+	tests := []struct {
+		name     string
+		patch    *entity.Patch
+		want     driverDocument
+		wantKind domainerr.Kind
+		wantErr  bool
+	}{
+		{
+			name:  "Should keep the fields absent from a merge patch",
+			patch: mergePatch(`{"name":"John"}`),
+			want: driverDocument{Name: "John", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeB,
+				LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name:  "Should clear the fields set to null by a merge patch",
+			patch: mergePatch(`{"licenseIssuedAt":null,"lastName":null}`),
+			want: driverDocument{Name: "Lucas", Email: "lucas@test.com", LicenseType: entity.LicenseTypeB,
+				LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name:  "Should add a field with a JSON patch",
+			patch: jsonPatch(`[{"op":"add","path":"/phone","value":"21987654321"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", Phone: "21987654321",
+				LicenseType: entity.LicenseTypeB, LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name:  "Should remove a field with a JSON patch",
+			patch: jsonPatch(`[{"op":"remove","path":"/licenseIssuedAt"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeB,
+				LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name:  "Should replace a field with a JSON patch",
+			patch: jsonPatch(`[{"op":"replace","path":"/licenseType","value":"C"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeC,
+				LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name:  "Should move a field with a JSON patch",
+			patch: jsonPatch(`[{"op":"move","from":"/licenseIssuedAt","path":"/firstLicenseAt"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeB,
+				LicenseExpiresAt: &expiresAt, FirstLicenseAt: &issuedAt},
+		},
+		{
+			name:  "Should copy a field with a JSON patch",
+			patch: jsonPatch(`[{"op":"copy","from":"/licenseIssuedAt","path":"/firstLicenseAt"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeB,
+				LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt, FirstLicenseAt: &issuedAt},
+		},
+		{
+			name: "Should apply a JSON patch when its test passes",
+			patch: jsonPatch(`[{"op":"test","path":"/licenseType","value":"B"},` +
+				`{"op":"replace","path":"/licenseType","value":"C"}]`),
+			want: driverDocument{Name: "Lucas", LastName: "Moura", Email: "lucas@test.com", LicenseType: entity.LicenseTypeC,
+				LicenseIssuedAt: &issuedAt, LicenseExpiresAt: &expiresAt},
+		},
+		{
+			name: "Should return conflict error when a JSON patch test fails",
+			patch: jsonPatch(`[{"op":"test","path":"/licenseType","value":"A"},` +
+				`{"op":"replace","path":"/licenseType","value":"C"}]`),
+			wantKind: domainerr.Conflict,
+			wantErr:  true,
+		},
+		{
+			name:     "Should return error for an invalid JSON patch",
+			patch:    jsonPatch(`{"op":"replace"}`),
+			wantKind: domainerr.Validation,
+			wantErr:  true,
+		},
+		{
+			name:     "Should return error for an unknown field",
+			patch:    mergePatch(`{"nickname":"Lu"}`),
+			wantKind: domainerr.Validation,
+			wantErr:  true,
+		},
+		{
+			name:     "Should return error for a field of the wrong type",
+			patch:    mergePatch(`{"name":10}`),
+			wantKind: domainerr.Validation,
+			wantErr:  true,
+		},
+		{
+			name:     "Should return error for an unsupported media type",
+			patch:    &entity.Patch{Type: "text/plain", Document: []byte(`name=John`)},
+			wantKind: domainerr.Unsupported,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got driverDocument
+			err := applyPatch(current, &got, tt.patch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantKind, domainerr.KindOf(err))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_driverDocument_apply(t *testing.T) {
+	issuedAt := "2020-01-15"
+	invalid := "15/01/2020"
+
+	driver := new(entity.Driver)
+	err := driverDocument{Name: "Lucas", LicenseIssuedAt: &issuedAt}.apply(driver)
+	assert.Nil(t, err)
+	assert.Equal(t, "Lucas", driver.Name)
+	assert.Equal(t, time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC), *driver.LicenseIssuedAt)
+	assert.Nil(t, driver.LicenseExpiresAt)
+
+	err = driverDocument{LicenseIssuedAt: &invalid}.apply(driver)
+	assert.Error(t, err)
+}
+
+func Test_vehicleDocument_apply(t *testing.T) {
+	vehicle := &entity.Vehicle{Plate: "ABC1234", PlateCountry: "BR"}
+	vehicleDocument{Plate: "AB123CD", PlateCountry: "BR"}.apply(vehicle)
+	assert.Equal(t, "AB123CD", vehicle.Plate)
+	assert.Empty(t, vehicle.PlateCountry)
+
+	vehicleDocument{Plate: "ABC1D23", PlateCountry: "BR"}.apply(vehicle)
+	assert.Equal(t, "BR", vehicle.PlateCountry)
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+)
+
+var (
+	ErrVehicleInUse            = domainerr.New(domainerr.Unprocessable, i18n.VehicleInUse)
+	ErrDriverStatusHasVehicles = domainerr.New(domainerr.Unprocessable, i18n.DriverStatusHasVehicles)
+)
+
+// driverStatuses lists the statuses of a driver in the order they are
+// reported to clients.
+var driverStatuses = []string{
+	entity.DriverStatusActive,
+	entity.DriverStatusOnLeave,
+	entity.DriverStatusSuspended,
+	entity.DriverStatusTerminated,
+}
+
+// driverTransitions maps each status of a driver to the statuses it can
+// change to. Terminated is final.
+var driverTransitions = map[string][]string{
+	entity.DriverStatusActive:     {entity.DriverStatusOnLeave, entity.DriverStatusSuspended, entity.DriverStatusTerminated},
+	entity.DriverStatusOnLeave:    {entity.DriverStatusActive, entity.DriverStatusTerminated},
+	entity.DriverStatusSuspended:  {entity.DriverStatusActive, entity.DriverStatusTerminated},
+	entity.DriverStatusTerminated: {},
+}
+
+// vehicleStatuses lists the statuses of a vehicle in the order they are
+// reported to clients.
+var vehicleStatuses = []string{
+	entity.VehicleStatusAvailable,
+	entity.VehicleStatusInUse,
+	entity.VehicleStatusInMaintenance,
+	entity.VehicleStatusOutOfService,
+	entity.VehicleStatusSold,
+}
+
+// vehicleTransitions maps each status of a vehicle to the statuses it can
+// change to. In use is only entered and left by assigning and unassigning
+// the vehicle, and sold is final.
+var vehicleTransitions = map[string][]string{
+	entity.VehicleStatusAvailable:     {entity.VehicleStatusInMaintenance, entity.VehicleStatusOutOfService, entity.VehicleStatusSold},
+	entity.VehicleStatusInUse:         {},
+	entity.VehicleStatusInMaintenance: {entity.VehicleStatusAvailable, entity.VehicleStatusOutOfService},
+	entity.VehicleStatusOutOfService:  {entity.VehicleStatusAvailable, entity.VehicleStatusInMaintenance, entity.VehicleStatusSold},
+	entity.VehicleStatusSold:          {},
+}
+
+// checkTransition makes sure status is one of statuses and transitions
+// allows changing from it to status.
+func checkTransition(statuses []string, transitions map[string][]string, from string, status string) error {
+	if !slices.Contains(statuses, status) {
+		return entity.NewErrorInvalidField("status", entity.CodeInvalid, status, i18n.StatusInvalid, strings.Join(statuses, ", "))
+	}
+	if !slices.Contains(transitions[from], status) {
+		return domainerr.New(domainerr.Unprocessable, i18n.StatusTransitionInvalid, from, status)
+	}
+	return nil
+}
+
+// checkAssignableStatus makes sure the driver is active and the vehicle is
+// available, or in use when it is transferred.
+func checkAssignableStatus(driver *entity.Driver, vehicle *entity.Vehicle) error {
+	if driver.Status != entity.DriverStatusActive {
+		return domainerr.New(domainerr.Unprocessable, i18n.DriverNotActive, driver.Status)
+	}
+	if vehicle.Status != entity.VehicleStatusAvailable && vehicle.Status != entity.VehicleStatusInUse {
+		return domainerr.New(domainerr.Unprocessable, i18n.VehicleNotAvailable, vehicle.Status)
+	}
+	return nil
+}
+
+type StatusUsecase interface {
+	GetByDriver(driverId int) ([]*entity.StatusChange, error)
+	GetByVehicle(vehicleId int) ([]*entity.StatusChange, error)
+	ChangeDriverStatus(driverId int, version uint, status string, reason string, actor entity.Actor) (*entity.Driver, error)
+	ChangeVehicleStatus(vehicleId int, version uint, status string, reason string, actor entity.Actor) (*entity.Vehicle, error)
+}
+
+type statusUsecase struct {
+	sRepo repository.StatusRepository
+	dRepo repository.DriverRepository
+	vRepo repository.VehicleRepository
+	uow   repository.UnitOfWork
+}
+
+func NewStatusUsecase(sRepo repository.StatusRepository, dRepo repository.DriverRepository, vRepo repository.VehicleRepository, uow repository.UnitOfWork) *statusUsecase {
+	return &statusUsecase{sRepo: sRepo, dRepo: dRepo, vRepo: vRepo, uow: uow}
+}
+
+// inTransaction runs fn with a copy of the usecase whose repositories are
+// bound to a unit of work, so what fn reads and writes is committed or
+// rolled back together.
+func (su statusUsecase) inTransaction(fn func(tx statusUsecase) error) error {
+	return su.uow.Do(func(repos repository.Repositories) error {
+		tx := su
+		tx.sRepo, tx.dRepo, tx.vRepo = repos.Statuses, repos.Drivers, repos.Vehicles
+		return fn(tx)
+	})
+}
+
+// GetByDriver returns the status changes of the driver, the latest first.
+func (su statusUsecase) GetByDriver(driverId int) ([]*entity.StatusChange, error) {
+	driver, err := getDriver(su.dRepo, driverId)
+	if err != nil {
+		return nil, err
+	}
+	return su.sRepo.GetByEntity(entity.AuditEntityDriver, driver.ID)
+}
+
+// GetByVehicle returns the status changes of the vehicle, the latest first.
+func (su statusUsecase) GetByVehicle(vehicleId int) ([]*entity.StatusChange, error) {
+	vehicle, err := getVehicle(su.vRepo, vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	return su.sRepo.GetByEntity(entity.AuditEntityVehicle, vehicle.ID)
+}
+
+// ChangeDriverStatus moves the driver to status when driverTransitions
+// allows it. Only drivers without vehicles can leave the active status, as
+// the others can not drive them. When version is not zero the driver is only
+// changed if it was not changed since that version.
+func (su statusUsecase) ChangeDriverStatus(driverId int, version uint, status string, reason string, actor entity.Actor) (*entity.Driver, error) {
+	var driver *entity.Driver
+	err := su.inTransaction(func(tx statusUsecase) error {
+		var err error
+		driver, err = tx.changeDriverStatus(driverId, version, status, reason, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// changeDriverStatus is ChangeDriverStatus inside a unit of work, so no
+// vehicle can be assigned to the driver before its status is saved.
+func (su statusUsecase) changeDriverStatus(driverId int, version uint, status string, reason string, actor entity.Actor) (*entity.Driver, error) {
+	driver, err := getDriver(su.dRepo, driverId)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != driver.Version {
+		return nil, ErrVersionMismatch
+	}
+	err = checkTransition(driverStatuses, driverTransitions, driver.Status, status)
+	if err != nil {
+		return nil, err
+	}
+	err = entity.ValidateStatusReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if driver.Status == entity.DriverStatusActive {
+		vehicles, err := su.vRepo.GetByDriver(driver.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(vehicles) > 0 {
+			return nil, ErrDriverStatusHasVehicles
+		}
+	}
+
+	err = su.sRepo.ChangeDriverStatus(driver, status, strings.TrimSpace(reason), actor)
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// ChangeVehicleStatus moves the vehicle to status when vehicleTransitions
+// allows it. A vehicle in use must be unassigned first. When version is not
+// zero the vehicle is only changed if it was not changed since that version.
+func (su statusUsecase) ChangeVehicleStatus(vehicleId int, version uint, status string, reason string, actor entity.Actor) (*entity.Vehicle, error) {
+	var vehicle *entity.Vehicle
+	err := su.inTransaction(func(tx statusUsecase) error {
+		var err error
+		vehicle, err = tx.changeVehicleStatus(vehicleId, version, status, reason, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+// changeVehicleStatus is ChangeVehicleStatus inside a unit of work, so the
+// status checked is the one replaced.
+func (su statusUsecase) changeVehicleStatus(vehicleId int, version uint, status string, reason string, actor entity.Actor) (*entity.Vehicle, error) {
+	vehicle, err := getVehicle(su.vRepo, vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != vehicle.Version {
+		return nil, ErrVersionMismatch
+	}
+	if vehicle.Status == entity.VehicleStatusInUse && slices.Contains(vehicleStatuses, status) {
+		return nil, ErrVehicleInUse
+	}
+	err = checkTransition(vehicleStatuses, vehicleTransitions, vehicle.Status, status)
+	if err != nil {
+		return nil, err
+	}
+	err = entity.ValidateStatusReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	err = su.sRepo.ChangeVehicleStatus(vehicle, status, strings.TrimSpace(reason), actor)
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/status.go
+
+// Package usecase is a generated GoMock package.
+package usecase
+
+import (
+	reflect "reflect"
+
+	entity "github.com/lucas-moura1/gobrax-challenge/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatusUsecase is a mock of StatusUsecase interface.
+type MockStatusUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusUsecaseMockRecorder
+}
+
+// MockStatusUsecaseMockRecorder is the mock recorder for MockStatusUsecase.
+type MockStatusUsecaseMockRecorder struct {
+	mock *MockStatusUsecase
+}
+
+// NewMockStatusUsecase creates a new mock instance.
+func NewMockStatusUsecase(ctrl *gomock.Controller) *MockStatusUsecase {
+	mock := &MockStatusUsecase{ctrl: ctrl}
+	mock.recorder = &MockStatusUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusUsecase) EXPECT() *MockStatusUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ChangeDriverStatus mocks base method.
+func (m *MockStatusUsecase) ChangeDriverStatus(driverId int, version uint, status, reason string, actor entity.Actor) (*entity.Driver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeDriverStatus", driverId, version, status, reason, actor)
+	ret0, _ := ret[0].(*entity.Driver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeDriverStatus indicates an expected call of ChangeDriverStatus.
+func (mr *MockStatusUsecaseMockRecorder) ChangeDriverStatus(driverId, version, status, reason, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeDriverStatus", reflect.TypeOf((*MockStatusUsecase)(nil).ChangeDriverStatus), driverId, version, status, reason, actor)
+}
+
+// ChangeVehicleStatus mocks base method.
+func (m *MockStatusUsecase) ChangeVehicleStatus(vehicleId int, version uint, status, reason string, actor entity.Actor) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeVehicleStatus", vehicleId, version, status, reason, actor)
+	ret0, _ := ret[0].(*entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeVehicleStatus indicates an expected call of ChangeVehicleStatus.
+func (mr *MockStatusUsecaseMockRecorder) ChangeVehicleStatus(vehicleId, version, status, reason, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeVehicleStatus", reflect.TypeOf((*MockStatusUsecase)(nil).ChangeVehicleStatus), vehicleId, version, status, reason, actor)
+}
+
+// GetByDriver mocks base method.
+func (m *MockStatusUsecase) GetByDriver(driverId int) ([]*entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDriver", driverId)
+	ret0, _ := ret[0].([]*entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDriver indicates an expected call of GetByDriver.
+func (mr *MockStatusUsecaseMockRecorder) GetByDriver(driverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDriver", reflect.TypeOf((*MockStatusUsecase)(nil).GetByDriver), driverId)
+}
+
+// GetByVehicle mocks base method.
+func (m *MockStatusUsecase) GetByVehicle(vehicleId int) ([]*entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByVehicle", vehicleId)
+	ret0, _ := ret[0].([]*entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByVehicle indicates an expected call of GetByVehicle.
+func (mr *MockStatusUsecaseMockRecorder) GetByVehicle(vehicleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByVehicle", reflect.TypeOf((*MockStatusUsecase)(nil).GetByVehicle), vehicleId)
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func Test_statusUsecase_GetByVehicle(t *testing.T) {
+	changes := []*entity.StatusChange{
+		{ID: 2, EntityType: entity.AuditEntityVehicle, EntityID: 2, FromStatus: entity.VehicleStatusInMaintenance, ToStatus: entity.VehicleStatusAvailable},
+		{ID: 1, EntityType: entity.AuditEntityVehicle, EntityID: 2, FromStatus: entity.VehicleStatusAvailable, ToStatus: entity.VehicleStatusInMaintenance},
+	}
+	tests := []struct {
+		name    string
+		setup   func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		want    []*entity.StatusChange
+		wantErr error
+	}{
+		{
+			name: "Should return the status history of the vehicle",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}}, nil)
+				mockStatusRepo.EXPECT().GetByEntity(entity.AuditEntityVehicle, uint(2)).Return(changes, nil)
+			},
+			want: changes,
+		},
+		{
+			name: "Should return vehicle not found error",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(nil, nil)
+			},
+			wantErr: ErrVehicleNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStatusRepo := repository.NewMockStatusRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockStatusRepo, mockVehicleRepo)
+
+			su := NewStatusUsecase(mockStatusRepo, repository.NewMockDriverRepository(ctrl), mockVehicleRepo, repository.NewMockUnitOfWork(ctrl))
+
+			got, err := su.GetByVehicle(2)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_statusUsecase_ChangeDriverStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint
+		status  string
+		reason  string
+		setup   func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		wantErr error
+	}{
+		{
+			name:   "Should put an active driver on leave",
+			status: entity.DriverStatusOnLeave,
+			reason: " vacation ",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				driver := &entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive}
+				mockDriverRepo.EXPECT().GetById(1, false).Return(driver, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return(nil, nil)
+				mockStatusRepo.EXPECT().ChangeDriverStatus(driver, entity.DriverStatusOnLeave, "vacation", testActor).Return(nil)
+			},
+		},
+		{
+			name:   "Should reactivate a suspended driver",
+			status: entity.DriverStatusActive,
+			reason: "suspension ended",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				driver := &entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusSuspended}
+				mockDriverRepo.EXPECT().GetById(1, false).Return(driver, nil)
+				mockStatusRepo.EXPECT().ChangeDriverStatus(driver, entity.DriverStatusActive, "suspension ended", testActor).Return(nil)
+			},
+		},
+		{
+			name:   "Should return error when the driver is terminated",
+			status: entity.DriverStatusActive,
+			reason: "rehired",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusTerminated}, nil)
+			},
+			wantErr: domainerr.New(domainerr.Unprocessable, i18n.StatusTransitionInvalid, entity.DriverStatusTerminated, entity.DriverStatusActive),
+		},
+		{
+			name:   "Should return error when the status is unknown",
+			status: "retired",
+			reason: "retired",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive}, nil)
+			},
+			wantErr: entity.NewErrorInvalidField("status", entity.CodeInvalid, "retired", i18n.StatusInvalid, strings.Join(driverStatuses, ", ")),
+		},
+		{
+			name:   "Should return error when the reason is empty",
+			status: entity.DriverStatusSuspended,
+			reason: " ",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive}, nil)
+			},
+			wantErr: entity.NewErrorInvalidField("reason", entity.CodeRequired, nil, i18n.StatusReasonRequired),
+		},
+		{
+			name:   "Should return error when the driver still has vehicles",
+			status: entity.DriverStatusSuspended,
+			reason: "traffic violation",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive}, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return([]*entity.Vehicle{{Model: gorm.Model{ID: 2}}}, nil)
+			},
+			wantErr: ErrDriverStatusHasVehicles,
+		},
+		{
+			name:    "Should return error when the version does not match",
+			version: 2,
+			status:  entity.DriverStatusOnLeave,
+			reason:  "vacation",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive, Version: 3}, nil)
+			},
+			wantErr: ErrVersionMismatch,
+		},
+		{
+			name:   "Should return driver not found error",
+			status: entity.DriverStatusOnLeave,
+			reason: "vacation",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(nil, nil)
+			},
+			wantErr: ErrDriverNotFound,
+		},
+		{
+			name:   "Should return error",
+			status: entity.DriverStatusOnLeave,
+			reason: "vacation",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockDriverRepo *repository.MockDriverRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{Model: gorm.Model{ID: 1}, Status: entity.DriverStatusActive}, nil)
+				mockVehicleRepo.EXPECT().GetByDriver(uint(1)).Return(nil, nil)
+				mockStatusRepo.EXPECT().ChangeDriverStatus(gomock.Any(), entity.DriverStatusOnLeave, "vacation", testActor).Return(fmt.Errorf("some error occurred"))
+			},
+			wantErr: fmt.Errorf("some error occurred"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStatusRepo := repository.NewMockStatusRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockStatusRepo, mockDriverRepo, mockVehicleRepo)
+
+			su := NewStatusUsecase(mockStatusRepo, mockDriverRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Statuses: mockStatusRepo}))
+
+			got, err := su.ChangeDriverStatus(1, tt.version, tt.status, tt.reason, testActor)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, uint(1), got.ID)
+		})
+	}
+}
+
+func Test_statusUsecase_ChangeVehicleStatus(t *testing.T) {
+	driverId := uint(1)
+	tests := []struct {
+		name    string
+		status  string
+		reason  string
+		setup   func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository)
+		wantErr error
+	}{
+		{
+			name:   "Should send an available vehicle to maintenance",
+			status: entity.VehicleStatusInMaintenance,
+			reason: "oil change",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				vehicle := &entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable}
+				mockVehicleRepo.EXPECT().GetById(2).Return(vehicle, nil)
+				mockStatusRepo.EXPECT().ChangeVehicleStatus(vehicle, entity.VehicleStatusInMaintenance, "oil change", testActor).Return(nil)
+			},
+		},
+		{
+			name:   "Should return error when the vehicle is in use",
+			status: entity.VehicleStatusInMaintenance,
+			reason: "oil change",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInUse, DriverID: &driverId}, nil)
+			},
+			wantErr: ErrVehicleInUse,
+		},
+		{
+			name:   "Should return error when the vehicle is set in use",
+			status: entity.VehicleStatusInUse,
+			reason: "lent",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable}, nil)
+			},
+			wantErr: domainerr.New(domainerr.Unprocessable, i18n.StatusTransitionInvalid, entity.VehicleStatusAvailable, entity.VehicleStatusInUse),
+		},
+		{
+			name:   "Should return error when a vehicle in maintenance is sold",
+			status: entity.VehicleStatusSold,
+			reason: "sold",
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusInMaintenance}, nil)
+			},
+			wantErr: domainerr.New(domainerr.Unprocessable, i18n.StatusTransitionInvalid, entity.VehicleStatusInMaintenance, entity.VehicleStatusSold),
+		},
+		{
+			name:   "Should return error when the reason is too long",
+			status: entity.VehicleStatusOutOfService,
+			reason: strings.Repeat("a", entity.MaxStatusReasonLength+1),
+			setup: func(mockStatusRepo *repository.MockStatusRepository, mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(2).Return(&entity.Vehicle{Model: gorm.Model{ID: 2}, Status: entity.VehicleStatusAvailable}, nil)
+			},
+			wantErr: entity.NewErrorInvalidField("reason", entity.CodeTooLong, strings.Repeat("a", entity.MaxStatusReasonLength+1), i18n.StatusReasonTooLong, entity.MaxStatusReasonLength),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStatusRepo := repository.NewMockStatusRepository(ctrl)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockStatusRepo, mockVehicleRepo)
+
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			su := NewStatusUsecase(mockStatusRepo, mockDriverRepo, mockVehicleRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo, Statuses: mockStatusRepo}))
+
+			got, err := su.ChangeVehicleStatus(2, 0, tt.status, tt.reason, testActor)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, uint(2), got.ID)
+		})
+	}
+}
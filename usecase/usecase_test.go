@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/repository"
+	"go.uber.org/mock/gomock"
+)
+
+// testActor is the actor of the changes made by the tests.
+var testActor = entity.NewActor("tester", "")
+
+// newUnitOfWork returns a unit of work that runs every operation with the
+// given repositories, as a transaction that is always committed.
+func newUnitOfWork(ctrl *gomock.Controller, repos repository.Repositories) *repository.MockUnitOfWork {
+	uow := repository.NewMockUnitOfWork(ctrl)
+	uow.EXPECT().Do(gomock.Any()).DoAndReturn(func(fn func(repository.Repositories) error) error {
+		return fn(repos)
+	}).AnyTimes()
+	return uow
+}
@@ -1,42 +1,81 @@
 package usecase
 
 import (
-	"errors"
-
+	"github.com/lucas-moura1/gobrax-challenge/domainerr"
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/repository"
 )
 
-var ErrVehicleNotFound = errors.New("vehicle not found")
+var ErrVehicleNotFound = domainerr.New(domainerr.NotFound, i18n.VehicleNotFound)
 
 type VehicleUsecase interface {
-	GetAll() ([]*entity.Vehicle, error)
+	GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error)
+	Export(opts *entity.QueryOptions, write func([]*entity.Vehicle) error) error
 	GetById(vehicleId int) (*entity.Vehicle, error)
-	Update(vehicleId int, updateVehicle *entity.Vehicle) error
-	Delete(vehicleId int) error
+	GetByExternalId(source string, externalId string) (*entity.Vehicle, error)
+	Create(vehicle *entity.Vehicle, actor entity.Actor) error
+	ImportVehicles(rows []entity.VehicleImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error)
+	Update(vehicleId int, patch *entity.Patch, actor entity.Actor) error
+	Replace(vehicleId int, vehicle *entity.Vehicle, actor entity.Actor) (bool, error)
+	ReplaceByExternalId(source string, externalId string, vehicle *entity.Vehicle, actor entity.Actor) (bool, error)
+	Delete(vehicleId int, version uint, actor entity.Actor) error
+	Restore(vehicleId int, actor entity.Actor) (*entity.Vehicle, error)
+	Purge(vehicleId int, actor entity.Actor) error
 }
 
 type vehicleUsecase struct {
 	vRepo repository.VehicleRepository
+	dRepo repository.DriverRepository
+	uow   repository.UnitOfWork
+}
+
+func NewVehicleUsecase(vRepo repository.VehicleRepository, dRepo repository.DriverRepository, uow repository.UnitOfWork) *vehicleUsecase {
+	return &vehicleUsecase{vRepo: vRepo, dRepo: dRepo, uow: uow}
 }
 
-func NewVehicleUsecase(vRepo repository.VehicleRepository) *vehicleUsecase {
-	return &vehicleUsecase{vRepo: vRepo}
+// inTransaction runs fn with a copy of the usecase whose repositories are
+// bound to a unit of work, so what fn reads and writes is committed or
+// rolled back together.
+func (vu vehicleUsecase) inTransaction(fn func(tx vehicleUsecase) error) error {
+	return vu.uow.Do(func(repos repository.Repositories) error {
+		tx := vu
+		tx.vRepo, tx.dRepo = repos.Vehicles, repos.Drivers
+		return fn(tx)
+	})
 }
 
-func (vu vehicleUsecase) GetAll() ([]*entity.Vehicle, error) {
-	vehicles, err := vu.vRepo.GetAll()
+func (vu vehicleUsecase) GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error) {
+	if opts == nil {
+		opts = entity.NewQueryOptions()
+	}
+	err := opts.Validate(entity.VehicleQueryFields)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	normalizePlateFilters(opts)
+
+	vehicles, total, err := vu.vRepo.GetAll(opts)
+	if err != nil {
+		return nil, 0, err
 	}
-	return vehicles, nil
+	return vehicles, total, nil
+}
+
+// Export calls write with the vehicles matching the filters of opts, a batch
+// at a time, ordered by id.
+func (vu vehicleUsecase) Export(opts *entity.QueryOptions, write func([]*entity.Vehicle) error) error {
+	opts, err := exportOptions(opts, entity.VehicleQueryFields)
+	if err != nil {
+		return err
+	}
+	normalizePlateFilters(opts)
+	return vu.vRepo.Export(opts, write)
 }
 
 func (vu vehicleUsecase) GetById(vehicleId int) (*entity.Vehicle, error) {
 	if vehicleId <= 0 {
-		return nil, &entity.ErrorInvalidField{
-			Message: []string{"vehicle id is invalid"},
-		}
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
 	}
 
 	vehicle, err := vu.vRepo.GetById(vehicleId)
@@ -46,13 +85,150 @@ func (vu vehicleUsecase) GetById(vehicleId int) (*entity.Vehicle, error) {
 	return vehicle, nil
 }
 
-func (vu vehicleUsecase) Update(vehicleId int, updateVehicle *entity.Vehicle) error {
-	if vehicleId <= 0 {
-		return &entity.ErrorInvalidField{
-			Message: []string{"vehicle id is invalid"},
+// GetByExternalId returns the vehicle with the id of the system it is mastered in.
+func (vu vehicleUsecase) GetByExternalId(source string, externalId string) (*entity.Vehicle, error) {
+	err := entity.ValidateExternalId(source, externalId)
+	if err != nil {
+		return nil, err
+	}
+	vehicle, err := vu.vRepo.GetByExternalId(source, externalId)
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+// Create registers an available vehicle without a driver, it can be
+// assigned later.
+func (vu vehicleUsecase) Create(vehicle *entity.Vehicle, actor entity.Actor) error {
+	if vehicle == nil {
+		return entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.VehicleRequired)
+	}
+	vehicle.DriverID = nil
+	vehicle.Status = entity.VehicleStatusAvailable
+	vehicle.NormalizePlate()
+	err := vehicle.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = checkPlate(vu.vRepo, vehicle)
+	if err != nil {
+		return err
+	}
+
+	err = checkExternalId(vu.vRepo, vehicle)
+	if err != nil {
+		return err
+	}
+
+	err = vu.vRepo.Create(vehicle, actor)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ImportVehicles validates the vehicles read from a file and creates the
+// valid ones without a driver. An all or nothing import creates none of them
+// when any row is invalid, and a dry run only validates them.
+func (vu vehicleUsecase) ImportVehicles(rows []entity.VehicleImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error) {
+	opts, err := importOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := entity.NewImportReport(*opts, len(rows))
+	keys := importKeys{}
+	var valid []entity.VehicleImportRow
+	for _, row := range rows {
+		err := vu.checkImportRow(row, keys)
+		if err != nil {
+			if !isRowError(err) {
+				return nil, err
+			}
+			report.Reject(row.Line, err)
+			continue
+		}
+		valid = append(valid, row)
+	}
+	report.Valid = len(valid)
+	if opts.DryRun || len(valid) == 0 {
+		return report, nil
+	}
+
+	if opts.Mode == entity.ImportModeAllOrNothing {
+		if report.Failed() > 0 {
+			return report, nil
 		}
+		vehicles := make([]*entity.Vehicle, len(valid))
+		for i, row := range valid {
+			vehicles[i] = row.Vehicle
+		}
+		err = vu.vRepo.CreateBatch(vehicles, actor)
+		if err != nil {
+			return nil, err
+		}
+		report.Imported = len(vehicles)
+		return report, nil
+	}
+
+	for _, row := range valid {
+		err = vu.vRepo.Create(row.Vehicle, actor)
+		if err != nil {
+			if !isRowError(err) {
+				return nil, err
+			}
+			report.Reject(row.Line, err)
+			continue
+		}
+		report.Imported++
+	}
+	return report, nil
+}
+
+// checkImportRow validates the vehicle of row like Create does, and makes
+// sure its plate and external id were not used by a previous row of the file.
+func (vu vehicleUsecase) checkImportRow(row entity.VehicleImportRow, keys importKeys) error {
+	if row.Vehicle == nil {
+		return row.Err
+	}
+	row.Vehicle.DriverID = nil
+	row.Vehicle.NormalizePlate()
+	err := rowError(row.Err, row.Vehicle.Validate())
+	if err != nil {
+		return err
+	}
+
+	err = keys.check(row.Line,
+		importKey{field: "plate", value: row.Vehicle.Plate},
+		importKey{field: "externalId", value: externalRef(row.Vehicle.ExternalSource, row.Vehicle.ExternalID)},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = checkPlate(vu.vRepo, row.Vehicle)
+	if err != nil {
+		return err
+	}
+	return checkExternalId(vu.vRepo, row.Vehicle)
+}
+
+// Update applies patch to the vehicle. When the version of the patch is not
+// zero the vehicle is only changed if it was not changed since that version.
+func (vu vehicleUsecase) Update(vehicleId int, patch *entity.Patch, actor entity.Actor) error {
+	if vehicleId <= 0 {
+		return entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
 	}
+	return vu.inTransaction(func(tx vehicleUsecase) error {
+		return tx.update(vehicleId, patch, actor)
+	})
+}
 
+// update is Update inside a unit of work, so the driver checked against a
+// new class can not change before the vehicle is saved.
+func (vu vehicleUsecase) update(vehicleId int, patch *entity.Patch, actor entity.Actor) error {
 	vehicle, err := vu.vRepo.GetById(vehicleId)
 	if err != nil {
 		return err
@@ -60,42 +236,296 @@ func (vu vehicleUsecase) Update(vehicleId int, updateVehicle *entity.Vehicle) er
 	if vehicle == nil {
 		return ErrVehicleNotFound
 	}
+	if patch.Version != 0 && patch.Version != vehicle.Version {
+		return ErrVersionMismatch
+	}
+
+	current := *vehicle
 
-	if updateVehicle.Brand != "" {
-		vehicle.Brand = updateVehicle.Brand
+	var doc vehicleDocument
+	err = applyPatch(newVehicleDocument(vehicle), &doc, patch)
+	if err != nil {
+		return err
 	}
-	if updateVehicle.Plate != "" {
-		vehicle.Plate = updateVehicle.Plate
+	doc.apply(vehicle)
+
+	vehicle.NormalizePlate()
+	err = vehicle.ValidateChange(current)
+	if err != nil {
+		return err
 	}
-	if updateVehicle.VehicleModel != "" {
-		vehicle.VehicleModel = updateVehicle.VehicleModel
+
+	if vehicle.Plate != current.Plate {
+		err = checkPlate(vu.vRepo, vehicle)
+		if err != nil {
+			return err
+		}
 	}
-	if updateVehicle.Year != 0 {
-		vehicle.Year = updateVehicle.Year
+
+	if externalRef(vehicle.ExternalSource, vehicle.ExternalID) != externalRef(current.ExternalSource, current.ExternalID) {
+		err = checkExternalId(vu.vRepo, vehicle)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = vehicle.Validate()
-	if err != nil {
-		return err
+	if vehicle.Class != current.Class && vehicle.DriverID != nil {
+		err = vu.checkDriverLicense(vehicle)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = vu.vRepo.Update(vehicle)
+	err = vu.vRepo.Update(vehicle, actor)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (vu vehicleUsecase) Delete(vehicleId int) error {
+// Replace overwrites every field of the vehicle with vehicleId. Vehicles are
+// never created with an id chosen by the client, so an unknown id is
+// ErrVehicleNotFound and the returned bool, whether the vehicle was created,
+// is always false. The driver of the vehicle is kept, it is changed by the
+// assignments. When the version of vehicle is not zero the vehicle is only
+// replaced if it was not changed since that version.
+func (vu vehicleUsecase) Replace(vehicleId int, vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
 	if vehicleId <= 0 {
-		return &entity.ErrorInvalidField{
-			Message: []string{"vehicle id is invalid"},
+		return false, entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
+	}
+	if vehicle == nil {
+		return false, entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.VehicleRequired)
+	}
+	var created bool
+	err := vu.inTransaction(func(tx vehicleUsecase) error {
+		current, err := tx.vRepo.GetById(vehicleId)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return missingVehicle(tx.vRepo, vehicleId)
+		}
+		vehicle.ID = uint(vehicleId)
+		created, err = tx.replace(vehicle, current, actor)
+		return err
+	})
+	return created, err
+}
+
+// ReplaceByExternalId is Replace for the vehicle with the external id, which
+// is created without a driver when it does not exist. It is the only replace
+// that creates vehicles.
+func (vu vehicleUsecase) ReplaceByExternalId(source string, externalId string, vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
+	err := entity.ValidateExternalId(source, externalId)
+	if err != nil {
+		return false, err
+	}
+	if vehicle == nil {
+		return false, entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.VehicleRequired)
+	}
+	var created bool
+	err = vu.inTransaction(func(tx vehicleUsecase) error {
+		current, err := tx.vRepo.GetByExternalId(source, externalId)
+		if err != nil {
+			return err
+		}
+		vehicle.ID = 0
+		if current != nil {
+			vehicle.ID = current.ID
+		}
+		vehicle.ExternalSource = &source
+		vehicle.ExternalID = &externalId
+		created, err = tx.replace(vehicle, current, actor)
+		return err
+	})
+	return created, err
+}
+
+// replace overwrites current with vehicle, or creates vehicle when current is nil.
+func (vu vehicleUsecase) replace(vehicle *entity.Vehicle, current *entity.Vehicle, actor entity.Actor) (bool, error) {
+	if current == nil {
+		current = new(entity.Vehicle)
+	}
+	if vehicle.Version != 0 && vehicle.Version != current.Version {
+		return false, ErrVersionMismatch
+	}
+	vehicle.Version = current.Version
+	vehicle.DriverID = current.DriverID
+	vehicle.Status = current.Status
+
+	vehicle.NormalizePlate()
+	err := vehicle.ValidateChange(*current)
+	if err != nil {
+		return false, err
+	}
+
+	if vehicle.Plate != current.Plate {
+		err = checkPlate(vu.vRepo, vehicle)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if externalRef(vehicle.ExternalSource, vehicle.ExternalID) != externalRef(current.ExternalSource, current.ExternalID) {
+		err = checkExternalId(vu.vRepo, vehicle)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if vehicle.Class != current.Class && vehicle.DriverID != nil {
+		err = vu.checkDriverLicense(vehicle)
+		if err != nil {
+			return false, err
 		}
 	}
 
-	err := vu.vRepo.Delete(vehicleId)
+	created, err := vu.vRepo.Replace(vehicle, actor)
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// Delete removes the vehicle. When version is not zero the vehicle is only
+// removed if it was not changed since that version.
+func (vu vehicleUsecase) Delete(vehicleId int, version uint, actor entity.Actor) error {
+	return vu.inTransaction(func(tx vehicleUsecase) error {
+		_, err := getVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		return tx.vRepo.Delete(vehicleId, version, actor)
+	})
+}
+
+// Restore undeletes the vehicle. It comes back without a driver, as its
+// assignment ended when it was deleted.
+func (vu vehicleUsecase) Restore(vehicleId int, actor entity.Actor) (*entity.Vehicle, error) {
+	var vehicle *entity.Vehicle
+	err := vu.inTransaction(func(tx vehicleUsecase) error {
+		var err error
+		vehicle, err = getDeletedVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		return tx.vRepo.Restore(vehicle, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vehicle, nil
+}
+
+// Purge removes a deleted vehicle for good. Only admins can purge, and only
+// vehicles already deleted.
+func (vu vehicleUsecase) Purge(vehicleId int, actor entity.Actor) error {
+	if !actor.IsAdmin() {
+		return ErrPurgeNotAllowed
+	}
+	return vu.inTransaction(func(tx vehicleUsecase) error {
+		_, err := getDeletedVehicle(tx.vRepo, vehicleId)
+		if err != nil {
+			return err
+		}
+		return tx.vRepo.Purge(vehicleId, actor)
+	})
+}
+
+// checkDriverLicense makes sure the license of the vehicle driver allows driving it.
+func (vu vehicleUsecase) checkDriverLicense(vehicle *entity.Vehicle) error {
+	driver, err := vu.dRepo.GetById(int(*vehicle.DriverID), false)
 	if err != nil {
 		return err
 	}
+	if driver == nil {
+		return nil
+	}
+	return driver.CheckLicense(*vehicle)
+}
+
+// checkPlate makes sure no other vehicle is registered with the same plate.
+func checkPlate(vRepo repository.VehicleRepository, vehicle *entity.Vehicle) error {
+	existing, err := vRepo.GetByPlate(vehicle.Plate)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != vehicle.ID {
+		return &entity.ErrorConflict{Entity: "vehicle", Field: "plate"}
+	}
 	return nil
 }
+
+// checkExternalId makes sure no other vehicle is registered with the same
+// external id.
+func checkExternalId(vRepo repository.VehicleRepository, vehicle *entity.Vehicle) error {
+	if vehicle.ExternalSource == nil || vehicle.ExternalID == nil {
+		return nil
+	}
+	existing, err := vRepo.GetByExternalId(*vehicle.ExternalSource, *vehicle.ExternalID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != vehicle.ID {
+		return &entity.ErrorConflict{Entity: "vehicle", Field: "externalId"}
+	}
+	return nil
+}
+
+// normalizePlateFilters lets the plates of the filters be written with or
+// without a mask, as they are stored normalized.
+func normalizePlateFilters(opts *entity.QueryOptions) {
+	for i, filter := range opts.Filters {
+		if filter.Field == "plate" {
+			opts.Filters[i].Value = entity.NormalizePlate(filter.Value)
+		}
+	}
+}
+
+// getVehicle returns the vehicle or ErrVehicleNotFound when it does not exist.
+func getVehicle(vRepo repository.VehicleRepository, vehicleId int) (*entity.Vehicle, error) {
+	if vehicleId <= 0 {
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
+	}
+	vehicle, err := vRepo.GetById(vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	if vehicle == nil {
+		return nil, ErrVehicleNotFound
+	}
+	return vehicle, nil
+}
+
+// missingVehicle returns the error of a vehicle id that was not found: the
+// vehicle may have been deleted, or never existed.
+func missingVehicle(vRepo repository.VehicleRepository, vehicleId int) error {
+	vehicle, err := vRepo.GetDeletedById(vehicleId)
+	if err != nil {
+		return err
+	}
+	if vehicle != nil {
+		return ErrRecordDeleted
+	}
+	return ErrVehicleNotFound
+}
+
+// getDeletedVehicle returns the deleted vehicle, ErrRecordNotDeleted when it
+// is not deleted or ErrVehicleNotFound when it does not exist.
+func getDeletedVehicle(vRepo repository.VehicleRepository, vehicleId int) (*entity.Vehicle, error) {
+	if vehicleId <= 0 {
+		return nil, entity.NewErrorInvalidField("id", entity.CodeInvalid, vehicleId, i18n.VehicleIdInvalid)
+	}
+	vehicle, err := vRepo.GetDeletedById(vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	if vehicle != nil {
+		return vehicle, nil
+	}
+	_, err = getVehicle(vRepo, vehicleId)
+	if err != nil {
+		return nil, err
+	}
+	return nil, ErrRecordNotDeleted
+}
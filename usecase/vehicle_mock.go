@@ -34,33 +34,77 @@ func (m *MockVehicleUsecase) EXPECT() *MockVehicleUsecaseMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockVehicleUsecase) Create(vehicle *entity.Vehicle, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", vehicle, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVehicleUsecaseMockRecorder) Create(vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVehicleUsecase)(nil).Create), vehicle, actor)
+}
+
 // Delete mocks base method.
-func (m *MockVehicleUsecase) Delete(vehicleId int) error {
+func (m *MockVehicleUsecase) Delete(vehicleId int, version uint, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", vehicleId)
+	ret := m.ctrl.Call(m, "Delete", vehicleId, version, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockVehicleUsecaseMockRecorder) Delete(vehicleId interface{}) *gomock.Call {
+func (mr *MockVehicleUsecaseMockRecorder) Delete(vehicleId, version, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleUsecase)(nil).Delete), vehicleId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVehicleUsecase)(nil).Delete), vehicleId, version, actor)
+}
+
+// Export mocks base method.
+func (m *MockVehicleUsecase) Export(opts *entity.QueryOptions, write func([]*entity.Vehicle) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", opts, write)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockVehicleUsecaseMockRecorder) Export(opts, write interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockVehicleUsecase)(nil).Export), opts, write)
 }
 
 // GetAll mocks base method.
-func (m *MockVehicleUsecase) GetAll() ([]*entity.Vehicle, error) {
+func (m *MockVehicleUsecase) GetAll(opts *entity.QueryOptions) ([]*entity.Vehicle, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", opts)
 	ret0, _ := ret[0].([]*entity.Vehicle)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockVehicleUsecaseMockRecorder) GetAll(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVehicleUsecase)(nil).GetAll), opts)
+}
+
+// GetByExternalId mocks base method.
+func (m *MockVehicleUsecase) GetByExternalId(source, externalId string) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalId", source, externalId)
+	ret0, _ := ret[0].(*entity.Vehicle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockVehicleUsecaseMockRecorder) GetAll() *gomock.Call {
+// GetByExternalId indicates an expected call of GetByExternalId.
+func (mr *MockVehicleUsecaseMockRecorder) GetByExternalId(source, externalId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockVehicleUsecase)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalId", reflect.TypeOf((*MockVehicleUsecase)(nil).GetByExternalId), source, externalId)
 }
 
 // GetById mocks base method.
@@ -78,16 +122,90 @@ func (mr *MockVehicleUsecaseMockRecorder) GetById(vehicleId interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockVehicleUsecase)(nil).GetById), vehicleId)
 }
 
+// ImportVehicles mocks base method.
+func (m *MockVehicleUsecase) ImportVehicles(rows []entity.VehicleImportRow, opts *entity.ImportOptions, actor entity.Actor) (*entity.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportVehicles", rows, opts, actor)
+	ret0, _ := ret[0].(*entity.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportVehicles indicates an expected call of ImportVehicles.
+func (mr *MockVehicleUsecaseMockRecorder) ImportVehicles(rows, opts, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportVehicles", reflect.TypeOf((*MockVehicleUsecase)(nil).ImportVehicles), rows, opts, actor)
+}
+
+// Purge mocks base method.
+func (m *MockVehicleUsecase) Purge(vehicleId int, actor entity.Actor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", vehicleId, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockVehicleUsecaseMockRecorder) Purge(vehicleId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockVehicleUsecase)(nil).Purge), vehicleId, actor)
+}
+
+// Replace mocks base method.
+func (m *MockVehicleUsecase) Replace(vehicleId int, vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", vehicleId, vehicle, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockVehicleUsecaseMockRecorder) Replace(vehicleId, vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockVehicleUsecase)(nil).Replace), vehicleId, vehicle, actor)
+}
+
+// ReplaceByExternalId mocks base method.
+func (m *MockVehicleUsecase) ReplaceByExternalId(source, externalId string, vehicle *entity.Vehicle, actor entity.Actor) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceByExternalId", source, externalId, vehicle, actor)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceByExternalId indicates an expected call of ReplaceByExternalId.
+func (mr *MockVehicleUsecaseMockRecorder) ReplaceByExternalId(source, externalId, vehicle, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceByExternalId", reflect.TypeOf((*MockVehicleUsecase)(nil).ReplaceByExternalId), source, externalId, vehicle, actor)
+}
+
+// Restore mocks base method.
+func (m *MockVehicleUsecase) Restore(vehicleId int, actor entity.Actor) (*entity.Vehicle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", vehicleId, actor)
+	ret0, _ := ret[0].(*entity.Vehicle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockVehicleUsecaseMockRecorder) Restore(vehicleId, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockVehicleUsecase)(nil).Restore), vehicleId, actor)
+}
+
 // Update mocks base method.
-func (m *MockVehicleUsecase) Update(vehicleId int, updateVehicle *entity.Vehicle) error {
+func (m *MockVehicleUsecase) Update(vehicleId int, patch *entity.Patch, actor entity.Actor) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", vehicleId, updateVehicle)
+	ret := m.ctrl.Call(m, "Update", vehicleId, patch, actor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockVehicleUsecaseMockRecorder) Update(vehicleId, updateVehicle interface{}) *gomock.Call {
+func (mr *MockVehicleUsecaseMockRecorder) Update(vehicleId, patch, actor interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleUsecase)(nil).Update), vehicleId, updateVehicle)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVehicleUsecase)(nil).Update), vehicleId, patch, actor)
 }
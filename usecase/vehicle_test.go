@@ -6,46 +6,83 @@ import (
 	"testing"
 
 	"github.com/lucas-moura1/gobrax-challenge/entity"
+	"github.com/lucas-moura1/gobrax-challenge/i18n"
 	"github.com/lucas-moura1/gobrax-challenge/repository"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func Test_vehicleUsecase_GetAll(t *testing.T) {
+	driverId := uint(1)
 	tests := []struct {
-		name    string
-		setup   func(mockVehicleRepo *repository.MockVehicleRepository)
-		want    []*entity.Vehicle
-		wantErr bool
+		name      string
+		opts      *entity.QueryOptions
+		setup     func(mockVehicleRepo *repository.MockVehicleRepository)
+		want      []*entity.Vehicle
+		wantTotal int64
+		wantErr   bool
 	}{
 		{
 			name: "Should return all vehicles",
 			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
-				mockVehicleRepo.EXPECT().GetAll().Return([]*entity.Vehicle{
+				mockVehicleRepo.EXPECT().GetAll(gomock.Any()).Return([]*entity.Vehicle{
 					{
 						Brand:        "Toyota",
 						VehicleModel: "Camry",
 						Year:         2022,
+						Class:        "car",
 						Plate:        "ABC-1234",
-						DriverID:     1,
+						DriverID:     &driverId,
 					},
-				}, nil)
+				}, int64(1), nil)
 			},
 			want: []*entity.Vehicle{
 				{
 					Brand:        "Toyota",
 					VehicleModel: "Camry",
 					Year:         2022,
+					Class:        "car",
 					Plate:        "ABC-1234",
-					DriverID:     1,
+					DriverID:     &driverId,
 				},
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
+		},
+		{
+			name: "Should pass query options to repository",
+			opts: &entity.QueryOptions{
+				Page:     2,
+				PageSize: 10,
+				Sort:     []entity.Sort{{Field: "createdAt", Desc: true}},
+			},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetAll(&entity.QueryOptions{
+					Page:     2,
+					PageSize: 10,
+					Sort:     []entity.Sort{{Field: "createdAt", Desc: true}},
+				}).Return(nil, int64(11), nil)
+			},
+			want:      nil,
+			wantTotal: 11,
+			wantErr:   false,
+		},
+		{
+			name: "Should return error for invalid query options",
+			opts: &entity.QueryOptions{
+				Page:     0,
+				PageSize: 1000,
+				Filters:  []entity.Filter{{Field: "unknown", Operator: entity.FilterOperatorEqual, Value: "x"}},
+			},
+			setup:   func(mockVehicleRepo *repository.MockVehicleRepository) {},
+			want:    nil,
+			wantErr: true,
 		},
 		{
 			name: "Should return error",
 			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
-				mockVehicleRepo.EXPECT().GetAll().Return(nil, fmt.Errorf("some error occurred"))
+				mockVehicleRepo.EXPECT().GetAll(gomock.Any()).Return(nil, int64(0), fmt.Errorf("some error occurred"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -58,18 +95,161 @@ func Test_vehicleUsecase_GetAll(t *testing.T) {
 
 			tt.setup(mockVehicleRepo)
 
-			vu := NewVehicleUsecase(mockVehicleRepo)
-			got, err := vu.GetAll()
+			vu := NewVehicleUsecase(mockVehicleRepo, repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, total, err := vu.GetAll(tt.opts)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func Test_vehicleUsecase_Export(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *entity.QueryOptions
+		setup   func(mockVehicleRepo *repository.MockVehicleRepository)
+		wantErr error
+	}{
+		{
+			name: "Should export the vehicles with the plate filter normalized",
+			opts: &entity.QueryOptions{
+				Page:     1,
+				PageSize: entity.DefaultPageSize,
+				Filters:  []entity.Filter{{Field: "plate", Operator: entity.FilterOperatorEqual, Value: "abc-1d23"}},
+			},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().Export(&entity.QueryOptions{
+					Page:     1,
+					PageSize: entity.DefaultPageSize,
+					Filters:  []entity.Filter{{Field: "plate", Operator: entity.FilterOperatorEqual, Value: "ABC1D23"}},
+				}, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Should export all vehicles without query options",
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().Export(entity.NewQueryOptions(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Should return error when the export is sorted",
+			opts: &entity.QueryOptions{
+				Page:     1,
+				PageSize: entity.DefaultPageSize,
+				Sort:     []entity.Sort{{Field: "year"}},
+			},
+			setup:   func(mockVehicleRepo *repository.MockVehicleRepository) {},
+			wantErr: entity.NewErrorInvalidField("sort", entity.CodeNotAllowed, nil, i18n.ExportSortNotAllowed),
+		},
+		{
+			name: "Should return error",
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().Export(gomock.Any(), gomock.Any()).Return(errors.New("some error occurred"))
+			},
+			wantErr: errors.New("some error occurred"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+
+			tt.setup(mockVehicleRepo)
+
+			vu := NewVehicleUsecase(mockVehicleRepo, repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			err := vu.Export(tt.opts, func([]*entity.Vehicle) error { return nil })
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_vehicleUsecase_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		vehicle *entity.Vehicle
+		setup   func(mockVehicleRepo *repository.MockVehicleRepository)
+		want    error
+		wantErr bool
+	}{
+		{
+			name: "Should create vehicle without driver",
+			vehicle: &entity.Vehicle{
+				Brand:        "Volvo",
+				VehicleModel: "FH 540",
+				Year:         2021,
+				Class:        "heavy_truck",
+				Plate:        "abc-1d23",
+			},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByPlate("ABC1D23").Return(nil, nil)
+				mockVehicleRepo.EXPECT().Create(gomock.Any(), testActor).DoAndReturn(func(vehicle *entity.Vehicle, _ entity.Actor) error {
+					assert.Nil(t, vehicle.DriverID)
+					assert.Equal(t, "ABC1D23", vehicle.Plate)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Should return error when vehicle is nil",
+			vehicle: nil,
+			setup:   func(mockVehicleRepo *repository.MockVehicleRepository) {},
+			want:    entity.NewErrorInvalidField("body", entity.CodeRequired, nil, i18n.VehicleRequired),
+			wantErr: true,
+		},
+		{
+			name: "Should return error when vehicle is invalid",
+			vehicle: &entity.Vehicle{
+				Brand:        "Volvo",
+				VehicleModel: "FH 540",
+				Year:         2021,
+				Class:        "spaceship",
+				Plate:        "ABC1D23",
+			},
+			setup:   func(mockVehicleRepo *repository.MockVehicleRepository) {},
+			want:    entity.NewErrorInvalidField("class", entity.CodeInvalid, "spaceship", i18n.VehicleClassInvalid),
+			wantErr: true,
+		},
+		{
+			name: "Should return conflict error when plate already exists",
+			vehicle: &entity.Vehicle{
+				Brand:        "Volvo",
+				VehicleModel: "FH 540",
+				Year:         2021,
+				Class:        "heavy_truck",
+				Plate:        "ABC1D23",
+			},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByPlate("ABC1D23").Return(&entity.Vehicle{Model: gorm.Model{ID: 9}}, nil)
+			},
+			want:    &entity.ErrorConflict{Entity: "vehicle", Field: "plate"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockVehicleRepo)
+
+			vu := NewVehicleUsecase(mockVehicleRepo, repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+
+			err := vu.Create(tt.vehicle, testActor)
+			if tt.wantErr {
+				assert.Equal(t, tt.want, err)
+				return
+			}
+			assert.Nil(t, err)
 		})
 	}
 }
 
 func Test_vehicleUsecase_GetById(t *testing.T) {
+	driverId := uint(1)
 	tests := []struct {
 		name      string
 		vehicleId int
@@ -85,16 +265,18 @@ func Test_vehicleUsecase_GetById(t *testing.T) {
 					Brand:        "Toyota",
 					VehicleModel: "Camry",
 					Year:         2022,
+					Class:        "car",
 					Plate:        "ABC-1234",
-					DriverID:     1,
+					DriverID:     &driverId,
 				}, nil)
 			},
 			want: &entity.Vehicle{
 				Brand:        "Toyota",
 				VehicleModel: "Camry",
 				Year:         2022,
+				Class:        "car",
 				Plate:        "ABC-1234",
-				DriverID:     1,
+				DriverID:     &driverId,
 			},
 			wantErr: false,
 		},
@@ -133,7 +315,7 @@ func Test_vehicleUsecase_GetById(t *testing.T) {
 
 			tt.setup(mockVehicleRepo)
 
-			vu := NewVehicleUsecase(mockVehicleRepo)
+			vu := NewVehicleUsecase(mockVehicleRepo, repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
 			got, err := vu.GetById(tt.vehicleId)
 
 			if tt.wantErr {
@@ -146,56 +328,119 @@ func Test_vehicleUsecase_GetById(t *testing.T) {
 }
 
 func Test_vehicleUsecase_Update(t *testing.T) {
-	mockUpdatedVehicle := &entity.Vehicle{
-		Brand:        "Toyota",
-		VehicleModel: "Camry",
-		Year:         2023,
-		Plate:        "DEF-5678",
-	}
+	driverId := uint(1)
+	mockPatch := mergePatch(`{"brand":"Toyota","vehicleModel":"Camry","year":2023,"class":"car","plate":"DEF-5678"}`)
 	tests := []struct {
-		name          string
-		vehicleId     int
-		updateVehicle *entity.Vehicle
-		setup         func(mockVehicleRepo *repository.MockVehicleRepository)
-		wantErr       bool
+		name      string
+		vehicleId int
+		patch     *entity.Patch
+		setup     func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository)
+		wantErr   bool
 	}{
 		{
-			name:          "Should update vehicle successfully",
-			vehicleId:     1,
-			updateVehicle: mockUpdatedVehicle,
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			name:      "Should update vehicle successfully",
+			vehicleId: 1,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
 				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
 					Brand:        "Toyotta",
 					VehicleModel: "Canry",
 					Year:         2022,
+					Class:        "car",
 					Plate:        "ABC-1234",
 				}, nil)
-				mockVehicleRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockVehicleRepo.EXPECT().GetByPlate("DEF5678").Return(nil, nil)
+				mockVehicleRepo.EXPECT().Update(gomock.Any(), testActor).Return(nil)
 			},
 			wantErr: false,
 		},
 		{
-			name:          "Should return error for invalid vehicle ID",
-			vehicleId:     0,
-			updateVehicle: mockUpdatedVehicle,
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			name:      "Should return conflict error when plate belongs to another vehicle",
+			vehicleId: 1,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Model:        gorm.Model{ID: 1},
+					Brand:        "Toyotta",
+					VehicleModel: "Canry",
+					Year:         2022,
+					Class:        "car",
+					Plate:        "ABC-1234",
+				}, nil)
+				mockVehicleRepo.EXPECT().GetByPlate("DEF5678").Return(&entity.Vehicle{Model: gorm.Model{ID: 2}}, nil)
 			},
 			wantErr: true,
 		},
 		{
-			name:          "Should return error to get vehicle by ID",
-			vehicleId:     1,
-			updateVehicle: mockUpdatedVehicle,
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			name:      "Should return error when driver license does not allow the new class",
+			vehicleId: 1,
+			patch:     mergePatch(`{"class":"heavy_truck"}`),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Brand:        "Toyota",
+					VehicleModel: "Camry",
+					Year:         2022,
+					Class:        entity.VehicleClassCar,
+					Plate:        "ABC1234",
+					DriverID:     &driverId,
+				}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{LicenseType: entity.LicenseTypeB}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should update vehicle without class when the patch does not set one",
+			vehicleId: 1,
+			patch:     mergePatch(`{"brand":"Volvo"}`),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Brand:        "Toyota",
+					VehicleModel: "Camry",
+					Year:         2022,
+					Plate:        "ABC1234",
+					DriverID:     &driverId,
+				}, nil)
+				mockVehicleRepo.EXPECT().Update(gomock.Any(), testActor).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:      "Should return error when the patch removes the vehicle class",
+			vehicleId: 1,
+			patch:     mergePatch(`{"class":""}`),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Brand:        "Toyota",
+					VehicleModel: "Camry",
+					Year:         2022,
+					Class:        entity.VehicleClassCar,
+					Plate:        "ABC1234",
+				}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error for invalid vehicle ID",
+			vehicleId: 0,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error to get vehicle by ID",
+			vehicleId: 1,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
 				mockVehicleRepo.EXPECT().GetById(1).Return(nil, fmt.Errorf("some error occurred"))
 			},
 			wantErr: true,
 		},
 		{
-			name:          "Should return error when vehicle is not found",
-			vehicleId:     2,
-			updateVehicle: mockUpdatedVehicle,
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			name:      "Should return error when vehicle is not found",
+			vehicleId: 2,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
 				mockVehicleRepo.EXPECT().GetById(2).Return(nil, nil)
 			},
 			wantErr: true,
@@ -203,29 +448,35 @@ func Test_vehicleUsecase_Update(t *testing.T) {
 		{
 			name:      "Should return error for some invalid field",
 			vehicleId: 1,
-			updateVehicle: &entity.Vehicle{
-				Brand:        "T",
-				VehicleModel: "Camry",
-				Year:         2023,
-				Plate:        "DEF-5678",
-			},
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			patch:     mergePatch(`{"brand":"T"}`),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
 				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
 					Brand:        "Toyotta",
 					VehicleModel: "Canry",
 					Year:         2022,
+					Class:        "car",
 					Plate:        "ABC-1234",
 				}, nil)
 			},
 			wantErr: true,
 		},
 		{
-			name:          "Should return error",
-			vehicleId:     3,
-			updateVehicle: mockUpdatedVehicle,
-			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+			name:      "Should return error when vehicle version does not match",
+			vehicleId: 1,
+			patch:     &entity.Patch{Type: entity.PatchTypeMerge, Document: []byte(`{"brand":"Volvo"}`), Version: 2},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{Brand: "Toyota", Version: 3}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error",
+			vehicleId: 3,
+			patch:     mockPatch,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
 				mockVehicleRepo.EXPECT().GetById(3).Return(new(entity.Vehicle), nil)
-				mockVehicleRepo.EXPECT().Update(gomock.Any()).Return(errors.New("some error occurred"))
+				mockVehicleRepo.EXPECT().GetByPlate("DEF5678").Return(nil, nil)
+				mockVehicleRepo.EXPECT().Update(gomock.Any(), testActor).Return(errors.New("some error occurred"))
 			},
 			wantErr: true,
 		},
@@ -235,11 +486,12 @@ func Test_vehicleUsecase_Update(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
 
-			tt.setup(mockVehicleRepo)
+			tt.setup(mockVehicleRepo, mockDriverRepo)
 
-			vu := NewVehicleUsecase(mockVehicleRepo)
-			err := vu.Update(tt.vehicleId, tt.updateVehicle)
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			err := vu.Update(tt.vehicleId, tt.patch, testActor)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -250,6 +502,221 @@ func Test_vehicleUsecase_Update(t *testing.T) {
 	}
 }
 
+func Test_vehicleUsecase_Replace(t *testing.T) {
+	driverId := uint(1)
+	newVehicle := func() *entity.Vehicle {
+		return &entity.Vehicle{
+			Brand:        "Toyota",
+			VehicleModel: "Camry",
+			Year:         2023,
+			Class:        entity.VehicleClassCar,
+			Plate:        "DEF-5678",
+		}
+	}
+	tests := []struct {
+		name        string
+		vehicleId   int
+		vehicle     *entity.Vehicle
+		setup       func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository)
+		want        error
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name:      "Should return vehicle not found error when the vehicle does not exist",
+			vehicleId: 10,
+			vehicle:   newVehicle(),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(10).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetDeletedById(10).Return(nil, nil)
+			},
+			want:    ErrVehicleNotFound,
+			wantErr: true,
+		},
+		{
+			name:      "Should return error when the id belongs to a deleted vehicle",
+			vehicleId: 10,
+			vehicle:   newVehicle(),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(10).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetDeletedById(10).Return(&entity.Vehicle{Model: gorm.Model{ID: 10}}, nil)
+			},
+			want:    ErrRecordDeleted,
+			wantErr: true,
+		},
+		{
+			name:      "Should replace an existing vehicle keeping its driver",
+			vehicleId: 1,
+			vehicle:   newVehicle(),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Model:    gorm.Model{ID: 1},
+					Brand:    "Fiat",
+					Class:    entity.VehicleClassCar,
+					Plate:    "DEF5678",
+					DriverID: &driverId,
+					Version:  4,
+				}, nil)
+				mockVehicleRepo.EXPECT().Replace(gomock.Any(), testActor).DoAndReturn(func(vehicle *entity.Vehicle, _ entity.Actor) (bool, error) {
+					assert.Equal(t, &driverId, vehicle.DriverID)
+					assert.Equal(t, uint(4), vehicle.Version)
+					return false, nil
+				})
+			},
+			wantCreated: false,
+			wantErr:     false,
+		},
+		{
+			name:      "Should return error when driver license does not allow the new class",
+			vehicleId: 1,
+			vehicle:   &entity.Vehicle{Brand: "Volvo", VehicleModel: "FH540", Year: 2023, Class: entity.VehicleClassHeavyTruck, Plate: "DEF5678"},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{
+					Class:    entity.VehicleClassCar,
+					Plate:    "DEF5678",
+					DriverID: &driverId,
+				}, nil)
+				mockDriverRepo.EXPECT().GetById(1, false).Return(&entity.Driver{LicenseType: entity.LicenseTypeB}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error when vehicle version does not match",
+			vehicleId: 1,
+			vehicle:   &entity.Vehicle{Brand: "Volvo", Version: 2},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{Brand: "Toyota", Version: 3}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error for some invalid field",
+			vehicleId: 1,
+			vehicle:   &entity.Vehicle{Brand: "T"},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{Model: gorm.Model{ID: 1}, Brand: "Toyota"}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error for invalid vehicle ID",
+			vehicleId: -1,
+			vehicle:   newVehicle(),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return error to get vehicle by ID",
+			vehicleId: 1,
+			vehicle:   newVehicle(),
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository, mockDriverRepo *repository.MockDriverRepository) {
+				mockVehicleRepo.EXPECT().GetById(1).Return(nil, errors.New("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+
+			tt.setup(mockVehicleRepo, mockDriverRepo)
+
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			created, err := vu.Replace(tt.vehicleId, tt.vehicle, testActor)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.want != nil {
+					assert.Equal(t, tt.want, err)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
+func Test_vehicleUsecase_ReplaceByExternalId(t *testing.T) {
+	newVehicle := func() *entity.Vehicle {
+		return &entity.Vehicle{
+			Brand:        "Volvo",
+			VehicleModel: "FH 540",
+			Year:         2021,
+			Class:        entity.VehicleClassHeavyTruck,
+			Plate:        "ABC1D23",
+		}
+	}
+	tests := []struct {
+		name        string
+		setup       func(mockVehicleRepo *repository.MockVehicleRepository)
+		wantCreated bool
+		wantErr     bool
+	}{
+		{
+			name: "Should create the vehicle with the external id when it does not exist",
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByExternalId("erp", "TRK-0042").Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetByPlate("ABC1D23").Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetByExternalId("erp", "TRK-0042").Return(nil, nil)
+				mockVehicleRepo.EXPECT().Replace(gomock.Any(), testActor).DoAndReturn(func(vehicle *entity.Vehicle, _ entity.Actor) (bool, error) {
+					assert.Equal(t, uint(0), vehicle.ID)
+					assert.Equal(t, "erp", *vehicle.ExternalSource)
+					assert.Equal(t, "TRK-0042", *vehicle.ExternalID)
+					return true, nil
+				})
+			},
+			wantCreated: true,
+		},
+		{
+			name: "Should return conflict error when the plate belongs to another vehicle",
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByExternalId("erp", "TRK-0042").Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetByPlate("ABC1D23").Return(&entity.Vehicle{Model: gorm.Model{ID: 3}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockVehicleRepo)
+
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			created, err := vu.ReplaceByExternalId("erp", "TRK-0042", newVehicle(), testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
+func Test_checkExternalId(t *testing.T) {
+	source, externalId := "erp", "TRK-0042"
+	ctrl := gomock.NewController(t)
+	mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+	mockVehicleRepo.EXPECT().GetByExternalId("erp", "TRK-0042").Return(&entity.Vehicle{Model: gorm.Model{ID: 2}}, nil).Times(2)
+
+	err := checkExternalId(mockVehicleRepo, &entity.Vehicle{Model: gorm.Model{ID: 1}, ExternalSource: &source, ExternalID: &externalId})
+	assert.Equal(t, &entity.ErrorConflict{Entity: "vehicle", Field: "externalId"}, err)
+
+	err = checkExternalId(mockVehicleRepo, &entity.Vehicle{Model: gorm.Model{ID: 2}, ExternalSource: &source, ExternalID: &externalId})
+	assert.Nil(t, err)
+
+	err = checkExternalId(mockVehicleRepo, &entity.Vehicle{Model: gorm.Model{ID: 1}})
+	assert.Nil(t, err)
+}
+
 func Test_vehicleUsecase_Delete(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -261,7 +728,8 @@ func Test_vehicleUsecase_Delete(t *testing.T) {
 			name:      "Should delete vehicle",
 			vehicleId: 1,
 			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
-				mockVehicleRepo.EXPECT().Delete(1).Return(nil)
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{Model: gorm.Model{ID: 1}}, nil)
+				mockVehicleRepo.EXPECT().Delete(1, uint(0), testActor).Return(nil)
 			},
 			wantErr: false,
 		},
@@ -276,7 +744,16 @@ func Test_vehicleUsecase_Delete(t *testing.T) {
 			name:      "Should return error",
 			vehicleId: 3,
 			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
-				mockVehicleRepo.EXPECT().Delete(3).Return(errors.New("some error occurred"))
+				mockVehicleRepo.EXPECT().GetById(3).Return(&entity.Vehicle{Model: gorm.Model{ID: 3}}, nil)
+				mockVehicleRepo.EXPECT().Delete(3, uint(0), testActor).Return(errors.New("some error occurred"))
+			},
+			wantErr: true,
+		},
+		{
+			name:      "Should return not found when the vehicle does not exist",
+			vehicleId: 4,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetById(4).Return(nil, nil)
 			},
 			wantErr: true,
 		},
@@ -289,8 +766,9 @@ func Test_vehicleUsecase_Delete(t *testing.T) {
 
 			tt.setup(mockVehicleRepo)
 
-			vu := NewVehicleUsecase(mockVehicleRepo)
-			err := vu.Delete(tt.vehicleId)
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			err := vu.Delete(tt.vehicleId, 0, testActor)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -300,3 +778,219 @@ func Test_vehicleUsecase_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_vehicleUsecase_ImportVehicles(t *testing.T) {
+	driverId := uint(1)
+	newRows := func() []entity.VehicleImportRow {
+		return []entity.VehicleImportRow{
+			{Line: 2, Vehicle: &entity.Vehicle{Brand: "Volvo", VehicleModel: "FH 540", Year: 2021, Class: "heavy_truck", Plate: "abc-1d23", DriverID: &driverId}},
+			{Line: 3, Vehicle: &entity.Vehicle{Brand: "Scania", VehicleModel: "R 450", Year: 2020, Class: "heavy_truck", Plate: "DEF4G56"}},
+		}
+	}
+	noConflicts := func(mockVehicleRepo *repository.MockVehicleRepository) {
+		mockVehicleRepo.EXPECT().GetByPlate(gomock.Any()).Return(nil, nil).AnyTimes()
+	}
+
+	tests := []struct {
+		name    string
+		rows    func() []entity.VehicleImportRow
+		opts    *entity.ImportOptions
+		setup   func(mockVehicleRepo *repository.MockVehicleRepository)
+		want    *entity.ImportReport
+		wantErr bool
+	}{
+		{
+			name: "Should create every vehicle without driver in a single batch",
+			rows: newRows,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				noConflicts(mockVehicleRepo)
+				mockVehicleRepo.EXPECT().CreateBatch(gomock.Len(2), testActor).DoAndReturn(func(vehicles []*entity.Vehicle, _ entity.Actor) error {
+					assert.Nil(t, vehicles[0].DriverID)
+					assert.Equal(t, "ABC1D23", vehicles[0].Plate)
+					return nil
+				})
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, Total: 2, Valid: 2, Imported: 2},
+		},
+		{
+			name: "Should create no vehicle when a plate is repeated",
+			rows: func() []entity.VehicleImportRow {
+				rows := newRows()
+				rows[1].Vehicle.Plate = "ABC1D23"
+				return rows
+			},
+			setup: noConflicts,
+			want: &entity.ImportReport{Mode: entity.ImportModeAllOrNothing, Total: 2, Valid: 1, Errors: []entity.ImportRowError{
+				{Line: 3, Err: entity.NewErrorInvalidField("plate", entity.CodeDuplicate, "ABC1D23", i18n.ImportDuplicate, "plate", 2)},
+			}},
+		},
+		{
+			name: "Should create the valid vehicles on a best effort import",
+			rows: func() []entity.VehicleImportRow {
+				rows := newRows()
+				rows[0].Vehicle.Class = "spaceship"
+				return rows
+			},
+			opts: &entity.ImportOptions{Mode: entity.ImportModeBestEffort},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				noConflicts(mockVehicleRepo)
+				mockVehicleRepo.EXPECT().Create(gomock.Any(), testActor).Return(nil)
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeBestEffort, Total: 2, Valid: 1, Imported: 1, Errors: []entity.ImportRowError{
+				{Line: 2, Err: entity.NewErrorInvalidField("class", entity.CodeInvalid, "spaceship", i18n.VehicleClassInvalid)},
+			}},
+		},
+		{
+			name: "Should report a vehicle created concurrently on a best effort import",
+			rows: newRows,
+			opts: &entity.ImportOptions{Mode: entity.ImportModeBestEffort},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				noConflicts(mockVehicleRepo)
+				gomock.InOrder(
+					mockVehicleRepo.EXPECT().Create(gomock.Any(), testActor).Return(&entity.ErrorConflict{Entity: "vehicle", Field: "plate"}),
+					mockVehicleRepo.EXPECT().Create(gomock.Any(), testActor).Return(nil),
+				)
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeBestEffort, Total: 2, Valid: 2, Imported: 1, Errors: []entity.ImportRowError{
+				{Line: 2, Err: &entity.ErrorConflict{Entity: "vehicle", Field: "plate"}},
+			}},
+		},
+		{
+			name: "Should only validate the vehicles on a dry run",
+			rows: newRows,
+			opts: &entity.ImportOptions{Mode: entity.ImportModeBestEffort, DryRun: true},
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByPlate("ABC1D23").Return(&entity.Vehicle{Model: gorm.Model{ID: 9}}, nil)
+				noConflicts(mockVehicleRepo)
+			},
+			want: &entity.ImportReport{Mode: entity.ImportModeBestEffort, DryRun: true, Total: 2, Valid: 1, Errors: []entity.ImportRowError{
+				{Line: 2, Err: &entity.ErrorConflict{Entity: "vehicle", Field: "plate"}},
+			}},
+		},
+		{
+			name: "Should return error when the database fails",
+			rows: newRows,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetByPlate(gomock.Any()).Return(nil, errors.New("some error occurred"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockVehicleRepo)
+
+			vu := NewVehicleUsecase(mockVehicleRepo, repository.NewMockDriverRepository(ctrl), repository.NewMockUnitOfWork(ctrl))
+			got, err := vu.ImportVehicles(tt.rows(), tt.opts, testActor)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_vehicleUsecase_Restore(t *testing.T) {
+	deleted := &entity.Vehicle{Model: gorm.Model{ID: 1}, Plate: "ABC1D23", Version: 2}
+	tests := []struct {
+		name      string
+		vehicleId int
+		setup     func(mockVehicleRepo *repository.MockVehicleRepository)
+		want      *entity.Vehicle
+		wantErr   error
+	}{
+		{
+			name:      "Should restore the deleted vehicle",
+			vehicleId: 1,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetDeletedById(1).Return(deleted, nil)
+				mockVehicleRepo.EXPECT().Restore(deleted, testActor).Return(nil)
+			},
+			want: deleted,
+		},
+		{
+			name:      "Should return conflict when the vehicle is not deleted",
+			vehicleId: 1,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetById(1).Return(&entity.Vehicle{Model: gorm.Model{ID: 1}}, nil)
+			},
+			wantErr: ErrRecordNotDeleted,
+		},
+		{
+			name:      "Should return not found when the vehicle never existed",
+			vehicleId: 1,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetById(1).Return(nil, nil)
+			},
+			wantErr: ErrVehicleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockVehicleRepo)
+
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			got, err := vu.Restore(tt.vehicleId, testActor)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_vehicleUsecase_Purge(t *testing.T) {
+	admin := entity.NewActor("root", entity.RoleAdmin)
+	tests := []struct {
+		name    string
+		actor   entity.Actor
+		setup   func(mockVehicleRepo *repository.MockVehicleRepository)
+		wantErr error
+	}{
+		{
+			name:  "Should purge the deleted vehicle",
+			actor: admin,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetDeletedById(1).Return(&entity.Vehicle{Model: gorm.Model{ID: 1}}, nil)
+				mockVehicleRepo.EXPECT().Purge(1, admin).Return(nil)
+			},
+		},
+		{
+			name:    "Should refuse to purge when the actor is not an admin",
+			actor:   testActor,
+			setup:   func(mockVehicleRepo *repository.MockVehicleRepository) {},
+			wantErr: ErrPurgeNotAllowed,
+		},
+		{
+			name:  "Should return not found when the vehicle never existed",
+			actor: admin,
+			setup: func(mockVehicleRepo *repository.MockVehicleRepository) {
+				mockVehicleRepo.EXPECT().GetDeletedById(1).Return(nil, nil)
+				mockVehicleRepo.EXPECT().GetById(1).Return(nil, nil)
+			},
+			wantErr: ErrVehicleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVehicleRepo := repository.NewMockVehicleRepository(ctrl)
+			tt.setup(mockVehicleRepo)
+
+			mockDriverRepo := repository.NewMockDriverRepository(ctrl)
+			vu := NewVehicleUsecase(mockVehicleRepo, mockDriverRepo, newUnitOfWork(ctrl, repository.Repositories{Drivers: mockDriverRepo, Vehicles: mockVehicleRepo}))
+			err := vu.Purge(1, tt.actor)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}